@@ -0,0 +1,121 @@
+package bytesizer
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// AccountingEntry is a key of an AccountingMap and its count.
+type AccountingEntry[K comparable] struct {
+	Key  K
+	Size ByteSize
+}
+
+// AccountingMap counts bytes per key, such as per tenant or per endpoint,
+// and in total. It is safe for concurrent use, and adding to an existing
+// key only takes a read lock. The zero value is ready to use.
+//
+//	var egress AccountingMap[string]
+//	egress.Add(tenant, ByteSize(n))
+//	for _, e := range egress.TopN(10) {
+//		log.Printf("%s: %s", e.Key, e.Size)
+//	}
+type AccountingMap[K comparable] struct {
+	mu    sync.RWMutex
+	m     map[K]*atomic.Int64
+	total atomic.Int64
+}
+
+// Add adds n bytes to key, which may be negative, and returns its new count.
+func (a *AccountingMap[K]) Add(key K, n ByteSize) ByteSize {
+	a.mu.RLock()
+	c, ok := a.m[key]
+	if ok {
+		defer a.mu.RUnlock()
+		a.total.Add(int64(n))
+		return ByteSize(c.Add(int64(n)))
+	}
+	a.mu.RUnlock()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.m == nil {
+		a.m = make(map[K]*atomic.Int64)
+	}
+	if c, ok = a.m[key]; !ok {
+		c = new(atomic.Int64)
+		a.m[key] = c
+	}
+	a.total.Add(int64(n))
+	return ByteSize(c.Add(int64(n)))
+}
+
+// Load returns the count of key, 0 if it has none.
+func (a *AccountingMap[K]) Load(key K) ByteSize {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if c, ok := a.m[key]; ok {
+		return ByteSize(c.Load())
+	}
+	return 0
+}
+
+// Delete removes key, subtracting its count from the total, and returns
+// the count it had.
+func (a *AccountingMap[K]) Delete(key K) ByteSize {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	c, ok := a.m[key]
+	if !ok {
+		return 0
+	}
+	delete(a.m, key)
+	n := c.Load()
+	a.total.Add(-n)
+	return ByteSize(n)
+}
+
+// Total returns the sum of the counts of all keys.
+func (a *AccountingMap[K]) Total() ByteSize {
+	return ByteSize(a.total.Load())
+}
+
+// Len returns the number of keys.
+func (a *AccountingMap[K]) Len() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return len(a.m)
+}
+
+// Snapshot returns a copy of the counts. Counts of keys being added to
+// concurrently are read one at a time.
+func (a *AccountingMap[K]) Snapshot() map[K]ByteSize {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	s := make(map[K]ByteSize, len(a.m))
+	for k, c := range a.m {
+		s[k] = ByteSize(c.Load())
+	}
+	return s
+}
+
+// TopN returns the n keys with the largest counts, largest first. The
+// order of keys with equal counts is unspecified.
+func (a *AccountingMap[K]) TopN(n int) []AccountingEntry[K] {
+	s := a.Snapshot()
+	entries := make([]AccountingEntry[K], 0, len(s))
+	for k, v := range s {
+		entries = append(entries, AccountingEntry[K]{Key: k, Size: v})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Size > entries[j].Size
+	})
+	if n < 0 {
+		n = 0
+	}
+	if n < len(entries) {
+		entries = entries[:n]
+	}
+	return entries
+}
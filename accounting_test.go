@@ -0,0 +1,56 @@
+package bytesizer
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountingMap(t *testing.T) {
+	var a AccountingMap[string]
+	assert.Equal(t, ByteSize(0), a.Load("missing"))
+	assert.Empty(t, a.TopN(3))
+
+	assert.Equal(t, KB, a.Add("alice", KB))
+	assert.Equal(t, 3*KB, a.Add("alice", 2*KB))
+	a.Add("bob", 5*KB)
+	a.Add("carol", MB)
+	a.Add("bob", -KB)
+
+	assert.Equal(t, 3*KB, a.Load("alice"))
+	assert.Equal(t, 4*KB, a.Load("bob"))
+	assert.Equal(t, MB+7*KB, a.Total())
+	assert.Equal(t, 3, a.Len())
+	assert.Equal(t, map[string]ByteSize{"alice": 3 * KB, "bob": 4 * KB, "carol": MB}, a.Snapshot())
+
+	assert.Equal(t, []AccountingEntry[string]{{"carol", MB}, {"bob", 4 * KB}}, a.TopN(2))
+	assert.Len(t, a.TopN(10), 3)
+	assert.Empty(t, a.TopN(-1))
+
+	assert.Equal(t, 4*KB, a.Delete("bob"))
+	assert.Equal(t, ByteSize(0), a.Delete("bob"))
+	assert.Equal(t, MB+3*KB, a.Total())
+	assert.Equal(t, 2, a.Len())
+}
+
+func TestAccountingMapConcurrent(t *testing.T) {
+	var a AccountingMap[int]
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				a.Add(j%10, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, ByteSize(8000), a.Total())
+	for k, v := range a.Snapshot() {
+		assert.Equal(t, ByteSize(800), v, fmt.Sprint(k))
+	}
+}
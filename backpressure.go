@@ -0,0 +1,107 @@
+package bytesizer
+
+import (
+	"context"
+	"io"
+)
+
+// Budgeter is a shared budget of bytes, such as the memory of a pipeline.
+// Acquire blocks until n bytes are available or ctx is done; Release gives
+// bytes back to the budget.
+type Budgeter interface {
+	Acquire(ctx context.Context, n ByteSize) error
+	Release(n ByteSize)
+}
+
+// QuotaBudget returns q as a Budgeter: Acquire reserves bytes with
+// WaitReserve and Release unreserves them.
+func QuotaBudget(q *Quota) Budgeter {
+	return quotaBudget{q}
+}
+
+type quotaBudget struct {
+	q *Quota
+}
+
+func (b quotaBudget) Acquire(ctx context.Context, n ByteSize) error {
+	return b.q.WaitReserve(ctx, n)
+}
+
+func (b quotaBudget) Release(n ByteSize) {
+	b.q.Unreserve(n)
+}
+
+func (b quotaBudget) maxAcquire() ByteSize {
+	return b.q.Snapshot().Limit
+}
+
+// budgetLimiter is implemented by budgets that refuse requests above a
+// limit, rather than wait for them. maxAcquire returns that limit.
+type budgetLimiter interface {
+	maxAcquire() ByteSize
+}
+
+// budgetReadSize is the most a BudgetReader reads at once, like the buffer
+// of io.Copy.
+const budgetReadSize = 32 * KB
+
+// BudgetReader reads from a reader only once the bytes fit in a budget,
+// blocking while they do not, so that a pipeline stage holds a bounded
+// amount of memory. The bytes read stay charged to the budget until the
+// consumer releases them, once it is done with the data:
+//
+//	budget := QuotaBudget(NewQuota(64 * MB))
+//	r := NewBudgetReader(ctx, src, budget)
+//	for {
+//		buf := make([]byte, 32*KB)
+//		n, err := r.Read(buf)
+//		if n > 0 {
+//			out <- buf[:n] // the consumer calls budget.Release(n)
+//		}
+//		if err != nil {
+//			break
+//		}
+//	}
+type BudgetReader struct {
+	ctx    context.Context
+	r      io.Reader
+	budget Budgeter
+}
+
+// NewBudgetReader returns a BudgetReader reading from r within budget.
+// Reads waiting for the budget return ctx.Err() when ctx is done.
+func NewBudgetReader(ctx context.Context, r io.Reader, budget Budgeter) *BudgetReader {
+	return &BudgetReader{ctx: ctx, r: r, budget: budget}
+}
+
+// Read acquires len(p) bytes from the budget, up to 32KB or the limit of a
+// QuotaBudget or Budget, then reads into p. The bytes not read are released at once;
+// the n bytes read are left for the caller to release.
+func (b *BudgetReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return b.r.Read(p)
+	}
+	size := budgetReadSize
+	if l, ok := b.budget.(budgetLimiter); ok {
+		if limit := l.maxAcquire(); limit > 0 && limit < size {
+			size = limit
+		}
+	}
+	if ByteSize(len(p)) > size {
+		p = p[:size]
+	}
+	if err := b.budget.Acquire(b.ctx, ByteSize(len(p))); err != nil {
+		return 0, err
+	}
+	n, err := b.r.Read(p)
+	if n < len(p) {
+		b.budget.Release(ByteSize(len(p) - n))
+	}
+	return n, err
+}
+
+// Release gives n bytes back to the budget, once the data read is no longer
+// held.
+func (b *BudgetReader) Release(n ByteSize) {
+	b.budget.Release(n)
+}
@@ -0,0 +1,130 @@
+package bytesizer
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudgetReader(t *testing.T) {
+	q := NewQuota(10)
+	r := NewBudgetReader(context.Background(), strings.NewReader("Hello, World!"), QuotaBudget(q))
+
+	buf := make([]byte, 8)
+	n, err := r.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello, W", string(buf[:n]))
+	assert.Equal(t, ByteSize(8), q.Snapshot().Reserved)
+
+	// The next read waits until the consumer releases what it holds.
+	done := make(chan string)
+	go func() {
+		buf := make([]byte, 8)
+		n, _ := r.Read(buf)
+		done <- string(buf[:n])
+	}()
+	select {
+	case <-done:
+		t.Fatal("Read did not wait for the budget")
+	case <-time.After(20 * time.Millisecond):
+	}
+	r.Release(8)
+	assert.Equal(t, "orld!", <-done)
+	assert.Equal(t, ByteSize(5), q.Snapshot().Reserved)
+}
+
+func TestBudgetReaderContext(t *testing.T) {
+	q := NewQuota(10)
+	assert.NoError(t, q.Reserve(10))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	r := NewBudgetReader(ctx, strings.NewReader("Hello"), QuotaBudget(q))
+	n, err := r.Read(make([]byte, 4))
+	assert.Equal(t, 0, n)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestBudgetReaderSmallQuota(t *testing.T) {
+	q := NewQuota(10)
+	r := NewBudgetReader(context.Background(), strings.NewReader("Hello, World!"), QuotaBudget(q))
+
+	// Reads from a large buffer reserve at most the limit.
+	var got []byte
+	buf := make([]byte, 32*KB)
+	for {
+		n, err := r.Read(buf)
+		got = append(got, buf[:n]...)
+		r.Release(ByteSize(n))
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, n, 10)
+	}
+	assert.Equal(t, "Hello, World!", string(got))
+	assert.Equal(t, ByteSize(0), q.Snapshot().Reserved)
+}
+
+func TestBudgetReaderBudget(t *testing.T) {
+	b := NewBudget(MB).Child(64 * KB).Child(KB)
+	data := bytes.Repeat([]byte("x"), int(5*KB))
+	r := NewBudgetReader(context.Background(), bytes.NewReader(data), NewBudget(10).Child(MB))
+
+	n, err := r.Read(make([]byte, 100))
+	assert.NoError(t, err)
+	assert.Equal(t, 10, n, "reads are capped at the smallest limit of the chain")
+
+	r = NewBudgetReader(context.Background(), bytes.NewReader(data), b)
+	var got int
+	buf := make([]byte, 32*KB)
+	for {
+		n, err := r.Read(buf)
+		got += n
+		r.Release(ByteSize(n))
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, n, int(KB))
+	}
+	assert.Equal(t, len(data), got)
+	assert.Equal(t, ByteSize(0), b.Stats().Reserved)
+}
+
+func TestBudgetReaderPipeline(t *testing.T) {
+	q := NewQuota(64 * KB)
+	budget := QuotaBudget(q)
+	data := bytes.Repeat([]byte("x"), int(MB))
+	r := NewBudgetReader(context.Background(), bytes.NewReader(data), budget)
+
+	chunks := make(chan []byte, 100)
+	go func() {
+		defer close(chunks)
+		for {
+			buf := make([]byte, 64*KB)
+			n, err := r.Read(buf)
+			if n > 0 {
+				chunks <- buf[:n]
+			}
+			if err == io.EOF {
+				return
+			}
+			assert.NoError(t, err)
+		}
+	}()
+
+	var total int
+	for c := range chunks {
+		assert.LessOrEqual(t, q.Snapshot().Reserved, 64*KB)
+		total += len(c)
+		budget.Release(ByteSize(len(c)))
+	}
+	assert.Equal(t, len(data), total)
+	assert.Equal(t, ByteSize(0), q.Snapshot().Reserved)
+}
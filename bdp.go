@@ -0,0 +1,82 @@
+package bytesizer
+
+import (
+	"math"
+	"math/bits"
+	"strconv"
+	"time"
+)
+
+// minTCPBuffer is the smallest buffer recommended by RecommendTCPBuffers.
+const minTCPBuffer = 64 * KB
+
+// BDP returns the bandwidth-delay product of a path: the bytes in flight
+// when a link of rate is kept busy over a round-trip time of rtt. A TCP
+// window smaller than that cannot use the whole link.
+//
+//	BDP(RateFromBits(1e9), 80*time.Millisecond) // 9.54MB
+func BDP(rate Rate, rtt time.Duration) ByteSize {
+	v := math.Ceil(float64(rate) * rtt.Seconds())
+	if v >= float64(Unlimited) {
+		return Unlimited
+	}
+	if v < 0 {
+		return 0
+	}
+	return ByteSize(v)
+}
+
+// TCPBuffers is a recommendation of socket buffer sizes for a path.
+type TCPBuffers struct {
+	// BDP is the bandwidth-delay product of the path.
+	BDP ByteSize
+	// Buffer is the recommended size of the receive and send buffers.
+	Buffer ByteSize
+}
+
+// RecommendTCPBuffers recommends buffer sizes for a path of rate and rtt.
+// Buffer is twice the BDP, as the kernel keeps part of each buffer for its
+// own bookkeeping, rounded up to a power of two and at least 64KB.
+func RecommendTCPBuffers(rate Rate, rtt time.Duration) TCPBuffers {
+	bdp := BDP(rate, rtt)
+	buf := minTCPBuffer
+	if bdp > buf/2 {
+		buf = Unlimited
+		if bdp <= Unlimited/4 {
+			buf = 1 << bits.Len64(uint64(2*bdp-1))
+		}
+	}
+	return TCPBuffers{BDP: bdp, Buffer: buf}
+}
+
+// Sysctls returns Linux sysctl settings allowing TCP buffers to grow to
+// Buffer, keeping the kernel's default minimum and initial sizes.
+//
+//	for k, v := range RecommendTCPBuffers(rate, rtt).Sysctls() {
+//		fmt.Printf("%s = %s\n", k, v)
+//	}
+func (b TCPBuffers) Sysctls() map[string]string {
+	limit := strconv.Itoa(int(b.Buffer))
+	return map[string]string{
+		"net.core.rmem_max": limit,
+		"net.core.wmem_max": limit,
+		"net.ipv4.tcp_rmem": "4096 131072 " + limit,
+		"net.ipv4.tcp_wmem": "4096 16384 " + limit,
+	}
+}
+
+// BufferSetter is implemented by connections with socket buffers, such as
+// *net.TCPConn and *net.UDPConn.
+type BufferSetter interface {
+	SetReadBuffer(bytes int) error
+	SetWriteBuffer(bytes int) error
+}
+
+// Apply sets the read and write buffers of c to Buffer. The kernel may cap
+// them, e.g. at net.core.rmem_max on Linux.
+func (b TCPBuffers) Apply(c BufferSetter) error {
+	if err := c.SetReadBuffer(int(b.Buffer)); err != nil {
+		return err
+	}
+	return c.SetWriteBuffer(int(b.Buffer))
+}
@@ -0,0 +1,103 @@
+package bytesizer
+
+import (
+	"errors"
+	"math"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBDP(t *testing.T) {
+	tests := []struct {
+		name string
+		rate Rate
+		rtt  time.Duration
+		want ByteSize
+	}{
+		{"1Gbps 80ms", RateFromBits(1e9), 80 * time.Millisecond, 10e6},
+		{"10Gbps 1ms", RateFromBits(10e9), time.Millisecond, 1250000},
+		{"100MB/s 50ms", 100 * MBps, 50 * time.Millisecond, 5 * MB},
+		{"Rounds up", 1000, 1500 * time.Microsecond, 2},
+		{"No delay", 100 * MBps, 0, 0},
+		{"Negative", -MBps, time.Second, 0},
+		{"Overflow", Rate(math.MaxFloat64), time.Second, Unlimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, BDP(tt.rate, tt.rtt))
+		})
+	}
+}
+
+func TestRecommendTCPBuffers(t *testing.T) {
+	tests := []struct {
+		name       string
+		rate       Rate
+		rtt        time.Duration
+		wantBuffer ByteSize
+	}{
+		{"1Gbps 80ms", RateFromBits(1e9), 80 * time.Millisecond, 32 * MB},
+		{"Power of two", 100 * MBps, 40 * time.Millisecond, 8 * MB},
+		{"LAN", RateFromBits(1e9), 100 * time.Microsecond, 64 * KB},
+		{"Just above minimum", 33 * KBps, time.Second, 128 * KB},
+		{"Huge", Rate(math.MaxFloat64), time.Second, Unlimited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := RecommendTCPBuffers(tt.rate, tt.rtt)
+			assert.Equal(t, BDP(tt.rate, tt.rtt), b.BDP)
+			assert.Equal(t, tt.wantBuffer, b.Buffer)
+		})
+	}
+}
+
+func TestTCPBuffersSysctls(t *testing.T) {
+	b := TCPBuffers{BDP: 10e6, Buffer: 32 * MB}
+	assert.Equal(t, map[string]string{
+		"net.core.rmem_max": "33554432",
+		"net.core.wmem_max": "33554432",
+		"net.ipv4.tcp_rmem": "4096 131072 33554432",
+		"net.ipv4.tcp_wmem": "4096 16384 33554432",
+	}, b.Sysctls())
+}
+
+type fakeBufferSetter struct {
+	read, write int
+	err         error
+}
+
+func (f *fakeBufferSetter) SetReadBuffer(n int) error {
+	f.read = n
+	return f.err
+}
+
+func (f *fakeBufferSetter) SetWriteBuffer(n int) error {
+	f.write = n
+	return nil
+}
+
+func TestTCPBuffersApply(t *testing.T) {
+	b := TCPBuffers{Buffer: 4 * MB}
+
+	f := &fakeBufferSetter{}
+	assert.NoError(t, b.Apply(f))
+	assert.Equal(t, 4*1024*1024, f.read)
+	assert.Equal(t, 4*1024*1024, f.write)
+
+	f = &fakeBufferSetter{err: errors.New("denied")}
+	assert.EqualError(t, b.Apply(f), "denied")
+	assert.Zero(t, f.write)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer l.Close()
+	c, err := net.Dial("tcp", l.Addr().String())
+	assert.NoError(t, err)
+	defer c.Close()
+	assert.NoError(t, b.Apply(c.(*net.TCPConn)))
+}
@@ -0,0 +1,193 @@
+package bytesizer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAllocs documents the allocations of the hot paths, so that changes
+// which add allocations fail.
+func TestAllocs(t *testing.T) {
+	size := 3*GB + 123456789
+	buf := make([]byte, 0, 64)
+	sizes := make([]ByteSize, 1000)
+	for i := range sizes {
+		sizes[i] = ByteSize(i) * 12345
+	}
+	FormatAll(sizes) // fill the buffer pool
+	var c Counter
+	cf := NewCachedFormatter(16)
+	cf.Format(size)
+	bf := NewFormatter(BoundaryAware())
+
+	type allocTest struct {
+		name string
+		max  float64
+		fn   func()
+	}
+	tests := []allocTest{
+		{"Parse integer", 0, func() { _, _ = Parse("4096") }},
+		{"Parse unit", 0, func() { _, _ = Parse("10MB") }},
+		{"Parse decimal", 0, func() { _, _ = Parse("1.5GiB") }},
+		{"ParseRate", 0, func() { _, _ = ParseRate("10MB/s") }},
+		{"AppendSize", 0, func() { buf = AppendSize(buf[:0], 3.14159, "GB", 2) }},
+		{"AppendFormat", 0, func() { buf = size.AppendFormat(buf[:0], MB) }},
+		{"AppendFormat auto", 0, func() { buf = size.AppendFormat(buf[:0], 0) }},
+		{"Counter", 0, func() { c.Add(KB) }},
+		{"CachedFormatter hit", 0, func() { _ = cf.Format(size) }},
+		{"Formatter AppendFormat", 0, func() { buf = bf.AppendFormat(buf[:0], size) }},
+		{"AppendText", 0, func() { buf, _ = (1536 * KB).AppendText(buf[:0]) }},
+		{"AppendText exact bytes", 0, func() { buf, _ = size.AppendText(buf[:0]) }},
+		// The returned string is the only allocation.
+		{"String", 1, func() { _ = size.String() }},
+		{"Format", 1, func() { _ = size.Format(KB) }},
+		{"FormatExact", 1, func() { _ = size.FormatExact() }},
+		{"Rate String", 1, func() { _ = (10 * MBps).String() }},
+	}
+	// The race detector makes sync.Pool drop items at random, so the
+	// buffers of FormatAll are not always reused.
+	if !raceEnabled {
+		tests = append(tests, []allocTest{
+			{"FormatAllTo", 0, func() { _, _ = FormatAllTo(io.Discard, sizes, "\n") }},
+			// The strings and the slice holding them.
+			{"FormatAll", 2, func() { FormatAll(sizes) }},
+		}...)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.LessOrEqual(t, testing.AllocsPerRun(100, tt.fn), tt.max)
+		})
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	inputs := []string{"4096", "10MB", "1.5GiB", "512B", "123456789012"}
+	for _, in := range inputs {
+		b.Run(in, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := Parse(in); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	b.ReportAllocs()
+	sizes := []ByteSize{532, 1536 * KB, 3 * GB, 3*GB + 123456789}
+	for i := 0; i < b.N; i++ {
+		_ = sizes[i%len(sizes)].String()
+	}
+}
+
+func BenchmarkFormat(b *testing.B) {
+	b.ReportAllocs()
+	size := 3*GB + 123456789
+	for i := 0; i < b.N; i++ {
+		_ = size.Format(MB)
+	}
+}
+
+func BenchmarkAppendFormat(b *testing.B) {
+	b.ReportAllocs()
+	size := 3*GB + 123456789
+	buf := make([]byte, 0, 64)
+	for i := 0; i < b.N; i++ {
+		buf = size.AppendFormat(buf[:0], MB)
+	}
+}
+
+func BenchmarkAppendSize(b *testing.B) {
+	b.ReportAllocs()
+	buf := make([]byte, 0, 64)
+	for i := 0; i < b.N; i++ {
+		buf = AppendSize(buf[:0], 3.14159, "GB", 2)
+	}
+}
+
+func BenchmarkDecimalPlaces(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = decimalPlaces(123456789.123)
+	}
+}
+
+func BenchmarkCounter(b *testing.B) {
+	b.ReportAllocs()
+	var c Counter
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c.Add(KB)
+		}
+	})
+}
+
+func BenchmarkFormatAll(b *testing.B) {
+	sizes := make([]ByteSize, 10000)
+	for i := range sizes {
+		sizes[i] = ByteSize(i) * 123457
+	}
+
+	b.Run("String", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			out := make([]string, len(sizes))
+			for j, s := range sizes {
+				out[j] = s.String()
+			}
+		}
+	})
+	b.Run("FormatAll", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			FormatAll(sizes)
+		}
+	})
+}
+
+func BenchmarkCachedFormatter(b *testing.B) {
+	buckets := []ByteSize{KB, 4 * KB, 64 * KB, MB, 16 * MB, 256 * MB, GB, 3*GB + 123456789}
+	b.Run("String", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = buckets[i%len(buckets)].String()
+		}
+	})
+	b.Run("CachedFormatter", func(b *testing.B) {
+		b.ReportAllocs()
+		f := NewCachedFormatter(len(buckets))
+		for i := 0; i < b.N; i++ {
+			_ = f.Format(buckets[i%len(buckets)])
+		}
+	})
+}
+
+// BenchmarkDirSize walks a tree of 100 directories of 100 files each.
+func BenchmarkDirSize(b *testing.B) {
+	dir := b.TempDir()
+	writeWideTree(b, dir, 100, 100)
+
+	for _, workers := range []int{1, 4, 16, 64} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := DirSize(context.Background(), dir, WithWorkers(workers)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+	b.Run("fanout=2", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := DirSize(context.Background(), dir, WithWorkers(16), WithFanout(2)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
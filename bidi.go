@@ -0,0 +1,50 @@
+package bytesizer
+
+// BidiMode selects how WithBidi protects formatted sizes embedded in
+// right-to-left text, such as Arabic or Hebrew, where the Unicode
+// bidirectional algorithm would otherwise reorder the number and a Latin
+// unit, or attach them to the text around.
+type BidiMode int
+
+const (
+	// BidiNone adds nothing, as without WithBidi.
+	BidiNone BidiMode = iota
+	// BidiIsolate wraps sizes in a first strong isolate (U+2068) and a pop
+	// directional isolate (U+2069), so that a size is laid out on its own,
+	// in the direction of its unit. It is the best choice for renderers
+	// that support isolates, such as current browsers.
+	BidiIsolate
+	// BidiMarks puts a left-to-right mark (U+200E) before and after sizes,
+	// for older renderers without isolates. It suits the left-to-right
+	// units of the built-in locales.
+	BidiMarks
+)
+
+const (
+	firstStrongIsolate    = "\u2068"
+	popDirectionalIsolate = "\u2069"
+	leftToRightMark       = "\u200e"
+)
+
+// WithBidi wraps formatted sizes in directional formatting characters, as
+// set by mode, so that they display correctly in right-to-left text:
+//
+//	label := size.FormatLocalized("ar", WithBidi(BidiIsolate))
+func WithBidi(mode BidiMode) FormatOption {
+	return func(c *formatConfig) {
+		c.bidi = mode
+	}
+}
+
+// appendBidi appends size to dst like appendSize, wrapped as set by c.bidi.
+func (c formatConfig) appendBidi(dst []byte, size ByteSize) []byte {
+	open, close := firstStrongIsolate, popDirectionalIsolate
+	if c.bidi == BidiMarks {
+		open, close = leftToRightMark, leftToRightMark
+	}
+	inner := c
+	inner.bidi = BidiNone
+	dst = append(dst, open...)
+	dst = inner.appendSize(dst, size)
+	return append(dst, close...)
+}
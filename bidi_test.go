@@ -0,0 +1,53 @@
+package bytesizer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithBidi(t *testing.T) {
+	tests := []struct {
+		name string
+		size ByteSize
+		opts []FormatOption
+		want string
+	}{
+		{"None", 1536 * KB, []FormatOption{WithBidi(BidiNone)}, "1.5MB"},
+		{"Isolate", 1536 * KB, []FormatOption{WithBidi(BidiIsolate)}, "\u20681.5MB\u2069"},
+		{"Marks", 1536 * KB, []FormatOption{WithBidi(BidiMarks)}, "\u200e1.5MB\u200e"},
+		{"Negative", -1536 * KB, []FormatOption{WithBidi(BidiIsolate)}, "\u2068-1.5MB\u2069"},
+		{"Unit", 1536 * KB, []FormatOption{WithBidi(BidiMarks), WithUnit(KB)}, "\u200e1536KB\u200e"},
+		{"Unlimited", Unlimited, []FormatOption{WithBidi(BidiIsolate)}, "\u2068unlimited\u2069"},
+		{"Locale", 1536 * KB, []FormatOption{WithBidi(BidiIsolate), WithLocale("fr")}, "\u20681,5\u00a0Mo\u2069"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewFormatter(tt.opts...).Format(tt.size))
+		})
+	}
+
+	assert.Equal(t, []string{"\u20681KB\u2069", "\u20682KB\u2069"}, FormatAll([]ByteSize{KB, 2 * KB}, WithBidi(BidiIsolate)))
+	assert.Equal(t, "\u2068-1,5\u00a0mégaoctet\u2069", (-1536*KB).Humanize("fr", WithBidi(BidiIsolate)))
+}
+
+func TestWithBidiParse(t *testing.T) {
+	p := NewParser(AllowSpaces())
+	for _, mode := range []BidiMode{BidiIsolate, BidiMarks} {
+		s := NewFormatter(WithBidi(mode)).Format(1536 * KB)
+		size, err := p.Parse(s)
+		assert.NoError(t, err, "%q", s)
+		assert.Equal(t, 1536*KB, size)
+	}
+
+	size, err := p.Parse("1.5\u200fGB")
+	assert.NoError(t, err)
+	assert.Equal(t, 1536*MB, size)
+
+	_, err = Parse("\u20681.5MB\u2069")
+	var perr *ParseError
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, "1.5MB", perr.Suggestion)
+}
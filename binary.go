@@ -0,0 +1,27 @@
+package bytesizer
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// The ByteSize is encoded as a zig-zag varint, as written by binary.AppendVarint,
+// so small sizes take a single byte and any size takes at most ten.
+func (fs ByteSize) MarshalBinary() ([]byte, error) {
+	return binary.AppendVarint(make([]byte, 0, binary.MaxVarintLen64), int64(fs)), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+// It decodes data written by MarshalBinary and rejects truncated input or trailing bytes.
+func (fs *ByteSize) UnmarshalBinary(data []byte) error {
+	v, n := binary.Varint(data)
+	if n <= 0 {
+		return fmt.Errorf("invalid binary byte size: % x", data)
+	}
+	if n != len(data) {
+		return fmt.Errorf("invalid binary byte size: %d trailing bytes", len(data)-n)
+	}
+	*fs = ByteSize(v)
+	return nil
+}
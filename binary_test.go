@@ -0,0 +1,55 @@
+package bytesizer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByteSizeMarshalBinary(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     ByteSize
+		expected []byte
+	}{
+		{"Zero", 0, []byte{0x00}},
+		{"Small", 63, []byte{0x7e}},
+		{"Negative", -1, []byte{0x01}},
+		{"Kilobyte", KB, []byte{0x80, 0x10}},
+		{"Megabyte", MB, []byte{0x80, 0x80, 0x80, 0x01}},
+		{"Max", math.MaxInt64, []byte{0xfe, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.size.MarshalBinary()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, b)
+
+			var got ByteSize
+			assert.NoError(t, got.UnmarshalBinary(b))
+			assert.Equal(t, tt.size, got)
+		})
+	}
+}
+
+func TestByteSizeUnmarshalBinaryInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"Empty", nil},
+		{"Truncated", []byte{0x80, 0x80}},
+		{"Trailing bytes", []byte{0x02, 0x00}},
+		{"Overflow", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size := KB
+			assert.Error(t, size.UnmarshalBinary(tt.data))
+			assert.Equal(t, KB, size)
+		})
+	}
+}
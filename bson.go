@@ -0,0 +1,58 @@
+package bytesizer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// BSON element types used by ByteSize.
+const (
+	bsonDouble = 0x01
+	bsonString = 0x02
+	bsonNull   = 0x0a
+	bsonInt32  = 0x10
+	bsonInt64  = 0x12
+)
+
+// MarshalBSONValue encodes the ByteSize as a BSON int64 holding the number of bytes.
+// It satisfies the ValueMarshaler interface of go.mongodb.org/mongo-driver/v2/bson
+// without depending on it.
+func (fs ByteSize) MarshalBSONValue() (typ byte, data []byte, err error) {
+	return bsonInt64, binary.LittleEndian.AppendUint64(nil, uint64(fs)), nil
+}
+
+// UnmarshalBSONValue decodes the ByteSize from a BSON int32, int64 or double
+// holding a number of bytes, or a string in any format accepted by Parse.
+// A BSON null leaves the value unchanged. It satisfies the ValueUnmarshaler
+// interface of go.mongodb.org/mongo-driver/v2/bson.
+func (fs *ByteSize) UnmarshalBSONValue(typ byte, data []byte) error {
+	switch {
+	case typ == bsonNull && len(data) == 0:
+		return nil
+	case typ == bsonInt32 && len(data) == 4:
+		*fs = ByteSize(int32(binary.LittleEndian.Uint32(data)))
+		return nil
+	case typ == bsonInt64 && len(data) == 8:
+		*fs = ByteSize(int64(binary.LittleEndian.Uint64(data)))
+		return nil
+	case typ == bsonDouble && len(data) == 8:
+		f := math.Float64frombits(binary.LittleEndian.Uint64(data))
+		size, err := FromFloat(f)
+		if err != nil {
+			return fmt.Errorf("bson: %w", err)
+		}
+		*fs = size
+		return nil
+	case typ == bsonString && len(data) >= 5:
+		// int32 length including the trailing NUL, then the bytes
+		n := int(binary.LittleEndian.Uint32(data))
+		text := data[4:]
+		if n != len(text) || text[n-1] != 0 || bytes.IndexByte(text[:n-1], 0) >= 0 {
+			return fmt.Errorf("bson: invalid string byte size")
+		}
+		return fs.UnmarshalText(text[:n-1])
+	}
+	return fmt.Errorf("bson: cannot decode type 0x%02x into ByteSize", typ)
+}
@@ -0,0 +1,83 @@
+package bytesizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type bsonDocument struct {
+	Name  string    `bson:"name"`
+	Size  ByteSize  `bson:"size"`
+	Limit *ByteSize `bson:"limit,omitempty"`
+}
+
+func TestByteSizeBSONRoundTrip(t *testing.T) {
+	limit := 10 * GB
+	in := bsonDocument{Name: "blob", Size: 1536 * KB, Limit: &limit}
+
+	b, err := bson.Marshal(in)
+	assert.NoError(t, err)
+
+	// stored as int64
+	var raw bson.M
+	assert.NoError(t, bson.Unmarshal(b, &raw))
+	assert.Equal(t, int64(1536*KB), raw["size"])
+	assert.Equal(t, int64(10*GB), raw["limit"])
+
+	var out bsonDocument
+	assert.NoError(t, bson.Unmarshal(b, &out))
+	assert.Equal(t, in, out)
+}
+
+func TestByteSizeUnmarshalBSONValue(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     interface{}
+		expectErr bool
+		expected  ByteSize
+	}{
+		{"int32", int32(2048), false, 2 * KB},
+		{"int64", int64(MB), false, MB},
+		{"double", 1536.0, false, 1536},
+		{"string", "10MB", false, 10 * MB},
+		{"null", nil, false, 0}, // the driver zeroes the field itself
+		{"invalid string", "lots", true, 0},
+		{"double overflow", 1e30, true, 0},
+		{"bool", true, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := bson.Marshal(bson.D{{Key: "size", Value: tt.value}})
+			assert.NoError(t, err)
+
+			out := bsonDocument{Size: 7}
+			err = bson.Unmarshal(b, &out)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, out.Size)
+			}
+		})
+	}
+}
+
+func TestByteSizeUnmarshalBSONValueMalformed(t *testing.T) {
+	tests := []struct {
+		typ  byte
+		data []byte
+	}{
+		{bsonInt64, []byte{1, 2, 3}},
+		{bsonString, []byte{4, 0, 0, 0, '1', 'K', 'B'}},
+		{bsonString, []byte{4, 0, 0, 0, '1', 'K', 'B', 'x'}},
+		{bsonString, []byte{4, 0, 0, 0, '1', 0, 'B', 0}},
+	}
+
+	for _, tt := range tests {
+		var size ByteSize
+		assert.Error(t, size.UnmarshalBSONValue(tt.typ, tt.data), "%x % x", tt.typ, tt.data)
+	}
+}
@@ -0,0 +1,242 @@
+package bytesizer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// BudgetStats is the state of a Budget at one point in time.
+type BudgetStats struct {
+	Limit    ByteSize
+	Reserved ByteSize
+	// Waiting is the number of reservations waiting for bytes.
+	Waiting int
+}
+
+// Available returns the bytes that can still be reserved from the budget
+// itself, not counting its parents.
+func (s BudgetStats) Available() ByteSize {
+	if s.Reserved >= s.Limit {
+		return 0
+	}
+	return s.Limit - s.Reserved
+}
+
+// BudgetOption configures a Budget.
+type BudgetOption func(*Budget)
+
+// WithBudgetMetrics sets a function called with the budget's stats after
+// each change, e.g. to update gauges. It is called without locks held.
+func WithBudgetMetrics(fn func(BudgetStats)) BudgetOption {
+	return func(b *Budget) {
+		b.metrics = fn
+	}
+}
+
+// budgetTree is shared by a Budget and its children.
+type budgetTree struct {
+	mu    sync.Mutex
+	freed chan struct{} // closed when bytes are released anywhere in the tree
+}
+
+// Budget caps the bytes held at once, such as the in-flight buffers of a
+// streaming system. Children have their own limits, and what they reserve
+// also counts against their parents, so a process can cap its total memory
+// and each stage of a pipeline separately. It is safe for concurrent use.
+//
+//	total := NewBudget(512 * MB)
+//	decode := total.Child(128 * MB)
+//
+//	release, err := decode.Reserve(ctx, ByteSize(len(frame)))
+//	if err != nil {
+//		return err
+//	}
+//	defer release()
+type Budget struct {
+	tree    *budgetTree
+	parent  *Budget
+	limit   ByteSize
+	metrics func(BudgetStats)
+
+	reserved ByteSize
+	waiting  int
+}
+
+// NewBudget returns a Budget of limit bytes.
+func NewBudget(limit ByteSize, opts ...BudgetOption) *Budget {
+	b := &Budget{tree: &budgetTree{}, limit: limit}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// Child returns a Budget of limit bytes drawing from b.
+func (b *Budget) Child(limit ByteSize, opts ...BudgetOption) *Budget {
+	c := &Budget{tree: b.tree, parent: b, limit: limit}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// TryReserve reserves n bytes if they fit in the budget and all its parents,
+// without waiting. The returned function releases them; calling it more
+// than once has no effect.
+func (b *Budget) TryReserve(n ByteSize) (release func(), ok bool) {
+	b.tree.mu.Lock()
+	ok = n >= 0 && n <= b.maxAcquire() && b.fits(n)
+	if ok {
+		b.add(n)
+	}
+	changed := b.chain()
+	b.tree.mu.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+	b.report(changed)
+	return b.releaseFunc(n), true
+}
+
+// Reserve is like TryReserve, but waits for bytes to be released while they
+// do not fit, until ctx is done. It fails at once if n exceeds the limit of
+// the budget or one of its parents.
+func (b *Budget) Reserve(ctx context.Context, n ByteSize) (release func(), err error) {
+	if err := b.Acquire(ctx, n); err != nil {
+		return nil, err
+	}
+	return b.releaseFunc(n), nil
+}
+
+// Acquire reserves n bytes like Reserve, to be given back with Release. It
+// makes a Budget a Budgeter.
+func (b *Budget) Acquire(ctx context.Context, n ByteSize) error {
+	if n < 0 {
+		return fmt.Errorf("invalid size %s", n)
+	}
+	for p := b; p != nil; p = p.parent {
+		if n > p.limit {
+			return fmt.Errorf("%s exceeds budget of %s", n, p.limit)
+		}
+	}
+
+	waiting := false
+	defer func() {
+		if waiting {
+			b.tree.mu.Lock()
+			b.waiting--
+			b.tree.mu.Unlock()
+			b.report([]*Budget{b})
+		}
+	}()
+	for {
+		b.tree.mu.Lock()
+		if b.fits(n) {
+			b.add(n)
+			changed := b.chain()
+			b.tree.mu.Unlock()
+			b.report(changed)
+			return nil
+		}
+		if b.tree.freed == nil {
+			b.tree.freed = make(chan struct{})
+		}
+		freed := b.tree.freed
+		if !waiting {
+			waiting = true
+			b.waiting++
+		}
+		b.tree.mu.Unlock()
+		b.report([]*Budget{b})
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-freed:
+		}
+	}
+}
+
+// maxAcquire returns the smallest limit of b and its parents, above which
+// Acquire fails.
+func (b *Budget) maxAcquire() ByteSize {
+	limit := b.limit
+	for p := b.parent; p != nil; p = p.parent {
+		if p.limit < limit {
+			limit = p.limit
+		}
+	}
+	return limit
+}
+
+// Release gives back n bytes reserved with Acquire.
+func (b *Budget) Release(n ByteSize) {
+	b.tree.mu.Lock()
+	for p := b; p != nil; p = p.parent {
+		p.reserved -= clampSize(n, p.reserved)
+	}
+	if b.tree.freed != nil {
+		close(b.tree.freed)
+		b.tree.freed = nil
+	}
+	changed := b.chain()
+	b.tree.mu.Unlock()
+	b.report(changed)
+}
+
+// Stats returns the current state of the budget.
+func (b *Budget) Stats() BudgetStats {
+	b.tree.mu.Lock()
+	defer b.tree.mu.Unlock()
+	return b.stats()
+}
+
+func (b *Budget) stats() BudgetStats {
+	return BudgetStats{Limit: b.limit, Reserved: b.reserved, Waiting: b.waiting}
+}
+
+func (b *Budget) releaseFunc(n ByteSize) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() { b.Release(n) })
+	}
+}
+
+// fits reports whether n more bytes fit in b and its parents. Call with
+// b.tree.mu held.
+func (b *Budget) fits(n ByteSize) bool {
+	for p := b; p != nil; p = p.parent {
+		if n > p.limit-p.reserved {
+			return false
+		}
+	}
+	return true
+}
+
+// add reserves n bytes in b and its parents. Call with b.tree.mu held.
+func (b *Budget) add(n ByteSize) {
+	for p := b; p != nil; p = p.parent {
+		p.reserved += n
+	}
+}
+
+// chain returns b and its parents.
+func (b *Budget) chain() []*Budget {
+	var chain []*Budget
+	for p := b; p != nil; p = p.parent {
+		chain = append(chain, p)
+	}
+	return chain
+}
+
+// report calls the metrics functions of budgets.
+func (b *Budget) report(budgets []*Budget) {
+	for _, p := range budgets {
+		if p.metrics == nil {
+			continue
+		}
+		p.metrics(p.Stats())
+	}
+}
@@ -0,0 +1,140 @@
+package bytesizer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudgetTryReserve(t *testing.T) {
+	b := NewBudget(10 * KB)
+
+	release, ok := b.TryReserve(8 * KB)
+	assert.True(t, ok)
+	_, ok = b.TryReserve(4 * KB)
+	assert.False(t, ok)
+	_, ok = b.TryReserve(-1)
+	assert.False(t, ok)
+	assert.Equal(t, BudgetStats{Limit: 10 * KB, Reserved: 8 * KB}, b.Stats())
+	assert.Equal(t, 2*KB, b.Stats().Available())
+
+	release()
+	release() // no effect
+	assert.Equal(t, ByteSize(0), b.Stats().Reserved)
+	_, ok = b.TryReserve(10 * KB)
+	assert.True(t, ok)
+	_, ok = b.TryReserve(11 * KB)
+	assert.False(t, ok)
+}
+
+func TestBudgetUnlimited(t *testing.T) {
+	b := NewBudget(Unlimited)
+	_, ok := b.TryReserve(1)
+	assert.True(t, ok)
+	_, ok = b.TryReserve(Unlimited)
+	assert.False(t, ok, "the sum would overflow")
+	assert.Equal(t, ByteSize(1), b.Stats().Reserved)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, b.Acquire(ctx, Unlimited), context.DeadlineExceeded)
+	assert.Equal(t, ByteSize(1), b.Stats().Reserved)
+}
+
+func TestBudgetChildren(t *testing.T) {
+	root := NewBudget(10 * KB)
+	a := root.Child(6 * KB)
+	b := root.Child(6 * KB)
+
+	releaseA, ok := a.TryReserve(6 * KB)
+	assert.True(t, ok)
+	_, ok = a.TryReserve(1)
+	assert.False(t, ok, "child limit")
+	_, ok = b.TryReserve(5 * KB)
+	assert.False(t, ok, "parent limit")
+	releaseB, ok := b.TryReserve(4 * KB)
+	assert.True(t, ok)
+	assert.Equal(t, 10*KB, root.Stats().Reserved)
+
+	releaseA()
+	releaseB()
+	assert.Equal(t, ByteSize(0), root.Stats().Reserved)
+	assert.Equal(t, ByteSize(0), a.Stats().Reserved)
+}
+
+func TestBudgetReserveWaits(t *testing.T) {
+	root := NewBudget(10 * KB)
+	child := root.Child(10 * KB)
+	release, ok := root.TryReserve(8 * KB)
+	assert.True(t, ok)
+
+	done := make(chan error)
+	go func() {
+		_, err := child.Reserve(context.Background(), 4*KB)
+		done <- err
+	}()
+	assert.Eventually(t, func() bool { return child.Stats().Waiting == 1 }, time.Second, time.Millisecond)
+
+	release()
+	assert.NoError(t, <-done)
+	assert.Equal(t, BudgetStats{Limit: 10 * KB, Reserved: 4 * KB}, child.Stats())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := child.Reserve(ctx, 8*KB)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 0, child.Stats().Waiting)
+
+	_, err = child.Reserve(context.Background(), 11*KB)
+	assert.Error(t, err)
+	_, err = root.Child(20*KB).Reserve(context.Background(), 11*KB)
+	assert.Error(t, err)
+}
+
+func TestBudgetMetrics(t *testing.T) {
+	var mu sync.Mutex
+	var stats []BudgetStats
+	b := NewBudget(MB, WithBudgetMetrics(func(s BudgetStats) {
+		mu.Lock()
+		defer mu.Unlock()
+		stats = append(stats, s)
+	}))
+	c := b.Child(MB)
+
+	release, ok := c.TryReserve(KB)
+	assert.True(t, ok)
+	release()
+
+	var budgeter Budgeter = b
+	assert.NoError(t, budgeter.Acquire(context.Background(), 2*KB))
+	budgeter.Release(2 * KB)
+
+	assert.Equal(t, []BudgetStats{
+		{Limit: MB, Reserved: KB},
+		{Limit: MB},
+		{Limit: MB, Reserved: 2 * KB},
+		{Limit: MB},
+	}, stats)
+}
+
+func TestBudgetConcurrent(t *testing.T) {
+	b := NewBudget(10 * KB)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				release, err := b.Reserve(context.Background(), 3*KB)
+				assert.NoError(t, err)
+				assert.LessOrEqual(t, b.Stats().Reserved, 10*KB)
+				release()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, BudgetStats{Limit: 10 * KB}, b.Stats())
+}
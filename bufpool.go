@@ -0,0 +1,167 @@
+package bytesizer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrPoolExhausted is returned by BufferPool when a buffer would take the
+// outstanding bytes past the pool's limit.
+var ErrPoolExhausted = errors.New("buffer pool limit reached")
+
+// minBufferClass is the smallest buffer handed out by a BufferPool.
+const minBufferClass = 64
+
+// BufferPool hands out byte slices and bytes.Buffers from size classes of
+// powers of two, reusing them once returned. It tracks the bytes handed out
+// and not yet returned, and can limit them. It is safe for concurrent use.
+//
+// Requests larger than the largest class are allocated and counted, but
+// not pooled.
+type BufferPool struct {
+	maxClass    ByteSize
+	limit       ByteSize
+	classes     []sync.Pool
+	outstanding atomic.Int64
+	buffers     sync.Map // *bytes.Buffer -> class it was handed out with
+}
+
+// NewBufferPool returns a pool with size classes from 64B up to maxClass,
+// rounded up to a power of two. If limit is positive, at most limit bytes
+// can be handed out at once, counted by class size.
+//
+//	pool := NewBufferPool(MB, 256*MB)
+//	buf, err := pool.Get(32 * KB)
+//	if err != nil {
+//		return err
+//	}
+//	defer pool.Put(buf)
+func NewBufferPool(maxClass, limit ByteSize) *BufferPool {
+	if maxClass < minBufferClass {
+		maxClass = minBufferClass
+	}
+	n := classIndex(maxClass) + 1
+	return &BufferPool{
+		maxClass: minBufferClass << (n - 1),
+		limit:    limit,
+		classes:  make([]sync.Pool, n),
+	}
+}
+
+// classIndex returns the index of the smallest class holding size.
+func classIndex(size ByteSize) int {
+	if size <= minBufferClass {
+		return 0
+	}
+	return bits.Len(uint(size-1)) - bits.Len(minBufferClass-1)
+}
+
+// Get returns a slice of length size, with the capacity of its class. It
+// returns ErrPoolExhausted if the pool's limit would be exceeded, and an
+// error for a negative size.
+func (p *BufferPool) Get(size ByteSize) ([]byte, error) {
+	if size < 0 {
+		return nil, fmt.Errorf("invalid buffer size %s", size)
+	}
+	if size > p.maxClass {
+		if err := p.acquire(size); err != nil {
+			return nil, err
+		}
+		return make([]byte, size), nil
+	}
+
+	i := classIndex(size)
+	class := ByteSize(minBufferClass) << i
+	if err := p.acquire(class); err != nil {
+		return nil, err
+	}
+	if b, ok := p.classes[i].Get().(*[]byte); ok {
+		return (*b)[:size], nil
+	}
+	return make([]byte, size, class), nil
+}
+
+// Put returns a slice obtained from Get to the pool. The slice must not be
+// used afterwards. Slices from elsewhere must not be put: those with the
+// capacity of a class would be counted as returned, though Outstanding
+// never goes below 0, and the others are ignored.
+func (p *BufferPool) Put(b []byte) {
+	size := ByteSize(cap(b))
+	if size > p.maxClass {
+		p.release(size)
+		return
+	}
+	i := classIndex(size)
+	if ByteSize(minBufferClass)<<i != size {
+		return // not from this pool
+	}
+	p.release(size)
+	b = b[:0]
+	p.classes[i].Put(&b)
+}
+
+// GetBuffer returns an empty bytes.Buffer with room for size bytes, like
+// Get.
+func (p *BufferPool) GetBuffer(size ByteSize) (*bytes.Buffer, error) {
+	b, err := p.Get(size)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBuffer(b[:0])
+	p.buffers.Store(buf, ByteSize(cap(b)))
+	return buf, nil
+}
+
+// PutBuffer returns a buffer obtained from GetBuffer to the pool. A buffer
+// that grew past its class is released instead of pooled.
+func (p *BufferPool) PutBuffer(buf *bytes.Buffer) {
+	v, ok := p.buffers.LoadAndDelete(buf)
+	if !ok {
+		return
+	}
+	class := v.(ByteSize)
+	buf.Reset()
+	if ByteSize(buf.Cap()) == class {
+		p.Put(buf.Bytes())
+		return
+	}
+	p.release(class)
+}
+
+// Outstanding returns the bytes handed out and not yet returned, counted by
+// class size.
+func (p *BufferPool) Outstanding() ByteSize {
+	return ByteSize(p.outstanding.Load())
+}
+
+// acquire counts size as outstanding, unless that exceeds the limit.
+func (p *BufferPool) acquire(size ByteSize) error {
+	for {
+		cur := p.outstanding.Load()
+		if p.limit > 0 && ByteSize(cur)+size > p.limit {
+			return ErrPoolExhausted
+		}
+		if p.outstanding.CompareAndSwap(cur, cur+int64(size)) {
+			return nil
+		}
+	}
+}
+
+// release counts size as returned, stopping at 0 should a slice the pool
+// did not hand out be put.
+func (p *BufferPool) release(size ByteSize) {
+	for {
+		cur := p.outstanding.Load()
+		next := cur - int64(size)
+		if next < 0 {
+			next = 0
+		}
+		if p.outstanding.CompareAndSwap(cur, next) {
+			return
+		}
+	}
+}
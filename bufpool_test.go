@@ -0,0 +1,109 @@
+package bytesizer
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBufferPoolClasses(t *testing.T) {
+	p := NewBufferPool(100*KB, 0)
+	tests := []struct {
+		size     ByteSize
+		expected int
+	}{
+		{0, 64}, {1, 64}, {64, 64}, {65, 128}, {4 * KB, 4096}, {4*KB + 1, 8192}, {128 * KB, 128 * 1024}, {128*KB + 1, 128*1024 + 1},
+	}
+
+	for _, tt := range tests {
+		b, err := p.Get(tt.size)
+		assert.NoError(t, err)
+		assert.Len(t, b, int(tt.size))
+		assert.Equal(t, tt.expected, cap(b), "size %d", tt.size)
+		p.Put(b)
+	}
+	assert.Equal(t, ByteSize(0), p.Outstanding())
+}
+
+func TestBufferPoolLimit(t *testing.T) {
+	p := NewBufferPool(MB, 10*KB)
+
+	a, err := p.Get(4 * KB)
+	assert.NoError(t, err)
+	b, err := p.Get(3 * KB) // 4KB class
+	assert.NoError(t, err)
+	assert.Equal(t, 8*KB, p.Outstanding())
+
+	_, err = p.Get(3 * KB)
+	assert.ErrorIs(t, err, ErrPoolExhausted)
+	_, err = p.Get(20 * KB)
+	assert.ErrorIs(t, err, ErrPoolExhausted)
+
+	p.Put(a)
+	c, err := p.Get(KB)
+	assert.NoError(t, err)
+	p.Put(b)
+	p.Put(c)
+	p.Put(make([]byte, 100)) // not from the pool
+	assert.Equal(t, ByteSize(0), p.Outstanding())
+}
+
+func TestBufferPoolInvalid(t *testing.T) {
+	p := NewBufferPool(MB, 0)
+	_, err := p.Get(-1)
+	assert.Error(t, err)
+	_, err = p.Get(Unknown)
+	assert.Error(t, err)
+	assert.Equal(t, ByteSize(0), p.Outstanding())
+
+	// A foreign slice with the capacity of a class cannot take the count
+	// below 0.
+	b, err := p.Get(KB)
+	assert.NoError(t, err)
+	p.Put(make([]byte, 4*KB))
+	assert.Equal(t, ByteSize(0), p.Outstanding())
+	p.Put(b)
+	assert.Equal(t, ByteSize(0), p.Outstanding())
+}
+
+func TestBufferPoolBuffer(t *testing.T) {
+	p := NewBufferPool(MB, 0)
+
+	buf, err := p.GetBuffer(KB)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, buf.Len())
+	assert.Equal(t, int(KB), buf.Cap())
+	buf.WriteString("hello")
+	assert.Equal(t, KB, p.Outstanding())
+	p.PutBuffer(buf)
+	assert.Equal(t, ByteSize(0), p.Outstanding())
+
+	// A grown buffer is released, not pooled.
+	buf, err = p.GetBuffer(64)
+	assert.NoError(t, err)
+	buf.Write(make([]byte, 1000))
+	p.PutBuffer(buf)
+	assert.Equal(t, ByteSize(0), p.Outstanding())
+
+	p.PutBuffer(buf) // twice is ignored
+	assert.Equal(t, ByteSize(0), p.Outstanding())
+}
+
+func TestBufferPoolConcurrent(t *testing.T) {
+	p := NewBufferPool(64*KB, 0)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				b, err := p.Get(ByteSize(i*j) % (100 * KB))
+				assert.NoError(t, err)
+				p.Put(b)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, ByteSize(0), p.Outstanding())
+}
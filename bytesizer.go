@@ -1,7 +1,8 @@
 package bytesizer
 
 import (
-	"fmt"
+	"bytes"
+	"errors"
 	"math"
 	"strconv"
 	"strings"
@@ -18,6 +19,33 @@ const (
 	PB
 )
 
+// Decimal units are powers of 1000, as used by disk vendors and most cloud
+// price lists, rather than the powers of 1024 of KB to PB.
+const (
+	DecimalKB ByteSize = 1000
+	DecimalMB ByteSize = 1000 * DecimalKB
+	DecimalGB ByteSize = 1000 * DecimalMB
+	DecimalTB ByteSize = 1000 * DecimalGB
+	DecimalPB ByteSize = 1000 * DecimalTB
+)
+
+// Unlimited is the largest ByteSize, used as a sentinel for sizes without a
+// limit, such as a container without a memory limit. It is formatted as
+// "unlimited", which a Parser accepts with AllowSentinels.
+const Unlimited ByteSize = math.MaxInt
+
+// Unknown is the smallest ByteSize, used as a sentinel for sizes that are
+// not known, such as the length of a stream. It is formatted as "–" (an en
+// dash), which a Parser accepts with AllowSentinels. Sizes range from -Unlimited to Unlimited, so
+// that no other size is Unknown.
+const Unknown ByteSize = math.MinInt
+
+// Names of the sentinels.
+const (
+	unlimitedName = "unlimited"
+	unknownName   = "\u2013"
+)
+
 var units = []struct {
 	size     ByteSize
 	unitName string
@@ -35,34 +63,65 @@ func Calc(b []byte) ByteSize {
 // then calls formatString to generate the final formatted string.
 // If the unit doesn't match any predefined units, it returns the string representation of the ByteSize itself.
 func (fs ByteSize) Format(bu ByteSize) string {
-
-	unitVal := float64(fs) / float64(bu)
-
-	for _, u := range units {
-		if u.size == bu {
-			return formatString(unitVal, u.unitName, 2)
-		}
-	}
-
-	return fs.String()
+	var buf [32]byte
+	return string(formatConfig{unit: bu, precision: 2}.appendSize(buf[:0], fs))
 }
 
 // String method converts ByteSize to a string with an appropriate unit.
+// The number is rounded to two decimals, so Parse(fs.String()) is within
+// half a hundredth of the unit of fs, or 0.5%: "1.00MB" for 1025KB parses
+// back as 1024KB. Use FormatExact for a string that parses back exactly.
+// The largest sizes are rounded toward zero instead, within a hundredth of
+// their unit, so that they do not print as "8192PB", beyond the largest
+// ByteSize.
+//
+// Negative sizes get the unit of their magnitude and a leading minus sign,
+// so (-fs).String() is "-" + fs.String(): -1536 is "-1.5KB". The same holds
+// for Format, FormatExact and FormatAll, except for sizes that round to 0.
 func (fs ByteSize) String() string {
-	switch {
-	case fs >= PB:
-		return formatString(fs.PB(), "PB", 2)
-	case fs >= TB:
-		return formatString(fs.TB(), "TB", 2)
-	case fs >= GB:
-		return formatString(fs.GB(), "GB", 2)
-	case fs >= MB:
-		return formatString(fs.MB(), "MB", 2)
-	case fs >= KB:
-		return formatString(fs.KB(), "KB", 2)
+	var buf [32]byte
+	return string(formatConfig{precision: 2}.appendSize(buf[:0], fs))
+}
+
+// FormatExact formats fs in the largest unit in which it has at most two
+// decimals, so that Parse(fs.FormatExact()) == fs for every ByteSize: 1536KB
+// is "1.5MB", like String, but 1025KB is "1025KB" where String prints
+// "1.00MB". Unknown, which is not a size, is "–" like String.
+func (fs ByteSize) FormatExact() string {
+	if fs == Unknown {
+		return unknownName
 	}
+	var buf [32]byte
+	return string(fs.appendExact(buf[:0]))
+}
 
-	return formatString(fs.Byte(), "B", 2)
+// appendExact appends fs.FormatExact() to dst.
+func (fs ByteSize) appendExact(dst []byte) []byte {
+	n := uint64(fs)
+	if fs < 0 {
+		dst = append(dst, '-')
+		n = -n
+	}
+	for i := len(units) - 1; i > 0; i-- {
+		u := uint64(units[i].size)
+		q, r := n/u, n%u
+		// Units are powers of two, so two decimals are .25, .5 or .75,
+		// which a float64 holds exactly for Parse if q is not too large.
+		if q == 0 || r*100%u != 0 || r != 0 && q >= 1<<50 {
+			continue
+		}
+		dst = strconv.AppendUint(dst, q, 10)
+		if r != 0 {
+			frac := r * 100 / u
+			dst = append(dst, '.', byte('0'+frac/10))
+			if frac%10 != 0 {
+				dst = append(dst, byte('0'+frac%10))
+			}
+		}
+		return append(dst, units[i].unitName...)
+	}
+	dst = strconv.AppendUint(dst, n, 10)
+	return append(dst, 'B')
 }
 
 // Byte method returns the ByteSize in bytes as a float64.
@@ -100,35 +159,57 @@ func (fs ByteSize) ByteInt() int {
 	return int(fs)
 }
 
-// KBInt method returns the ByteSize in kilobytes as an integer.
+// KBInt method returns the ByteSize in kilobytes as an integer, truncated
+// toward zero.
+//
+// Deprecated: Use In(KB, RoundTowardZero), which makes the rounding explicit.
 func (fs ByteSize) KBInt() int {
 	return int(fs / KB)
 }
 
-// MBInt method returns the ByteSize in megabytes as an integer.
+// MBInt method returns the ByteSize in megabytes as an integer, truncated
+// toward zero.
+//
+// Deprecated: Use In(MB, RoundTowardZero), which makes the rounding explicit.
 func (fs ByteSize) MBInt() int {
 	return int(fs / MB)
 }
 
-// GBInt method returns the ByteSize in gigabytes as an integer.
+// GBInt method returns the ByteSize in gigabytes as an integer, truncated
+// toward zero.
+//
+// Deprecated: Use In(GB, RoundTowardZero), which makes the rounding explicit.
 func (fs ByteSize) GBInt() int {
 	return int(fs / GB)
 }
 
-// TBInt method returns the ByteSize in terabytes as an integer.
+// TBInt method returns the ByteSize in terabytes as an integer, truncated
+// toward zero.
+//
+// Deprecated: Use In(TB, RoundTowardZero), which makes the rounding explicit.
 func (fs ByteSize) TBInt() int {
 	return int(fs / TB)
 }
 
-// PBInt method returns the ByteSize in petabytes as an integer.
+// PBInt method returns the ByteSize in petabytes as an integer, truncated
+// toward zero.
+//
+// Deprecated: Use In(PB, RoundTowardZero), which makes the rounding explicit.
 func (fs ByteSize) PBInt() int {
-	return int(fs / TB)
+	return int(fs / PB)
 }
 
 // parse a string s in bytes, kilobytes, megabytes, gigabytes,
 // terabytes or petabytes format and converts it into ByteSize, a datatype representing byte sizes.
 // accepts a string s like "10B", "10KB", "10MB", "10GB", "10TB", "10PB" and returns the corresponding ByteSize.
-// returns an error if the format of s is invalid or if an invalid size unit is found.
+// the IEC names "10KiB", "10MiB", "10GiB", "10TiB" and "10PiB" are accepted too, with the same values.
+// a number without a unit, like "1024", is a count of bytes.
+// the number must be a plain decimal, like "1.5" or "1e3", and the unit must end s:
+// trailing text, several sizes and other number forms such as hexadecimal are errors.
+// returns a *ParseError if the format of s is invalid (ErrSyntax), if an invalid size
+// unit is found (ErrUnknownUnit), if the number is NaN or infinite (ErrNotFinite), or
+// if the size does not fit in a ByteSize (ErrOutOfRange).
+// whole numbers are parsed exactly; numbers with decimals go through a float64.
 //
 // Example usage:
 //
@@ -140,23 +221,133 @@ func (fs ByteSize) PBInt() int {
 //
 // Output: 10240 // Bytes equivalent of 10KB
 func Parse(s string) (ByteSize, error) {
+	size, err := parse(s)
+	if err != nil {
+		return 0, newParseError(s, err)
+	}
+	return size, nil
+}
+
+// parse parses s like Parse, but returns only the cause of an error, such
+// as ErrSyntax.
+func parse(s string) (ByteSize, error) {
+	valueStr, unit, err := splitSize(s)
+	if err != nil {
+		return 0, err
+	}
+
+	// Whole numbers are parsed and multiplied as integers, which is faster
+	// and exact.
+	if isInteger(valueStr) {
+		n, err := strconv.ParseInt(valueStr, 10, strconv.IntSize)
+		if err != nil || n > math.MaxInt/int64(unit) || n < -math.MaxInt/int64(unit) {
+			return 0, ErrOutOfRange
+		}
+		return ByteSize(n) * unit, nil
+	}
+
+	value, err := parseFloat(valueStr)
+	if err != nil {
+		return 0, err
+	}
+	size, err := FromFloat(value * float64(unit))
+	if err != nil {
+		return 0, ErrOutOfRange
+	}
+	return size, nil
+}
+
+// parseUnits maps the unit names accepted by Parse to units. Names are
+// looked up as written first, then upper-cased, so the usual spellings do
+// not allocate.
+var parseUnits = map[string]ByteSize{
+	"B":   Byte,
+	"KB":  KB,
+	"MB":  MB,
+	"GB":  GB,
+	"TB":  TB,
+	"PB":  PB,
+	"KIB": KB,
+	"MIB": MB,
+	"GIB": GB,
+	"TIB": TB,
+	"PIB": PB,
+	"KiB": KB,
+	"MiB": MB,
+	"GiB": GB,
+	"TiB": TB,
+	"PiB": PB,
+}
+
+// parseSize parses s like Parse, but returns the number of bytes as a
+// float64 so that fractional bytes are kept, e.g. for rates.
+func parseSize(s string) (float64, error) {
+	valueStr, unit, err := splitSize(s)
+	if err != nil {
+		return 0, newParseError(s, err)
+	}
+
+	value, err := parseFloat(valueStr)
+	if err != nil {
+		return 0, newParseError(s, err)
+	}
+
+	return value * float64(unit), nil
+}
+
+// parseFloat parses the number of a size. It accepts only decimal numbers,
+// rejecting the hexadecimal, NaN and infinities strconv.ParseFloat accepts,
+// and numbers beyond the range of a float64.
+func parseFloat(s string) (float64, error) {
+	if !isDecimal(s) {
+		if v, err := strconv.ParseFloat(s, 64); err == nil && (math.IsNaN(v) || math.IsInf(v, 0)) {
+			return 0, ErrNotFinite
+		}
+		return 0, ErrSyntax
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	switch {
+	case errors.Is(err, strconv.ErrRange):
+		return 0, ErrOutOfRange
+	case err != nil:
+		return 0, ErrSyntax
+	}
+	return v, nil
+}
+
+// isInteger reports whether s is a decimal integer with an optional sign.
+func isInteger(s string) bool {
+	if len(s) > 0 && (s[0] == '+' || s[0] == '-') {
+		s = s[1:]
+	}
 	if len(s) == 0 {
-		return 0, fmt.Errorf("empty size string")
+		return false
 	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
 
-	units := map[string]ByteSize{
-		"B":  Byte,
-		"KB": KB,
-		"MB": MB,
-		"GB": GB,
-		"TB": TB,
-		"PB": PB,
+// splitSize splits s into its number and unit.
+func splitSize(s string) (string, ByteSize, error) {
+	if len(s) == 0 {
+		return "", 0, ErrSyntax
 	}
 
 	var unitName string
 	var valueStr string
 
-	if len(s) > 2 && strings.Contains("KMGTP", s[len(s)-2:len(s)-1]) {
+	if last := s[len(s)-1]; last >= '0' && last <= '9' || last == '.' {
+		// a bare number is a count of bytes
+		unitName = "B"
+		valueStr = s
+	} else if len(s) > 3 && s[len(s)-2] == 'i' && strings.Contains("KMGTP", s[len(s)-3:len(s)-2]) {
+		unitName = s[len(s)-3:]
+		valueStr = s[:len(s)-3]
+	} else if len(s) > 2 && strings.Contains("KMGTP", s[len(s)-2:len(s)-1]) {
 		unitName = s[len(s)-2:]
 		valueStr = s[:len(s)-2]
 	} else {
@@ -164,17 +355,14 @@ func Parse(s string) (ByteSize, error) {
 		valueStr = s[:len(s)-1]
 	}
 
-	unit, exists := units[strings.ToUpper(unitName)]
+	unit, exists := parseUnits[unitName]
 	if !exists {
-		return 0, fmt.Errorf("invalid size unit: %v", unitName)
+		unit, exists = parseUnits[strings.ToUpper(unitName)]
 	}
-
-	value, err := strconv.ParseFloat(valueStr, 64)
-	if err != nil {
-		return 0, err
+	if !exists {
+		return "", 0, ErrUnknownUnit
 	}
-
-	return ByteSize(value * float64(unit)), nil
+	return valueStr, unit, nil
 }
 
 // formatString. format value in a proper way
@@ -183,30 +371,95 @@ func Parse(s string) (ByteSize, error) {
 // example formatString(1.011, "MB") => 1.01MB
 // example formatString(1.001, "MB") => 1.00MB
 func formatString(v float64, unit string, maxDecimalCount ...int) string {
+	maxDecimals := -1
+	if len(maxDecimalCount) > 0 {
+		maxDecimals = maxDecimalCount[0]
+	}
+	// Most sizes fit in buf, so only the string is allocated.
+	var buf [32]byte
+	return string(AppendSize(buf[:0], v, unit, maxDecimals))
+}
+
+// AppendSize appends v followed by unit to dst, with the formatting rules
+// of String and Format, and returns the extended buffer. It is for encoders
+// that write sizes without allocating, such as log encoders.
+//
+// v is printed with as many decimals as it has, up to prec, or up to ten if
+// prec is negative. A value rounded to prec decimals keeps them, so 1.001
+// with a prec of 2 is "1.00". NaN and infinities are printed as "NaN",
+// "+Inf" and "-Inf" without the unit, which would be meaningless.
+//
+//	buf = AppendSize(buf, size.MB(), "MB", 2)
+func AppendSize(dst []byte, v float64, unit string, prec int) []byte {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.AppendFloat(dst, v, 'g', -1, 64)
+	}
 	decimals := decimalPlaces(v)
-	if len(maxDecimalCount) > 0 && maxDecimalCount[0] >= 0 {
-		if decimals > maxDecimalCount[0] {
-			decimals = maxDecimalCount[0]
-		}
+	if prec >= 0 && decimals > prec {
+		decimals = prec
 	}
 
-	// rounding
+	// rounding, half away from zero; adding 0 turns -0 into 0
 	multiper := math.Pow(10, float64(decimals))
-	n := math.Round(v*multiper) / multiper
+	n := math.Round(v*multiper)/multiper + 0
 
-	formatString := fmt.Sprintf("%%.%df%%s", decimals)
-	return fmt.Sprintf(formatString, n, unit)
+	dst = strconv.AppendFloat(dst, n, 'f', decimals, 64)
+	return append(dst, unit...)
 }
 
-// decimalPlaces counts the decimal places in a float64.
+// maxDecimalPlaces is the precision of decimalPlaces: decimals beyond it,
+// or beyond the 15 significant digits a float64 holds exactly, are treated
+// as floating point noise, so 0.1+0.2 has 1 decimal place.
+const maxDecimalPlaces = 10
+
+// decimalPlaces counts the decimal places in a float64, up to
+// maxDecimalPlaces.
 // E.g1: decimalPlaces(1.23) returns 2
 // E.g2: decimalPlaces(100.456) returns 3.
 // E.g3: decimalPlaces(10.100) returns 1.
-func decimalPlaces(f float64) (n int) {
-	const epsilon = 1e-10
-	for math.Abs(f-math.Floor(f)) > epsilon {
-		f *= 10
-		n++
+func decimalPlaces(f float64) int {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0
+	}
+
+	// Round to 15 significant digits, e.g. "1.23000000000000e+02", and
+	// count the digits of the mantissa left after the decimal point.
+	var buf [64]byte
+	b := strconv.AppendFloat(buf[:0], f, 'e', 14, 64)
+	e := len(b) - 1
+	for b[e] != 'e' {
+		e--
+	}
+	exp := 0
+	for _, c := range b[e+2:] {
+		exp = exp*10 + int(c-'0')
+	}
+	if b[e+1] == '-' {
+		exp = -exp
+	}
+	last := e - 1
+	for b[last] == '0' {
+		last--
+	}
+	n := 0
+	if b[last] != '.' {
+		n = last - bytes.IndexByte(b, '.')
+	}
+	n -= exp
+
+	switch {
+	case n <= 0:
+		return 0
+	case n <= maxDecimalPlaces:
+		return n
+	}
+
+	// Too small a fraction: round to maxDecimalPlaces instead.
+	b = strconv.AppendFloat(buf[:0], f, 'f', maxDecimalPlaces, 64)
+	n = maxDecimalPlaces
+	for n > 0 && b[len(b)-1] == '0' {
+		b = b[:len(b)-1]
+		n--
 	}
 	return n
 }
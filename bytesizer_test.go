@@ -2,6 +2,10 @@ package bytesizer
 
 import (
 	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -93,6 +97,141 @@ func TestConversions(t *testing.T) {
 	}
 }
 
+func TestFormatExact(t *testing.T) {
+	tests := []struct {
+		size ByteSize
+		want string
+	}{
+		{0, "0B"},
+		{532, "532B"},
+		{KB, "1KB"},
+		{1536, "1.5KB"},
+		{1025 * KB, "1025KB"},
+		{1536 * KB, "1.5MB"},
+		{MB + 256*KB, "1.25MB"},
+		{MB + 1, "1048577B"},
+		{3*GB + 768*MB, "3.75GB"},
+		{8191 * PB, "8191PB"},
+		{Unlimited, "9223372036854775807B"},
+		{-1536, "-1.5KB"},
+		{-2 * GB, "-2GB"},
+		{-Unlimited, "-9223372036854775807B"},
+		{Unknown, "\u2013"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.size.FormatExact())
+		})
+	}
+}
+
+// roundTripSizes returns sizes to check round trips with: all sizes up to
+// a few KB, sizes around each unit boundary and random sizes of every
+// magnitude.
+func roundTripSizes() []ByteSize {
+	var sizes []ByteSize
+	for s := ByteSize(0); s <= 4*KB; s++ {
+		sizes = append(sizes, s)
+	}
+	for _, unit := range units {
+		u := unit.size
+		for _, m := range []ByteSize{1, 2, 1023, 1024, 1025} {
+			for d := ByteSize(-3); d <= 3; d++ {
+				if m*u+d >= 0 {
+					sizes = append(sizes, m*u+d, m*u+d*u/100)
+				}
+			}
+		}
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100000; i++ {
+		sizes = append(sizes, ByteSize(r.Int63()>>r.Intn(63)))
+	}
+	sizes = append(sizes, Unlimited)
+	return sizes
+}
+
+func TestFormatExactRoundTrip(t *testing.T) {
+	for _, size := range roundTripSizes() {
+		for _, s := range []ByteSize{size, -size} {
+			got, err := Parse(s.FormatExact())
+			if !assert.NoError(t, err) || !assert.Equal(t, s, got, "Parse(%q)", s.FormatExact()) {
+				return
+			}
+		}
+	}
+}
+
+// TestStringRoundTrip checks the tolerance documented on String.
+func TestStringRoundTrip(t *testing.T) {
+	for _, size := range roundTripSizes() {
+		got, err := sentinels.Parse(size.String())
+		if !assert.NoError(t, err, "Parse(%q)", size.String()) {
+			return
+		}
+		// The unit String picked, and the bytes lost to truncating
+		// fractional bytes.
+		unit := Byte
+		for _, u := range units {
+			if size >= u.size {
+				unit = u.size
+			}
+		}
+		diff := math.Abs(float64(got) - float64(size))
+		tolerance := float64(unit) / 200
+		if float64(size) >= 0x1p63-float64(unit) {
+			tolerance = float64(unit) / 99 // a hundredth toward zero, and float rounding
+		}
+		if !assert.LessOrEqual(t, diff, tolerance+1, "Parse(%q) for %d", size.String(), int(size)) {
+			return
+		}
+		assert.LessOrEqual(t, diff, float64(size)*tolerance/float64(unit)+1)
+	}
+}
+
+func TestSentinels(t *testing.T) {
+	tests := []struct {
+		size ByteSize
+		want string
+	}{
+		{Unlimited, "unlimited"},
+		{Unknown, "\u2013"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.size.String())
+			assert.Equal(t, tt.want, tt.size.Format(GB))
+			assert.Equal(t, []string{tt.want}, FormatAll([]ByteSize{tt.size}, WithPrecision(-1)))
+			assert.Equal(t, tt.want, NewFormatter(BoundaryAware()).Format(tt.size))
+
+			got, err := NewParser(AllowSentinels()).Parse(tt.want)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.size, got)
+
+			_, err = Parse(tt.want)
+			assert.Error(t, err, "sentinels need AllowSentinels")
+		})
+	}
+
+	for _, s := range []string{"unlimited", "Unlimited", "UNLIMITED", "\u2013", "", "-", "unlimitedKB", "unlimited "} {
+		_, err := Parse(s)
+		assert.Error(t, err, s)
+	}
+	got, err := Parse("-1")
+	assert.NoError(t, err)
+	assert.Equal(t, ByteSize(-1), got, "-1 is a byte without AllowSentinels")
+
+	// Text stays a number, and Unknown has none.
+	assert.Equal(t, "9223372036854775807B", Unlimited.FormatExact())
+	text, err := Unlimited.MarshalText()
+	assert.NoError(t, err)
+	assert.Equal(t, "9223372036854775807B", string(text))
+	_, err = Unknown.MarshalText()
+	assert.Error(t, err)
+}
+
 func TestParse(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -109,6 +248,9 @@ func TestParse(t *testing.T) {
 		{"Invalid Unit", "1XB", true, 0},
 		{"Invalid Format", "OneKB", true, 0},
 		{"Empty String", "", true, 0},
+		{"Valid Parse without unit", "2048", false, 2 * KB},
+		{"Valid Parse without unit with decimal", "1.5", false, 1},
+		{"Invalid Unit only", "KB", true, 0},
 
 		{"Valid Parse B with decimal", "1024.5B", false, 1024},
 		{"Valid Parse KB with decimal", "1.5KB", false, ByteSize(1.5 * float64(KB))},
@@ -117,6 +259,26 @@ func TestParse(t *testing.T) {
 		{"Valid Parse TB with decimal", "1.5TB", false, ByteSize(1.5 * float64(TB))},
 		{"Valid Parse PB with decimal", "1.5PB", false, ByteSize(1.5 * float64(PB))},
 
+		{"Valid Parse KiB", "1KiB", false, KB},
+		{"Valid Parse MiB", "512MiB", false, 512 * MB},
+		{"Valid Parse GiB with decimal", "1.5GiB", false, ByteSize(1.5 * float64(GB))},
+		{"Valid Parse TiB", "2TiB", false, 2 * TB},
+		{"Valid Parse PiB", "1PiB", false, PB},
+		{"Valid Parse Gib", "1Gib", false, GB},
+		{"Invalid Unit iB", "1iB", true, 0},
+
+		{"Exact integer", "9007199254740993", false, 9007199254740993},
+		{"Exact integer with unit", "8191PB", false, 8191 * PB},
+		{"Negative integer", "-2KB", false, -2 * KB},
+		{"Signed integer", "+3MB", false, 3 * MB},
+		{"Exponent", "1e3", false, 1000},
+		{"Integer overflow", "8192PB", true, 0},
+		{"Negative overflow", "-8193PB", true, 0},
+		{"Unknown is not a size", "-8192PB", true, 0},
+		{"Smallest integer", "-9223372036854775807", false, -Unlimited},
+		{"Unknown integer", "-9223372036854775808", true, 0},
+		{"Too many digits", "99999999999999999999", true, 0},
+
 		// Invalid case with floating point value
 		{"Invalid Format with MA", "5MA", true, 0},
 		{"Invalid Format with float", "One.5KB", true, 0},
@@ -191,6 +353,135 @@ func TestFormatString(t *testing.T) {
 	}
 }
 
+func TestAppendSize(t *testing.T) {
+	tests := []struct {
+		name string
+		dst  string
+		v    float64
+		unit string
+		prec int
+		want string
+	}{
+		{"Whole", "", 1, "MB", 2, "1MB"},
+		{"Decimals", "", 1.5, "GB", 2, "1.5GB"},
+		{"Rounded", "", 1.011, "MB", 2, "1.01MB"},
+		{"Rounded keeps decimals", "", 1.001, "MB", 2, "1.00MB"},
+		{"No decimals", "", 1.75, "KB", 0, "2KB"},
+		{"Any precision", "", 1.0009765625, "MB", -1, "1.0009765625MB"},
+		{"Appends", "size=", 512, "B", 2, "size=512B"},
+		{"Negative", "", -2.25, "TB", 1, "-2.3TB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, string(AppendSize([]byte(tt.dst), tt.v, tt.unit, tt.prec)))
+		})
+	}
+
+}
+
+func TestDecimalPlaces(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want int
+	}{
+		{0, 0},
+		{1, 0},
+		{1.23, 2},
+		{100.456, 3},
+		{10.100, 1},
+		{0.1 + 0.2, 1},
+		{123456789.123, 3},
+		{1e13 + 0.5, 1},
+		{1e15 + 0.5, 0}, // beyond float64 precision
+		{-2.75, 2},
+		{1.00000000001, 0},
+		{1.0 / 3, maxDecimalPlaces},
+		{1e300, 0},
+		{math.NaN(), 0},
+		{math.Inf(1), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.in), func(t *testing.T) {
+			assert.Equal(t, tt.want, decimalPlaces(tt.in))
+		})
+	}
+}
+
+// TestDecimalPlacesProperty checks numbers with a known count of decimals,
+// m / 10^n with m not a multiple of 10, against decimalPlaces and against
+// strconv's shortest formatting.
+func TestDecimalPlacesProperty(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 10000; i++ {
+		n := r.Intn(7)
+		m := r.Int63n(1e9)*10 + 1 + r.Int63n(9)
+		v := float64(m) / math.Pow(10, float64(n))
+		if !assert.Equal(t, n, decimalPlaces(v), "decimalPlaces(%v)", v) {
+			return
+		}
+		want := strconv.FormatFloat(v, 'f', -1, 64) + "B"
+		if !assert.Equal(t, want, formatString(v, "B", -1)) {
+			return
+		}
+	}
+}
+
+// TestNegative checks that negative sizes format and convert as their
+// magnitude with a minus sign.
+func TestNegative(t *testing.T) {
+	tests := []struct {
+		size ByteSize
+		want string
+	}{
+		{1, "-1B"},
+		{1023, "-1023B"},
+		{KB, "-1KB"},
+		{1536, "-1.5KB"},
+		{1025 * KB, "-1.00MB"},
+		{3*GB + 123456789, "-3.11GB"},
+		{2 * PB, "-2PB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			neg := -tt.size
+			assert.Equal(t, tt.want, neg.String())
+			assert.Equal(t, "-"+tt.size.String(), neg.String())
+			assert.Equal(t, "-"+tt.size.FormatExact(), neg.FormatExact())
+			assert.Equal(t, []string{"-" + tt.size.String()}, FormatAll([]ByteSize{neg}))
+			assert.Equal(t, "-"+NewFormatter(BoundaryAware()).Format(tt.size), NewFormatter(BoundaryAware()).Format(neg))
+			for _, u := range units {
+				want := tt.size.Format(u.size)
+				if strings.Trim(strings.TrimSuffix(want, u.unitName), "0.") != "" {
+					assert.Equal(t, "-"+want, neg.Format(u.size))
+				} else {
+					assert.Equal(t, want, neg.Format(u.size), "rounds to zero without a sign")
+				}
+			}
+
+			assert.Equal(t, -tt.size.Byte(), neg.Byte())
+			assert.Equal(t, -tt.size.KB(), neg.KB())
+			assert.Equal(t, -tt.size.MB(), neg.MB())
+			assert.Equal(t, -tt.size.GB(), neg.GB())
+			assert.Equal(t, -tt.size.TB(), neg.TB())
+			assert.Equal(t, -tt.size.PB(), neg.PB())
+
+			// Integer accessors truncate toward zero.
+			assert.Equal(t, -tt.size.ByteInt(), neg.ByteInt())
+			assert.Equal(t, -tt.size.KBInt(), neg.KBInt())
+			assert.Equal(t, -tt.size.MBInt(), neg.MBInt())
+			assert.Equal(t, -tt.size.GBInt(), neg.GBInt())
+			assert.Equal(t, -tt.size.TBInt(), neg.TBInt())
+			assert.Equal(t, -tt.size.PBInt(), neg.PBInt())
+		})
+	}
+
+	assert.Equal(t, "-8191.99PB", (-Unlimited).String())
+	assert.Equal(t, "-10MB/s", (-10 * MBps).String())
+}
+
 func TestByteSizeMethods(t *testing.T) {
 	assert := assert.New(t)
 
@@ -199,6 +490,7 @@ func TestByteSizeMethods(t *testing.T) {
 		byteInt, kbInt, mbInt, gbInt, tbInt, pbInt int
 	}{
 		{2048 * MB, 2048 * int(MB), 2048 * int(MB) / int(KB), 2048, int(2), 0, 0},
+		{3*PB + 5*TB, 3*int(PB) + 5*int(TB), 3*1024*1024*1024*1024 + 5*1024*1024*1024, 3*1024*1024*1024 + 5*1024*1024, 3*1024*1024 + 5*1024, 3*1024 + 5, 3},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,44 @@
+// Package bytesizerecho limits request body sizes in echo applications.
+//
+//	e := echo.New()
+//	e.Use(bytesizerecho.MaxBytes(25 * bytesizer.MB))
+package bytesizerecho
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/iamlongalong/bytesizer"
+	"github.com/labstack/echo/v4"
+)
+
+// MaxBytes returns a middleware limiting request bodies to limit bytes.
+// Requests declaring a larger Content-Length fail with a 413 Request Entity
+// Too Large *echo.HTTPError naming the limit. Other bodies fail with an
+// *bytesizer.ErrSizeExceeded once they go past the limit; handlers can
+// return it through HTTPError.
+func MaxBytes(limit bytesizer.ByteSize) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if err := bytesizer.CheckContentLength(req, limit); err != nil {
+				return HTTPError(err)
+			}
+			if req.Body != nil && req.Body != http.NoBody {
+				req.Body = bytesizer.MaxBytesReader(c.Response(), req.Body, limit)
+			}
+			return next(c)
+		}
+	}
+}
+
+// HTTPError converts an *bytesizer.ErrSizeExceeded into a 413 Request Entity
+// Too Large *echo.HTTPError naming the limit. Other errors are returned
+// unchanged.
+func HTTPError(err error) error {
+	var tooLarge *bytesizer.ErrSizeExceeded
+	if !errors.As(err, &tooLarge) {
+		return err
+	}
+	return echo.NewHTTPError(tooLarge.StatusCode(), "request body exceeded "+tooLarge.Limit.String()+" limit").SetInternal(err)
+}
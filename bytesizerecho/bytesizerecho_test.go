@@ -0,0 +1,59 @@
+package bytesizerecho
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/iamlongalong/bytesizer"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxBytes(t *testing.T) {
+	e := echo.New()
+	e.Use(MaxBytes(2 * bytesizer.KB))
+	e.POST("/upload", func(c echo.Context) error {
+		b, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return HTTPError(err)
+		}
+		return c.String(http.StatusOK, bytesizer.Calc(b).String())
+	})
+
+	tests := []struct {
+		name          string
+		size          int
+		contentLength int64
+		status        int
+		body          string
+	}{
+		{"Under limit", 1024, 1024, http.StatusOK, "1KB"},
+		{"Declared too large", 4096, 4096, http.StatusRequestEntityTooLarge, `{"message":"request body exceeded 2KB limit"}` + "\n"},
+		{"Chunked too large", 4096, -1, http.StatusRequestEntityTooLarge, `{"message":"request body exceeded 2KB limit"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(strings.Repeat("x", tt.size)))
+			req.ContentLength = tt.contentLength
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, tt.body, rec.Body.String())
+		})
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	other := errors.New("boom")
+	assert.Equal(t, other, HTTPError(other))
+
+	err := HTTPError(&bytesizer.ErrSizeExceeded{Limit: bytesizer.MB, Read: bytesizer.MB + 1})
+	var he *echo.HTTPError
+	assert.True(t, errors.As(err, &he))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, he.Code)
+}
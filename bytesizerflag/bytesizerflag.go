@@ -0,0 +1,103 @@
+// Package bytesizerflag provides pflag and cobra integration for bytesizer.ByteSize.
+//
+//	var maxSize bytesizer.ByteSize
+//	bytesizerflag.VarP(cmd.Flags(), &maxSize, "max-size", "m", 100*bytesizer.MB, "maximum upload size")
+//	_ = bytesizerflag.RegisterCompletion(cmd, "max-size")
+package bytesizerflag
+
+import (
+	"strings"
+
+	"github.com/iamlongalong/bytesizer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// unitSuffixes are the unit suffixes offered by shell completion.
+var unitSuffixes = []string{"B", "KB", "MB", "GB", "TB", "PB", "KiB", "MiB", "GiB", "TiB", "PiB"}
+
+// Value is a pflag.Value backed by a bytesizer.ByteSize.
+type Value bytesizer.ByteSize
+
+var _ pflag.Value = (*Value)(nil)
+
+// NewValue sets *p to val and returns p as a pflag.Value.
+func NewValue(val bytesizer.ByteSize, p *bytesizer.ByteSize) *Value {
+	*p = val
+	return (*Value)(p)
+}
+
+// Set parses s with bytesizer.Parse.
+func (v *Value) Set(s string) error {
+	size, err := bytesizer.Parse(s)
+	if err != nil {
+		return err
+	}
+	*v = Value(size)
+	return nil
+}
+
+// String returns the humanized size.
+func (v *Value) String() string {
+	return bytesizer.ByteSize(*v).String()
+}
+
+// Type returns the type name shown in usage messages.
+func (v *Value) Type() string {
+	return "bytesize"
+}
+
+// Var defines a ByteSize flag with the given name, default value and usage string.
+// The argument p points to a ByteSize variable in which to store the value of the flag.
+func Var(fs *pflag.FlagSet, p *bytesizer.ByteSize, name string, value bytesizer.ByteSize, usage string) {
+	fs.Var(NewValue(value, p), name, usage)
+}
+
+// VarP is like Var, but accepts a shorthand letter that can be used after a single dash.
+func VarP(fs *pflag.FlagSet, p *bytesizer.ByteSize, name, shorthand string, value bytesizer.ByteSize, usage string) {
+	fs.VarP(NewValue(value, p), name, shorthand, usage)
+}
+
+// ByteSize defines a ByteSize flag with the given name, default value and usage string.
+// The return value is the address of a ByteSize variable that stores the value of the flag.
+func ByteSize(fs *pflag.FlagSet, name string, value bytesizer.ByteSize, usage string) *bytesizer.ByteSize {
+	p := new(bytesizer.ByteSize)
+	Var(fs, p, name, value, usage)
+	return p
+}
+
+// ByteSizeP is like ByteSize, but accepts a shorthand letter that can be used after a single dash.
+func ByteSizeP(fs *pflag.FlagSet, name, shorthand string, value bytesizer.ByteSize, usage string) *bytesizer.ByteSize {
+	p := new(bytesizer.ByteSize)
+	VarP(fs, p, name, shorthand, value, usage)
+	return p
+}
+
+// Complete is a cobra completion function for ByteSize flags.
+// Once a number has been typed, it suggests that number followed by each unit suffix,
+// e.g. "10" completes to "10B", "10KB", "10MB", ..., "10KiB", ... and "10mi" to "10MiB".
+func Complete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	i := strings.IndexFunc(toComplete, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	if i < 0 {
+		i = len(toComplete)
+	}
+	number, suffix := toComplete[:i], strings.ToUpper(toComplete[i:])
+	if number == "" {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for _, unit := range unitSuffixes {
+		if strings.HasPrefix(strings.ToUpper(unit), suffix) {
+			completions = append(completions, number+unit)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// RegisterCompletion registers Complete as the completion function of the named flag of cmd.
+func RegisterCompletion(cmd *cobra.Command, name string) error {
+	return cmd.RegisterFlagCompletionFunc(name, Complete)
+}
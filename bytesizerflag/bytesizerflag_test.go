@@ -0,0 +1,76 @@
+package bytesizerflag
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/iamlongalong/bytesizer"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVarP(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+
+	var maxSize bytesizer.ByteSize
+	VarP(fs, &maxSize, "max-size", "m", 100*bytesizer.MB, "maximum size")
+	chunk := ByteSize(fs, "chunk", 4*bytesizer.KB, "chunk size")
+
+	assert.Equal(t, 100*bytesizer.MB, maxSize)
+	assert.Equal(t, 4*bytesizer.KB, *chunk)
+
+	assert.NoError(t, fs.Parse([]string{"-m", "1.5GB", "--chunk=512"}))
+	assert.Equal(t, bytesizer.ByteSize(1.5*float64(bytesizer.GB)), maxSize)
+	assert.Equal(t, bytesizer.ByteSize(512), *chunk)
+
+	assert.Error(t, fs.Parse([]string{"--chunk", "lots"}))
+}
+
+func TestValueUsage(t *testing.T) {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	ByteSizeP(fs, "max-size", "m", 100*bytesizer.MB, "maximum size")
+
+	f := fs.Lookup("max-size")
+	assert.Equal(t, "bytesize", f.Value.Type())
+	assert.Equal(t, "100MB", f.DefValue)
+	assert.Contains(t, fs.FlagUsages(), "-m, --max-size bytesize   maximum size (default 100MB)")
+}
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		toComplete string
+		expected   []string
+	}{
+		{"", nil},
+		{"MB", nil},
+		{"10", []string{"10B", "10KB", "10MB", "10GB", "10TB", "10PB", "10KiB", "10MiB", "10GiB", "10TiB", "10PiB"}},
+		{"1.5m", []string{"1.5MB", "1.5MiB"}},
+		{"1.5mi", []string{"1.5MiB"}},
+		{"10GB", []string{"10GB"}},
+		{"10GiB", []string{"10GiB"}},
+		{"10X", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.toComplete, func(t *testing.T) {
+			completions, directive := Complete(nil, nil, tt.toComplete)
+			assert.Equal(t, tt.expected, completions)
+			assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+		})
+	}
+}
+
+func TestRegisterCompletion(t *testing.T) {
+	var maxSize bytesizer.ByteSize
+	root := &cobra.Command{Use: "app", Run: func(*cobra.Command, []string) {}}
+	VarP(root.Flags(), &maxSize, "max-size", "m", 0, "maximum size")
+	assert.NoError(t, RegisterCompletion(root, "max-size"))
+
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetArgs([]string{cobra.ShellCompRequestCmd, "--max-size", "2g"})
+	assert.NoError(t, root.Execute())
+	assert.True(t, strings.HasPrefix(out.String(), "2GB\n"), out.String())
+}
@@ -0,0 +1,45 @@
+// Package bytesizergin limits request body sizes in gin applications.
+//
+//	r := gin.New()
+//	r.Use(bytesizergin.MaxBytes(25 * bytesizer.MB))
+package bytesizergin
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamlongalong/bytesizer"
+)
+
+// MaxBytes returns a middleware limiting request bodies to limit bytes.
+// Requests declaring a larger Content-Length are aborted with 413 Request
+// Entity Too Large and a JSON error naming the limit. Other bodies fail
+// with an *bytesizer.ErrSizeExceeded once they go past the limit, which
+// handlers can report with Abort.
+func MaxBytes(limit bytesizer.ByteSize) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := bytesizer.CheckContentLength(c.Request, limit); err != nil {
+			Abort(c, err)
+			return
+		}
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			c.Request.Body = bytesizer.MaxBytesReader(c.Writer, c.Request.Body, limit)
+		}
+		c.Next()
+	}
+}
+
+// Abort aborts the request with 413 Request Entity Too Large if err is an
+// *bytesizer.ErrSizeExceeded, and reports whether it did.
+func Abort(c *gin.Context, err error) bool {
+	var tooLarge *bytesizer.ErrSizeExceeded
+	if !errors.As(err, &tooLarge) {
+		return false
+	}
+	c.AbortWithStatusJSON(tooLarge.StatusCode(), gin.H{
+		"error": "request body exceeded " + tooLarge.Limit.String() + " limit",
+		"limit": int64(tooLarge.Limit),
+	})
+	return true
+}
@@ -0,0 +1,49 @@
+package bytesizergin
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/iamlongalong/bytesizer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxBytes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(MaxBytes(2 * bytesizer.KB))
+	r.POST("/upload", func(c *gin.Context) {
+		b, err := io.ReadAll(c.Request.Body)
+		if Abort(c, err) {
+			return
+		}
+		c.String(http.StatusOK, bytesizer.Calc(b).String())
+	})
+
+	tests := []struct {
+		name          string
+		size          int
+		contentLength int64
+		status        int
+		body          string
+	}{
+		{"Under limit", 1024, 1024, http.StatusOK, "1KB"},
+		{"Declared too large", 4096, 4096, http.StatusRequestEntityTooLarge, `{"error":"request body exceeded 2KB limit","limit":2048}`},
+		{"Chunked too large", 4096, -1, http.StatusRequestEntityTooLarge, `{"error":"request body exceeded 2KB limit","limit":2048}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(strings.Repeat("x", tt.size)))
+			req.ContentLength = tt.contentLength
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, tt.body, rec.Body.String())
+		})
+	}
+}
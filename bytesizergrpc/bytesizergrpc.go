@@ -0,0 +1,253 @@
+// Package bytesizergrpc provides gRPC interceptors that measure message
+// sizes, enforce size limits configured as ByteSize and report per-call
+// totals.
+//
+//	srv := grpc.NewServer(
+//		grpc.ChainUnaryInterceptor(bytesizergrpc.UnaryServerInterceptor(
+//			bytesizergrpc.WithMaxMessageSize(4*bytesizer.MB),
+//			bytesizergrpc.WithStats(func(ctx context.Context, s bytesizergrpc.Stats) {
+//				log.Printf("%s: %s in, %s out", s.Method, s.Received, s.Sent)
+//			}),
+//		)),
+//	)
+//
+// Sizes are those of the marshaled protobuf messages, as given by
+// proto.Size; messages that are not protobuf messages count as zero bytes.
+package bytesizergrpc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/iamlongalong/bytesizer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Stats holds the message sizes of a call.
+type Stats struct {
+	Method           string
+	Sent             bytesizer.ByteSize
+	Received         bytesizer.ByteSize
+	MessagesSent     int
+	MessagesReceived int
+}
+
+type config struct {
+	maxMessage bytesizer.ByteSize
+	budget     bytesizer.ByteSize
+	stats      func(context.Context, Stats)
+}
+
+// Option configures the interceptors.
+type Option func(*config)
+
+// WithMaxMessageSize limits the size of each message sent or received.
+func WithMaxMessageSize(max bytesizer.ByteSize) Option {
+	return func(c *config) {
+		c.maxMessage = max
+	}
+}
+
+// WithCallBudget limits the total size of the messages sent and received
+// during a call, which matters for streams.
+func WithCallBudget(max bytesizer.ByteSize) Option {
+	return func(c *config) {
+		c.budget = max
+	}
+}
+
+// WithStats sets a function called with the sizes of each call once it
+// ends, including calls that failed.
+func WithStats(fn func(ctx context.Context, s Stats)) Option {
+	return func(c *config) {
+		c.stats = fn
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := new(config)
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// call tracks the sizes of one call.
+type call struct {
+	cfg  *config
+	ctx  context.Context
+	once sync.Once
+
+	mu    sync.Mutex
+	stats Stats
+}
+
+func (c *config) newCall(ctx context.Context, method string) *call {
+	return &call{cfg: c, ctx: ctx, stats: Stats{Method: method}}
+}
+
+// sent records a sent message, returning a ResourceExhausted error if it
+// breaks a limit.
+func (c *call) sent(m interface{}) error {
+	size := messageSize(m)
+	c.mu.Lock()
+	c.stats.Sent += size
+	c.stats.MessagesSent++
+	c.mu.Unlock()
+	return c.check(size)
+}
+
+// received records a received message, returning a ResourceExhausted error
+// if it breaks a limit.
+func (c *call) received(m interface{}) error {
+	size := messageSize(m)
+	c.mu.Lock()
+	c.stats.Received += size
+	c.stats.MessagesReceived++
+	c.mu.Unlock()
+	return c.check(size)
+}
+
+func (c *call) check(size bytesizer.ByteSize) error {
+	if max := c.cfg.maxMessage; max > 0 && size > max {
+		return status.Errorf(codes.ResourceExhausted, "message size %s exceeds the %s limit", size, max)
+	}
+	c.mu.Lock()
+	total := c.stats.Sent + c.stats.Received
+	c.mu.Unlock()
+	if max := c.cfg.budget; max > 0 && total > max {
+		return status.Errorf(codes.ResourceExhausted, "call size %s exceeds the %s budget", total, max)
+	}
+	return nil
+}
+
+// done reports the stats of the call, once.
+func (c *call) done() {
+	if c.cfg.stats == nil {
+		return
+	}
+	c.once.Do(func() {
+		c.mu.Lock()
+		s := c.stats
+		c.mu.Unlock()
+		c.cfg.stats(c.ctx, s)
+	})
+}
+
+func messageSize(m interface{}) bytesizer.ByteSize {
+	if pm, ok := m.(proto.Message); ok {
+		return bytesizer.ByteSize(proto.Size(pm))
+	}
+	return 0
+}
+
+// UnaryServerInterceptor returns a server interceptor for unary calls.
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	cfg := newConfig(opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		c := cfg.newCall(ctx, info.FullMethod)
+		defer c.done()
+
+		if err := c.received(req); err != nil {
+			return nil, err
+		}
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+		if err := c.sent(resp); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor returns a server interceptor for streaming calls.
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	cfg := newConfig(opts)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		c := cfg.newCall(ss.Context(), info.FullMethod)
+		defer c.done()
+		return handler(srv, &serverStream{ServerStream: ss, call: c})
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	call *call
+}
+
+func (s *serverStream) SendMsg(m interface{}) error {
+	if err := s.call.sent(m); err != nil {
+		return err
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return s.call.received(m)
+}
+
+// UnaryClientInterceptor returns a client interceptor for unary calls.
+// Requests breaking a limit fail before being sent.
+func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	cfg := newConfig(opts)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		c := cfg.newCall(ctx, method)
+		defer c.done()
+
+		if err := c.sent(req); err != nil {
+			return err
+		}
+		if err := invoker(ctx, method, req, reply, cc, callOpts...); err != nil {
+			return err
+		}
+		return c.received(reply)
+	}
+}
+
+// StreamClientInterceptor returns a client interceptor for streaming calls.
+// The stats of a stream are reported when RecvMsg returns an error, which
+// includes io.EOF at the end of the stream.
+func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	cfg := newConfig(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		c := cfg.newCall(ctx, method)
+		cs, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			c.done()
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, call: c}, nil
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	call *call
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	if err := s.call.sent(m); err != nil {
+		return err
+	}
+	return s.ClientStream.SendMsg(m)
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	if err := s.ClientStream.RecvMsg(m); err != nil {
+		s.call.done()
+		return err
+	}
+	if err := s.call.received(m); err != nil {
+		s.call.done()
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package bytesizergrpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/iamlongalong/bytesizer"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	testpb "google.golang.org/grpc/interop/grpc_testing"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+type testServer struct {
+	testpb.UnimplementedTestServiceServer
+}
+
+func (testServer) UnaryCall(ctx context.Context, req *testpb.SimpleRequest) (*testpb.SimpleResponse, error) {
+	return &testpb.SimpleResponse{Payload: &testpb.Payload{Body: make([]byte, req.ResponseSize)}}, nil
+}
+
+func (testServer) StreamingOutputCall(req *testpb.StreamingOutputCallRequest, stream testpb.TestService_StreamingOutputCallServer) error {
+	for _, p := range req.ResponseParameters {
+		if err := stream.Send(&testpb.StreamingOutputCallResponse{Payload: &testpb.Payload{Body: make([]byte, p.Size)}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statsRecorder collects the stats reported by the interceptors.
+type statsRecorder struct {
+	mu    sync.Mutex
+	stats []Stats
+}
+
+func (r *statsRecorder) record(ctx context.Context, s Stats) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats = append(r.stats, s)
+}
+
+func (r *statsRecorder) last() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats[len(r.stats)-1]
+}
+
+func startServer(t *testing.T, serverOpts []Option, clientOpts []Option) testpb.TestServiceClient {
+	lis := bufconn.Listen(int(bytesizer.MB))
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor(serverOpts...)),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor(serverOpts...)),
+	)
+	testpb.RegisterTestServiceServer(srv, testServer{})
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(UnaryClientInterceptor(clientOpts...)),
+		grpc.WithChainStreamInterceptor(StreamClientInterceptor(clientOpts...)),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return testpb.NewTestServiceClient(conn)
+}
+
+func TestUnaryStats(t *testing.T) {
+	var server, client statsRecorder
+	c := startServer(t, []Option{WithStats(server.record)}, []Option{WithStats(client.record)})
+
+	req := &testpb.SimpleRequest{ResponseSize: int32(bytesizer.KB), Payload: &testpb.Payload{Body: make([]byte, 100)}}
+	resp, err := c.UnaryCall(context.Background(), req)
+	assert.NoError(t, err)
+
+	reqSize := bytesizer.ByteSize(proto.Size(req))
+	respSize := bytesizer.ByteSize(proto.Size(resp))
+	assert.Equal(t, Stats{
+		Method:           "/grpc.testing.TestService/UnaryCall",
+		Sent:             reqSize,
+		Received:         respSize,
+		MessagesSent:     1,
+		MessagesReceived: 1,
+	}, client.last())
+	assert.Equal(t, Stats{
+		Method:           "/grpc.testing.TestService/UnaryCall",
+		Sent:             respSize,
+		Received:         reqSize,
+		MessagesSent:     1,
+		MessagesReceived: 1,
+	}, server.last())
+}
+
+func TestUnaryMaxMessageSize(t *testing.T) {
+	c := startServer(t, []Option{WithMaxMessageSize(bytesizer.KB)}, nil)
+
+	_, err := c.UnaryCall(context.Background(), &testpb.SimpleRequest{ResponseSize: 100})
+	assert.NoError(t, err)
+
+	_, err = c.UnaryCall(context.Background(), &testpb.SimpleRequest{ResponseSize: int32(2 * bytesizer.KB)})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "exceeds the 1KB limit")
+
+	c = startServer(t, nil, []Option{WithMaxMessageSize(bytesizer.KB)})
+	_, err = c.UnaryCall(context.Background(), &testpb.SimpleRequest{Payload: &testpb.Payload{Body: make([]byte, 2*bytesizer.KB)}})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestStreamStatsAndBudget(t *testing.T) {
+	var server, client statsRecorder
+	c := startServer(t,
+		[]Option{WithStats(server.record), WithCallBudget(10 * bytesizer.KB)},
+		[]Option{WithStats(client.record)},
+	)
+
+	stream := func(sizes ...bytesizer.ByteSize) (int, error) {
+		req := &testpb.StreamingOutputCallRequest{}
+		for _, size := range sizes {
+			req.ResponseParameters = append(req.ResponseParameters, &testpb.ResponseParameters{Size: int32(size)})
+		}
+		s, err := c.StreamingOutputCall(context.Background(), req)
+		if err != nil {
+			return 0, err
+		}
+		n := 0
+		for {
+			if _, err := s.Recv(); err != nil {
+				if err == io.EOF {
+					return n, nil
+				}
+				return n, err
+			}
+			n++
+		}
+	}
+
+	n, err := stream(bytesizer.KB, 2*bytesizer.KB, 3*bytesizer.KB)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, 3, client.last().MessagesReceived)
+	assert.Greater(t, client.last().Received, 6*bytesizer.KB)
+	assert.Equal(t, client.last().Received, server.last().Sent)
+	assert.Equal(t, client.last().Sent, server.last().Received)
+
+	n, err = stream(4*bytesizer.KB, 4*bytesizer.KB, 4*bytesizer.KB)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "exceeds the 10KB budget")
+}
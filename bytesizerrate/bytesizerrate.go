@@ -0,0 +1,99 @@
+// Package bytesizerrate adapts golang.org/x/time/rate limiters to bytes,
+// with bytesizer.Rate limits and bytesizer.ByteSize bursts and events.
+//
+//	l := bytesizerrate.NewLimiter(10*bytesizer.MBps, bytesizer.MB)
+//	if err := l.WaitN(ctx, bytesizer.ByteSize(len(chunk))); err != nil {
+//		return err
+//	}
+package bytesizerrate
+
+import (
+	"context"
+	"time"
+
+	"github.com/iamlongalong/bytesizer"
+	"golang.org/x/time/rate"
+)
+
+// Limit converts a Rate to a rate.Limit of bytes per second. A rate of zero
+// or less means no limit, as in bytesizer, and becomes rate.Inf.
+func Limit(r bytesizer.Rate) rate.Limit {
+	if r <= 0 {
+		return rate.Inf
+	}
+	return rate.Limit(r)
+}
+
+// FromLimit converts a rate.Limit of bytes per second to a Rate. rate.Inf
+// becomes 0, meaning no limit.
+func FromLimit(l rate.Limit) bytesizer.Rate {
+	if l == rate.Inf {
+		return 0
+	}
+	return bytesizer.Rate(l)
+}
+
+// Burst converts a ByteSize to a burst for rate.NewLimiter. Negative sizes,
+// such as bytesizer.Unknown, are 0.
+func Burst(b bytesizer.ByteSize) int {
+	if b < 0 {
+		return 0
+	}
+	return int(b)
+}
+
+// Limiter wraps a rate.Limiter whose events are bytes.
+type Limiter struct {
+	l *rate.Limiter
+}
+
+// NewLimiter returns a Limiter allowing r, with bursts of up to burst
+// bytes.
+func NewLimiter(r bytesizer.Rate, burst bytesizer.ByteSize) *Limiter {
+	return Wrap(rate.NewLimiter(Limit(r), Burst(burst)))
+}
+
+// Wrap returns a Limiter using l, which counts bytes.
+func Wrap(l *rate.Limiter) *Limiter {
+	return &Limiter{l: l}
+}
+
+// Limiter returns the underlying rate.Limiter.
+func (l *Limiter) Limiter() *rate.Limiter {
+	return l.l
+}
+
+// Limit returns the rate, 0 if there is no limit.
+func (l *Limiter) Limit() bytesizer.Rate {
+	return FromLimit(l.l.Limit())
+}
+
+// Burst returns the burst size.
+func (l *Limiter) Burst() bytesizer.ByteSize {
+	return bytesizer.ByteSize(l.l.Burst())
+}
+
+// SetLimit changes the rate.
+func (l *Limiter) SetLimit(r bytesizer.Rate) {
+	l.l.SetLimit(Limit(r))
+}
+
+// SetBurst changes the burst size.
+func (l *Limiter) SetBurst(burst bytesizer.ByteSize) {
+	l.l.SetBurst(Burst(burst))
+}
+
+// AllowN reports whether n bytes may be sent now, and takes them if so.
+func (l *Limiter) AllowN(n bytesizer.ByteSize) bool {
+	return l.l.AllowN(time.Now(), Burst(n))
+}
+
+// ReserveN reserves n bytes, see rate.Limiter.ReserveN.
+func (l *Limiter) ReserveN(n bytesizer.ByteSize) *rate.Reservation {
+	return l.l.ReserveN(time.Now(), Burst(n))
+}
+
+// WaitN blocks until n bytes may be sent, see rate.Limiter.WaitN.
+func (l *Limiter) WaitN(ctx context.Context, n bytesizer.ByteSize) error {
+	return l.l.WaitN(ctx, Burst(n))
+}
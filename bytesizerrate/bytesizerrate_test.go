@@ -0,0 +1,58 @@
+package bytesizerrate
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/iamlongalong/bytesizer"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestConversions(t *testing.T) {
+	assert.Equal(t, rate.Limit(1048576), Limit(bytesizer.MBps))
+	assert.Equal(t, rate.Inf, Limit(0))
+	assert.Equal(t, rate.Inf, Limit(-1))
+
+	assert.Equal(t, bytesizer.KBps, FromLimit(1024))
+	assert.Equal(t, bytesizer.Rate(0), FromLimit(rate.Inf))
+
+	assert.Equal(t, 1024, Burst(bytesizer.KB))
+	assert.Equal(t, math.MaxInt, Burst(bytesizer.Unlimited))
+	assert.Equal(t, 0, Burst(-bytesizer.KB))
+	assert.Equal(t, 0, Burst(bytesizer.Unknown))
+}
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(100*bytesizer.KBps, 10*bytesizer.KB)
+	assert.Equal(t, 100*bytesizer.KBps, l.Limit())
+	assert.Equal(t, 10*bytesizer.KB, l.Burst())
+
+	assert.True(t, l.AllowN(10*bytesizer.KB))
+	assert.False(t, l.AllowN(bytesizer.KB))
+
+	start := time.Now()
+	assert.NoError(t, l.WaitN(context.Background(), 10*bytesizer.KB))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	assert.Error(t, l.WaitN(context.Background(), 11*bytesizer.KB))
+
+	r := l.ReserveN(bytesizer.KB)
+	assert.True(t, r.OK())
+	r.Cancel()
+
+	l.SetLimit(0)
+	l.SetBurst(bytesizer.MB)
+	assert.Equal(t, bytesizer.Rate(0), l.Limit())
+	assert.Equal(t, bytesizer.MB, l.Burst())
+	assert.True(t, l.AllowN(bytesizer.GB))
+}
+
+func TestWrap(t *testing.T) {
+	rl := rate.NewLimiter(rate.Limit(bytesizer.MBps), Burst(bytesizer.MB))
+	l := Wrap(rl)
+	assert.Same(t, rl, l.Limiter())
+	assert.Equal(t, bytesizer.MBps, l.Limit())
+}
@@ -0,0 +1,121 @@
+// Package bytesizervalidator registers byte size validation tags with
+// github.com/go-playground/validator/v10.
+//
+// After Register, the following tags are available on bytesizer.ByteSize,
+// integer and size-string fields:
+//
+//	bytesize              the value is a valid byte size (useful on strings)
+//	bytesize_min=1MB      the value is at least 1MB
+//	bytesize_max=1GB      the value is at most 1GB
+//
+// For example:
+//
+//	type UploadRequest struct {
+//		MaxSize bytesizer.ByteSize `validate:"bytesize_min=1MB,bytesize_max=1GB"`
+//		Chunk   string             `validate:"bytesize,bytesize_max=64MB"`
+//	}
+package bytesizervalidator
+
+import (
+	"fmt"
+	"reflect"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/iamlongalong/bytesizer"
+)
+
+// Tags registered by Register.
+const (
+	TagByteSize = "bytesize"
+	TagMin      = "bytesize_min"
+	TagMax      = "bytesize_max"
+)
+
+// Register adds the bytesize, bytesize_min and bytesize_max tags to v.
+func Register(v *validator.Validate) error {
+	if err := v.RegisterValidation(TagByteSize, validateByteSize); err != nil {
+		return err
+	}
+	if err := v.RegisterValidation(TagMin, validateMin); err != nil {
+		return err
+	}
+	return v.RegisterValidation(TagMax, validateMax)
+}
+
+// RegisterTranslations adds English messages for the tags to trans, such as
+// "MaxSize must be at least 1MB". Use RegisterTranslation for other languages.
+func RegisterTranslations(v *validator.Validate, trans ut.Translator) error {
+	messages := map[string]string{
+		TagByteSize: "{0} must be a valid byte size",
+		TagMin:      "{0} must be at least {1}",
+		TagMax:      "{0} must be at most {1}",
+	}
+	for tag, text := range messages {
+		if err := RegisterTranslation(v, trans, tag, text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterTranslation adds a message for one of the tags to trans.
+// In text, {0} is replaced by the field name and {1} by the tag parameter.
+func RegisterTranslation(v *validator.Validate, trans ut.Translator, tag, text string) error {
+	return v.RegisterTranslation(tag, trans,
+		func(ut ut.Translator) error {
+			return ut.Add(tag, text, true)
+		},
+		func(ut ut.Translator, fe validator.FieldError) string {
+			t, err := ut.T(fe.Tag(), fe.Field(), fe.Param())
+			if err != nil {
+				return fe.Error()
+			}
+			return t
+		},
+	)
+}
+
+func validateByteSize(fl validator.FieldLevel) bool {
+	_, ok := fieldSize(fl.Field())
+	return ok
+}
+
+func validateMin(fl validator.FieldLevel) bool {
+	size, ok := fieldSize(fl.Field())
+	return ok && size >= param(fl)
+}
+
+func validateMax(fl validator.FieldLevel) bool {
+	size, ok := fieldSize(fl.Field())
+	return ok && size <= param(fl)
+}
+
+// param parses the tag parameter. Like the built-in validator tags,
+// it panics on a malformed parameter since that is a programming error.
+func param(fl validator.FieldLevel) bytesizer.ByteSize {
+	size, err := bytesizer.Parse(fl.Param())
+	if err != nil {
+		panic(fmt.Sprintf("bytesizervalidator: bad parameter %q for tag %s: %v", fl.Param(), fl.GetTag(), err))
+	}
+	return size
+}
+
+// fieldSize returns the size held by an integer or size-string field.
+// Unsigned integers too large for a ByteSize are Unlimited, above any
+// maximum.
+func fieldSize(field reflect.Value) (bytesizer.ByteSize, bool) {
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return bytesizer.ByteSize(field.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if u := field.Uint(); u < uint64(bytesizer.Unlimited) {
+			return bytesizer.ByteSize(u), true
+		}
+		return bytesizer.Unlimited, true
+	case reflect.String:
+		size, err := bytesizer.Parse(field.String())
+		return size, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,80 @@
+package bytesizervalidator
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	"github.com/iamlongalong/bytesizer"
+	"github.com/stretchr/testify/assert"
+)
+
+type uploadRequest struct {
+	MaxSize bytesizer.ByteSize `validate:"bytesize_min=1MB,bytesize_max=1GB"`
+	Chunk   string             `validate:"bytesize,bytesize_max=64MB"`
+}
+
+func newValidate(t *testing.T) *validator.Validate {
+	v := validator.New()
+	assert.NoError(t, Register(v))
+	return v
+}
+
+func TestValidate(t *testing.T) {
+	v := newValidate(t)
+
+	tests := []struct {
+		name    string
+		req     uploadRequest
+		invalid []string
+	}{
+		{"Valid", uploadRequest{MaxSize: 10 * bytesizer.MB, Chunk: "8MB"}, nil},
+		{"Bounds are inclusive", uploadRequest{MaxSize: bytesizer.GB, Chunk: "64MB"}, nil},
+		{"Too small", uploadRequest{MaxSize: bytesizer.KB, Chunk: "8MB"}, []string{"bytesize_min"}},
+		{"Too large", uploadRequest{MaxSize: 2 * bytesizer.GB, Chunk: "65MB"}, []string{"bytesize_max", "bytesize_max"}},
+		{"Invalid string", uploadRequest{MaxSize: bytesizer.MB, Chunk: "lots"}, []string{"bytesize"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Struct(tt.req)
+			if tt.invalid == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			var tags []string
+			for _, fe := range err.(validator.ValidationErrors) {
+				tags = append(tags, fe.Tag())
+			}
+			assert.Equal(t, tt.invalid, tags)
+		})
+	}
+}
+
+func TestValidateVar(t *testing.T) {
+	v := newValidate(t)
+	assert.NoError(t, v.Var(2048, "bytesize_min=2KB"))
+	assert.Error(t, v.Var(uint(2047), "bytesize_min=2KB"))
+	assert.Error(t, v.Var(uint64(math.MaxUint64), "bytesize_max=1GB"))
+	assert.Error(t, v.Var(uint64(1<<63), "bytesize_max=1GB"))
+	assert.NoError(t, v.Var(uint64(math.MaxUint64), "bytesize_min=1GB"))
+	assert.Error(t, v.Var(1.5, "bytesize_min=1B"))
+	assert.Panics(t, func() { _ = v.Var(1, "bytesize_max=lots") })
+}
+
+func TestTranslations(t *testing.T) {
+	v := newValidate(t)
+	english := en.New()
+	trans, _ := ut.New(english, english).GetTranslator("en")
+	assert.NoError(t, RegisterTranslations(v, trans))
+
+	err := v.Struct(uploadRequest{MaxSize: bytesizer.KB, Chunk: "lots"})
+	var messages []string
+	for _, fe := range err.(validator.ValidationErrors) {
+		messages = append(messages, fe.Translate(trans))
+	}
+	assert.Equal(t, []string{"MaxSize must be at least 1MB", "Chunk must be a valid byte size"}, messages)
+}
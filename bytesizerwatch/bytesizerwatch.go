@@ -0,0 +1,253 @@
+// Package bytesizerwatch keeps the size of a directory tree up to date with
+// fsnotify, and notifies subscribers when it crosses thresholds.
+//
+//	w, err := bytesizerwatch.New("/var/cache/app", bytesizerwatch.WithThresholds(8*bytesizer.GB))
+//	if err != nil {
+//		return err
+//	}
+//	defer w.Close()
+//	w.Subscribe(func(c bytesizerwatch.Crossing) {
+//		if c.Above {
+//			evict()
+//		}
+//	})
+package bytesizerwatch
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/iamlongalong/bytesizer"
+)
+
+// Crossing reports that the size of the tree crossed a threshold.
+type Crossing struct {
+	Threshold bytesizer.ByteSize
+	Total     bytesizer.ByteSize // size after the change
+	Above     bool               // whether the size went up to or past the threshold, rather than below it
+}
+
+type config struct {
+	thresholds []bytesizer.ByteSize
+	onError    func(error)
+}
+
+// Option configures a Watcher.
+type Option func(*config)
+
+// WithThresholds sets the sizes whose crossing is reported to subscribers.
+func WithThresholds(thresholds ...bytesizer.ByteSize) Option {
+	return func(c *config) {
+		c.thresholds = append(c.thresholds, thresholds...)
+	}
+}
+
+// WithErrorHandler sets a function called with errors met while watching,
+// such as files that cannot be read. By default they are ignored.
+func WithErrorHandler(fn func(error)) Option {
+	return func(c *config) {
+		c.onError = fn
+	}
+}
+
+// Watcher maintains the total size of the regular files under a directory.
+// It walks the tree once, then updates the total from file system events.
+type Watcher struct {
+	cfg  config
+	fsw  *fsnotify.Watcher
+	done chan struct{}
+
+	mu    sync.Mutex
+	files map[string]bytesizer.ByteSize
+	total bytesizer.ByteSize
+	subs  []func(Crossing)
+}
+
+// New walks the tree at root and starts watching it.
+func New(root string, opts ...Option) (*Watcher, error) {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	sort.Slice(cfg.thresholds, func(i, j int) bool { return cfg.thresholds[i] < cfg.thresholds[j] })
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &Watcher{
+		cfg:   cfg,
+		fsw:   fsw,
+		done:  make(chan struct{}),
+		files: make(map[string]bytesizer.ByteSize),
+	}
+	if err := w.addTree(root); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+	go w.run()
+	return w, nil
+}
+
+// Total returns the current size of the tree.
+func (w *Watcher) Total() bytesizer.ByteSize {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.total
+}
+
+// Subscribe registers fn to be called when the size crosses a threshold.
+// Calls are made from the goroutine processing events, in order. Thresholds
+// already crossed by the initial walk are not reported; compare them with
+// Total after New.
+func (w *Watcher) Subscribe(fn func(Crossing)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subs = append(w.subs, fn)
+}
+
+// Close stops watching.
+func (w *Watcher) Close() error {
+	err := w.fsw.Close()
+	<-w.done
+	return err
+}
+
+// addTree records the files under root and watches its directories.
+func (w *Watcher) addTree(root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			w.fail(err)
+			return nil
+		}
+		switch {
+		case d.IsDir():
+			if err := w.fsw.Add(path); err != nil {
+				if path == root {
+					return err
+				}
+				w.fail(err)
+			}
+		case d.Type().IsRegular():
+			info, err := d.Info()
+			if err != nil {
+				w.fail(err)
+				return nil
+			}
+			w.set(path, bytesizer.ByteSize(info.Size()))
+		}
+		return nil
+	})
+}
+
+func (w *Watcher) run() {
+	defer close(w.done)
+	for {
+		select {
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			w.handle(ev)
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			w.fail(err)
+		}
+	}
+}
+
+func (w *Watcher) handle(ev fsnotify.Event) {
+	switch {
+	case ev.Has(fsnotify.Create), ev.Has(fsnotify.Write):
+		info, err := os.Lstat(ev.Name)
+		if errors.Is(err, fs.ErrNotExist) {
+			return // already gone, a Remove event follows
+		}
+		if err != nil {
+			w.fail(err)
+			return
+		}
+		switch {
+		case info.IsDir():
+			// Files may have been created before the watch was added.
+			if err := w.addTree(ev.Name); err != nil {
+				w.fail(err)
+			}
+		case info.Mode().IsRegular():
+			w.set(ev.Name, bytesizer.ByteSize(info.Size()))
+		}
+	case ev.Has(fsnotify.Remove), ev.Has(fsnotify.Rename):
+		w.remove(ev.Name)
+	}
+}
+
+// set records the size of the file at path.
+func (w *Watcher) set(path string, size bytesizer.ByteSize) {
+	w.mu.Lock()
+	old := w.total
+	w.total += size - w.files[path]
+	w.files[path] = size
+	w.mu.Unlock()
+	w.notify(old)
+}
+
+// remove forgets the file at path, or all files under it if it was a directory.
+func (w *Watcher) remove(path string) {
+	prefix := path + string(filepath.Separator)
+	w.mu.Lock()
+	old := w.total
+	for name, size := range w.files {
+		if name == path || strings.HasPrefix(name, prefix) {
+			w.total -= size
+			delete(w.files, name)
+		}
+	}
+	w.mu.Unlock()
+	w.notify(old)
+}
+
+// notify calls the subscribers for each threshold crossed since old, in the
+// order they were crossed.
+func (w *Watcher) notify(old bytesizer.ByteSize) {
+	w.mu.Lock()
+	total := w.total
+	subs := w.subs
+	w.mu.Unlock()
+
+	n := len(w.cfg.thresholds)
+	for i := range w.cfg.thresholds {
+		t := w.cfg.thresholds[i]
+		if total < old {
+			t = w.cfg.thresholds[n-1-i]
+		}
+		var c Crossing
+		switch {
+		case old < t && total >= t:
+			c = Crossing{Threshold: t, Total: total, Above: true}
+		case old >= t && total < t:
+			c = Crossing{Threshold: t, Total: total}
+		default:
+			continue
+		}
+		for _, fn := range subs {
+			fn(c)
+		}
+	}
+}
+
+func (w *Watcher) fail(err error) {
+	if w.cfg.onError != nil {
+		w.cfg.onError(err)
+	}
+}
@@ -0,0 +1,88 @@
+package bytesizerwatch
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/iamlongalong/bytesizer"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, path string, size bytesizer.ByteSize) {
+	t.Helper()
+	assert.NoError(t, os.WriteFile(path, make([]byte, size), 0o644))
+}
+
+func eventuallyTotal(t *testing.T, w *Watcher, expected bytesizer.ByteSize) {
+	t.Helper()
+	assert.Eventually(t, func() bool { return w.Total() == expected }, 5*time.Second, 10*time.Millisecond,
+		"expected total %s", expected)
+}
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.bin"), bytesizer.KB)
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0o755))
+	writeFile(t, filepath.Join(dir, "sub", "b.bin"), 2*bytesizer.KB)
+
+	w, err := New(dir)
+	assert.NoError(t, err)
+	defer w.Close()
+	assert.Equal(t, 3*bytesizer.KB, w.Total())
+
+	// New file, then grow it.
+	writeFile(t, filepath.Join(dir, "c.bin"), bytesizer.KB)
+	eventuallyTotal(t, w, 4*bytesizer.KB)
+	writeFile(t, filepath.Join(dir, "c.bin"), 4*bytesizer.KB)
+	eventuallyTotal(t, w, 7*bytesizer.KB)
+
+	// New directory with a file.
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "new"), 0o755))
+	writeFile(t, filepath.Join(dir, "new", "d.bin"), bytesizer.KB)
+	eventuallyTotal(t, w, 8*bytesizer.KB)
+
+	// Removals.
+	assert.NoError(t, os.Remove(filepath.Join(dir, "a.bin")))
+	eventuallyTotal(t, w, 7*bytesizer.KB)
+	assert.NoError(t, os.RemoveAll(filepath.Join(dir, "sub")))
+	eventuallyTotal(t, w, 5*bytesizer.KB)
+}
+
+func TestWatcherThresholds(t *testing.T) {
+	dir := t.TempDir()
+	w, err := New(dir, WithThresholds(2*bytesizer.KB, bytesizer.KB))
+	assert.NoError(t, err)
+	defer w.Close()
+
+	var mu sync.Mutex
+	var crossings []Crossing
+	w.Subscribe(func(c Crossing) {
+		mu.Lock()
+		defer mu.Unlock()
+		crossings = append(crossings, c)
+	})
+
+	writeFile(t, filepath.Join(dir, "a.bin"), 3*bytesizer.KB)
+	eventuallyTotal(t, w, 3*bytesizer.KB)
+	assert.NoError(t, os.Remove(filepath.Join(dir, "a.bin")))
+	eventuallyTotal(t, w, 0)
+
+	mu.Lock()
+	defer mu.Unlock()
+	// The file may be seen empty, then full, but each crossing happens once
+	// up and once down.
+	assert.Equal(t, []Crossing{
+		{Threshold: bytesizer.KB, Total: 3 * bytesizer.KB, Above: true},
+		{Threshold: 2 * bytesizer.KB, Total: 3 * bytesizer.KB, Above: true},
+		{Threshold: 2 * bytesizer.KB, Total: 0},
+		{Threshold: bytesizer.KB, Total: 0},
+	}, crossings)
+}
+
+func TestNewMissingRoot(t *testing.T) {
+	_, err := New(filepath.Join(t.TempDir(), "missing"))
+	assert.True(t, os.IsNotExist(err))
+}
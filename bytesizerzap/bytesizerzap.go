@@ -0,0 +1,42 @@
+// Package bytesizerzap provides zap fields for bytesizer values.
+//
+//	logger.Info("upload finished", bytesizerzap.Size("body", size))
+//	// {"msg":"upload finished","body":{"bytes":1572864,"human":"1.5MB"}}
+package bytesizerzap
+
+import (
+	"github.com/iamlongalong/bytesizer"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Object is a zapcore.ObjectMarshaler that logs a ByteSize as its raw number
+// of bytes and its humanized form.
+type Object bytesizer.ByteSize
+
+var _ zapcore.ObjectMarshaler = Object(0)
+
+// MarshalLogObject adds the "bytes" and "human" keys to enc.
+func (o Object) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	size := bytesizer.ByteSize(o)
+	enc.AddInt64("bytes", int64(size))
+	enc.AddString("human", size.String())
+	return nil
+}
+
+// Size returns a field logging v as an object with "bytes" and "human" keys.
+func Size(key string, v bytesizer.ByteSize) zap.Field {
+	return zap.Object(key, Object(v))
+}
+
+// Sizes returns a field logging each of vs as an object with "bytes" and "human" keys.
+func Sizes(key string, vs []bytesizer.ByteSize) zap.Field {
+	return zap.Array(key, zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+		for _, v := range vs {
+			if err := enc.AppendObject(Object(v)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
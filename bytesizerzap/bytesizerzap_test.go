@@ -0,0 +1,34 @@
+package bytesizerzap
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/iamlongalong/bytesizer"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSize(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	zap.New(core).Info("upload finished", Size("body", 1536*bytesizer.KB))
+
+	entries := logs.All()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, map[string]interface{}{
+		"body": map[string]interface{}{"bytes": int64(1572864), "human": "1.5MB"},
+	}, entries[0].ContextMap())
+}
+
+func TestSizeJSON(t *testing.T) {
+	var buf bytes.Buffer
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"})
+	logger := zap.New(zapcore.NewCore(enc, zapcore.AddSync(&buf), zap.InfoLevel))
+
+	logger.Info("sizes", Size("body", bytesizer.MB), Sizes("parts", []bytesizer.ByteSize{bytesizer.KB, 512}))
+	assert.Equal(t,
+		`{"msg":"sizes","body":{"bytes":1048576,"human":"1MB"},"parts":[{"bytes":1024,"human":"1KB"},{"bytes":512,"human":"512B"}]}`+"\n",
+		buf.String())
+}
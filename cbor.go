@@ -0,0 +1,141 @@
+package bytesizer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// CBOR major types used by ByteSize.
+const (
+	cborUnsigned = 0
+	cborNegative = 1
+	cborText     = 3
+	cborSimple   = 7
+)
+
+// MarshalCBOR encodes the ByteSize as a CBOR integer holding the number of
+// bytes, in its shortest form. It satisfies the Marshaler interface of
+// github.com/fxamacker/cbor without depending on it.
+func (fs ByteSize) MarshalCBOR() ([]byte, error) {
+	if fs < 0 {
+		return appendCBORHead(nil, cborNegative, uint64(-1-int64(fs))), nil
+	}
+	return appendCBORHead(nil, cborUnsigned, uint64(fs)), nil
+}
+
+// UnmarshalCBOR decodes the ByteSize from a CBOR integer or float holding a
+// number of bytes, or a text string in any format accepted by Parse.
+// CBOR null and undefined leave the value unchanged.
+func (fs *ByteSize) UnmarshalCBOR(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("cbor: empty byte size")
+	}
+
+	major, info := data[0]>>5, data[0]&0x1f
+	if major == cborSimple {
+		return fs.unmarshalCBORSimple(info, data[1:])
+	}
+
+	arg, body, err := readCBORArgument(info, data[1:])
+	if err != nil {
+		return err
+	}
+
+	switch major {
+	case cborUnsigned, cborNegative:
+		if len(body) != 0 || arg > math.MaxInt64 {
+			return fmt.Errorf("cbor: invalid byte size")
+		}
+		if major == cborNegative {
+			*fs = ByteSize(-1 - int64(arg))
+		} else {
+			*fs = ByteSize(arg)
+		}
+		return nil
+	case cborText:
+		if uint64(len(body)) != arg {
+			return fmt.Errorf("cbor: invalid byte size")
+		}
+		return fs.UnmarshalText(body)
+	}
+	return fmt.Errorf("cbor: cannot decode major type %d into ByteSize", major)
+}
+
+func (fs *ByteSize) unmarshalCBORSimple(info byte, body []byte) error {
+	var f float64
+	switch {
+	case (info == 22 || info == 23) && len(body) == 0: // null, undefined
+		return nil
+	case info == 25 && len(body) == 2:
+		f = float16ToFloat64(binary.BigEndian.Uint16(body))
+	case info == 26 && len(body) == 4:
+		f = float64(math.Float32frombits(binary.BigEndian.Uint32(body)))
+	case info == 27 && len(body) == 8:
+		f = math.Float64frombits(binary.BigEndian.Uint64(body))
+	default:
+		return fmt.Errorf("cbor: cannot decode simple value %d into ByteSize", info)
+	}
+
+	size, err := FromFloat(f)
+	if err != nil {
+		return fmt.Errorf("cbor: %w", err)
+	}
+	*fs = size
+	return nil
+}
+
+// appendCBORHead appends the initial byte and argument of a CBOR data item.
+func appendCBORHead(dst []byte, major byte, arg uint64) []byte {
+	major <<= 5
+	switch {
+	case arg < 24:
+		return append(dst, major|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(dst, major|24, byte(arg))
+	case arg <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, major|25), uint16(arg))
+	case arg <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, major|26), uint32(arg))
+	}
+	return binary.BigEndian.AppendUint64(append(dst, major|27), arg)
+}
+
+// readCBORArgument decodes the argument described by the additional information
+// of an initial byte and returns it with the remaining bytes.
+func readCBORArgument(info byte, data []byte) (uint64, []byte, error) {
+	switch {
+	case info < 24:
+		return uint64(info), data, nil
+	case info == 24 && len(data) >= 1:
+		return uint64(data[0]), data[1:], nil
+	case info == 25 && len(data) >= 2:
+		return uint64(binary.BigEndian.Uint16(data)), data[2:], nil
+	case info == 26 && len(data) >= 4:
+		return uint64(binary.BigEndian.Uint32(data)), data[4:], nil
+	case info == 27 && len(data) >= 8:
+		return binary.BigEndian.Uint64(data), data[8:], nil
+	}
+	return 0, nil, fmt.Errorf("cbor: invalid byte size")
+}
+
+// float16ToFloat64 converts an IEEE 754 half-precision float.
+func float16ToFloat64(h uint16) float64 {
+	exp, frac := int(h>>10&0x1f), float64(h&0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(frac, -24)
+	case 0x1f:
+		if frac != 0 {
+			return math.NaN()
+		}
+		f = math.Inf(1)
+	default:
+		f = math.Ldexp(frac+1024, exp-25)
+	}
+	if h&0x8000 != 0 {
+		return -f
+	}
+	return f
+}
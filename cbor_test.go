@@ -0,0 +1,104 @@
+package bytesizer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByteSizeMarshalCBOR(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     ByteSize
+		expected []byte
+	}{
+		{"Zero", 0, []byte{0x00}},
+		{"Small", 23, []byte{0x17}},
+		{"uint8", 200, []byte{0x18, 0xc8}},
+		{"uint16", KB, []byte{0x19, 0x04, 0x00}},
+		{"uint32", MB, []byte{0x1a, 0x00, 0x10, 0x00, 0x00}},
+		{"uint64", 4 * GB, []byte{0x1b, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00}},
+		{"Negative", -1, []byte{0x20}},
+		{"Negative uint16", -KB, []byte{0x39, 0x03, 0xff}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.size.MarshalCBOR()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, b)
+
+			// must agree with the reference encoder
+			ref, err := cbor.Marshal(int64(tt.size))
+			assert.NoError(t, err)
+			assert.Equal(t, ref, b)
+
+			var got ByteSize
+			assert.NoError(t, got.UnmarshalCBOR(b))
+			assert.Equal(t, tt.size, got)
+		})
+	}
+}
+
+func TestByteSizeUnmarshalCBOR(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     interface{}
+		expectErr bool
+		expected  ByteSize
+	}{
+		{"int", 2048, false, 2 * KB},
+		{"negative", -2048, false, -2 * KB},
+		{"float32", float32(1536), false, 1536},
+		{"float64", 1.5e3, false, 1500},
+		{"float16", cbor.RawMessage{0xf9, 0x66, 0x00}, false, 1536},
+		{"float16 subnormal", cbor.RawMessage{0xf9, 0x00, 0x01}, false, 0},
+		{"string", "1.5KB", false, 1536},
+		{"nil", nil, false, 7},
+		{"invalid string", "lots", true, 0},
+		{"uint64 overflow", uint64(math.MaxUint64), true, 0},
+		{"float overflow", 1e30, true, 0},
+		{"bool", true, true, 0},
+		{"bytes", []byte("1KB"), true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := cbor.Marshal(tt.value)
+			assert.NoError(t, err)
+
+			var size ByteSize = 7
+			err = size.UnmarshalCBOR(b)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, size)
+			}
+		})
+	}
+}
+
+func TestByteSizeUnmarshalCBORMalformed(t *testing.T) {
+	for _, data := range [][]byte{nil, {0x18}, {0x01, 0x02}, {0x63, 'K', 'B'}, {0xf9, 0x7c, 0x00}, {0xf5}} {
+		var size ByteSize
+		assert.Error(t, size.UnmarshalCBOR(data), "% x", data)
+	}
+}
+
+func TestByteSizeCBORStruct(t *testing.T) {
+	type payload struct {
+		Device string   `cbor:"1,keyasint"`
+		Size   ByteSize `cbor:"2,keyasint"`
+	}
+
+	in := payload{Device: "sensor", Size: 64 * KB}
+	b, err := cbor.Marshal(in)
+	assert.NoError(t, err)
+
+	var out payload
+	assert.NoError(t, cbor.Unmarshal(b, &out))
+	assert.Equal(t, in, out)
+}
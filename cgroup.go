@@ -0,0 +1,106 @@
+package bytesizer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// cgroupV1Unlimited is the smallest memory.limit_in_bytes treated as no
+// limit. Without a limit, cgroup v1 reports the largest page-aligned int64.
+const cgroupV1Unlimited = 1 << 62
+
+// CgroupMemoryLimit returns the memory limit of the cgroup of the current
+// process, from memory.max (cgroup v2) or memory.limit_in_bytes (cgroup v1).
+// It returns Unlimited if the cgroup has no limit, and an error if the
+// limit cannot be read, for example outside Linux.
+//
+// A cache can be sized from it:
+//
+//	limit, err := CgroupMemoryLimit()
+//	if err != nil || limit == Unlimited {
+//		limit = 4 * GB
+//	}
+//	cacheSize := limit / 4
+func CgroupMemoryLimit() (ByteSize, error) {
+	f, err := os.Open("/proc/self/cgroup")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return cgroupMemoryLimit("/sys/fs/cgroup", f)
+}
+
+// cgroupMemoryLimit reads the memory limit from the cgroup file system
+// mounted at mount, for the cgroups listed in procCgroup (in the format of
+// /proc/self/cgroup).
+func cgroupMemoryLimit(mount string, procCgroup io.Reader) (ByteSize, error) {
+	return cgroupMemoryFile(mount, procCgroup, "memory.limit_in_bytes", "memory.max")
+}
+
+// cgroupMemoryFile reads a file of the memory controller, named v1File on
+// cgroup v1 and v2File on cgroup v2, like cgroupMemoryLimit.
+func cgroupMemoryFile(mount string, procCgroup io.Reader, v1File, v2File string) (ByteSize, error) {
+	var v1, v2 string
+	var isV1, isV2 bool
+
+	sc := bufio.NewScanner(procCgroup)
+	for sc.Scan() {
+		// hierarchy-ID:controller-list:cgroup-path
+		parts := strings.SplitN(sc.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			v2, isV2 = parts[2], true
+			continue
+		}
+		for _, c := range strings.Split(parts[1], ",") {
+			if c == "memory" {
+				v1, isV1 = parts[2], true
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return 0, err
+	}
+
+	// On hybrid hierarchies the memory controller is on cgroup v1.
+	switch {
+	case isV1:
+		return readCgroupLimit(filepath.Join(mount, "memory"), v1, v1File)
+	case isV2:
+		return readCgroupLimit(mount, v2, v2File)
+	}
+	return 0, fmt.Errorf("cgroup memory controller not found")
+}
+
+// readCgroupLimit reads file in the cgroup at path below mount. Inside a
+// container the cgroup is usually mounted as the root, so the file at the
+// mount root is used when the path does not exist.
+func readCgroupLimit(mount, path, file string) (ByteSize, error) {
+	data, err := os.ReadFile(filepath.Join(mount, path, file))
+	if os.IsNotExist(err) {
+		data, err = os.ReadFile(filepath.Join(mount, file))
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	s := strings.TrimSpace(string(data))
+	if s == "max" {
+		return Unlimited, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cgroup memory limit %q", s)
+	}
+	if n >= cgroupV1Unlimited {
+		return Unlimited, nil
+	}
+	return ByteSize(n), nil
+}
@@ -0,0 +1,108 @@
+package bytesizer
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeCgroupFile(t *testing.T, path, content string) {
+	t.Helper()
+	assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestCgroupMemoryLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		procCgroup string
+		files      map[string]string
+		expectErr  bool
+		expected   ByteSize
+	}{
+		{
+			name:       "v2",
+			procCgroup: "0::/system.slice/app.service\n",
+			files:      map[string]string{"system.slice/app.service/memory.max": "536870912\n"},
+			expected:   512 * MB,
+		},
+		{
+			name:       "v2 unlimited",
+			procCgroup: "0::/system.slice/app.service\n",
+			files:      map[string]string{"system.slice/app.service/memory.max": "max\n"},
+			expected:   Unlimited,
+		},
+		{
+			name:       "v2 container",
+			procCgroup: "0::/\n",
+			files:      map[string]string{"memory.max": "2147483648\n"},
+			expected:   2 * GB,
+		},
+		{
+			name:       "v2 namespaced path",
+			procCgroup: "0::/kubepods/pod1234\n",
+			files:      map[string]string{"memory.max": "1073741824\n"},
+			expected:   GB,
+		},
+		{
+			name:       "v1",
+			procCgroup: "5:cpu,cpuacct:/docker/abc\n4:memory:/docker/abc\n0::/\n",
+			files:      map[string]string{"memory/docker/abc/memory.limit_in_bytes": "268435456\n"},
+			expected:   256 * MB,
+		},
+		{
+			name:       "v1 unlimited",
+			procCgroup: "4:memory:/\n",
+			files:      map[string]string{"memory/memory.limit_in_bytes": "9223372036854771712\n"},
+			expected:   Unlimited,
+		},
+		{
+			name:       "No memory controller",
+			procCgroup: "5:cpu:/\n",
+			expectErr:  true,
+		},
+		{
+			name:       "Missing file",
+			procCgroup: "0::/\n",
+			expectErr:  true,
+		},
+		{
+			name:       "Invalid limit",
+			procCgroup: "0::/\n",
+			files:      map[string]string{"memory.max": "lots\n"},
+			expectErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mount := t.TempDir()
+			for name, content := range tt.files {
+				writeCgroupFile(t, filepath.Join(mount, name), content)
+			}
+
+			limit, err := cgroupMemoryLimit(mount, strings.NewReader(tt.procCgroup))
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, limit)
+			}
+		})
+	}
+}
+
+func TestCgroupMemoryLimitHost(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("cgroups are Linux only")
+	}
+	limit, err := CgroupMemoryLimit()
+	if err != nil {
+		t.Skipf("no cgroup memory limit available: %v", err)
+	}
+	assert.Greater(t, limit, ByteSize(0))
+}
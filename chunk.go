@@ -0,0 +1,74 @@
+package bytesizer
+
+import "io"
+
+// Chunker reads an io.Reader in successive chunks of a fixed size. The last
+// chunk is shorter when the data does not divide evenly. Its API follows
+// bufio.Scanner:
+//
+//	c := NewChunker(f, 8*MB)
+//	for c.Next() {
+//		upload(c.Offset(), c.Chunk())
+//	}
+//	if err := c.Err(); err != nil {
+//		return err
+//	}
+type Chunker struct {
+	r      io.Reader
+	buf    []byte
+	chunk  []byte
+	offset ByteSize
+	next   ByteSize
+	err    error
+}
+
+// NewChunker returns a Chunker reading chunks of size bytes from r.
+// It panics if size is not positive.
+func NewChunker(r io.Reader, size ByteSize) *Chunker {
+	if size <= 0 {
+		panic("bytesizer: non-positive chunk size")
+	}
+	return &Chunker{r: r, buf: make([]byte, size)}
+}
+
+// Next reads the next chunk, which is then available through Chunk. It
+// returns false when the input is exhausted or an error occurs.
+func (c *Chunker) Next() bool {
+	if c.err != nil {
+		return false
+	}
+	n, err := io.ReadFull(c.r, c.buf)
+	switch err {
+	case nil, io.ErrUnexpectedEOF:
+		c.chunk = c.buf[:n]
+		c.offset = c.next
+		c.next += ByteSize(n)
+		if err != nil {
+			c.err = io.EOF
+		}
+		return true
+	default:
+		c.chunk = nil
+		c.err = err
+		return false
+	}
+}
+
+// Chunk returns the current chunk. The underlying array is reused by the
+// next call to Next, so copy the data to keep it.
+func (c *Chunker) Chunk() []byte {
+	return c.chunk
+}
+
+// Offset returns the position of the current chunk in the input.
+func (c *Chunker) Offset() ByteSize {
+	return c.offset
+}
+
+// Err returns the first error other than io.EOF that occurred while reading.
+func (c *Chunker) Err() error {
+	if c.err == io.EOF {
+		return nil
+	}
+	return c.err
+}
@@ -0,0 +1,53 @@
+package bytesizer
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunker(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		chunk   ByteSize
+		lengths []int
+	}{
+		{"Even", int(4 * KB), KB, []int{1024, 1024, 1024, 1024}},
+		{"Short final chunk", int(2*KB) + 100, KB, []int{1024, 1024, 100}},
+		{"Smaller than a chunk", 100, KB, []int{100}},
+		{"Empty", 0, KB, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewChunker(iotest.HalfReader(strings.NewReader(strings.Repeat("x", tt.size))), tt.chunk)
+			var lengths []int
+			var offset ByteSize
+			for c.Next() {
+				assert.Equal(t, offset, c.Offset())
+				lengths = append(lengths, len(c.Chunk()))
+				offset += ByteSize(len(c.Chunk()))
+			}
+			assert.NoError(t, c.Err())
+			assert.Equal(t, tt.lengths, lengths)
+			assert.False(t, c.Next())
+		})
+	}
+}
+
+func TestChunkerError(t *testing.T) {
+	failure := errors.New("disk on fire")
+	c := NewChunker(io.MultiReader(strings.NewReader(strings.Repeat("x", int(KB))), iotest.ErrReader(failure)), KB)
+	assert.True(t, c.Next())
+	assert.False(t, c.Next())
+	assert.Equal(t, failure, c.Err())
+}
+
+func TestChunkerInvalidSize(t *testing.T) {
+	assert.Panics(t, func() { NewChunker(strings.NewReader(""), 0) })
+}
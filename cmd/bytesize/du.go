@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/iamlongalong/bytesizer"
+)
+
+const duUsage = `usage: bytesize du [flags] [path ...]
+
+Prints the size of the files and directories in each path, largest last
+with --sort, followed by the total of the path. Hard links are counted
+once. The default path is the current directory.
+
+Flags:
+`
+
+// excludeFlag collects --exclude patterns.
+type excludeFlag []string
+
+func (f *excludeFlag) String() string { return fmt.Sprint(*f) }
+
+func (f *excludeFlag) Set(s string) error {
+	*f = append(*f, s)
+	return nil
+}
+
+func runDu(args []string, stdout, stderr io.Writer) int {
+	var opts options
+	var excludes excludeFlag
+	var sorted, summarize, oneFS, follow bool
+	var top, workers int
+	fs := flag.NewFlagSet("bytesize du", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Var(&excludes, "exclude", "skip files matching the .gitignore-style `pattern`; may be repeated")
+	fs.BoolVar(&sorted, "sort", false, "sort entries by size, largest last")
+	fs.IntVar(&top, "top", 0, "print only the `n` largest entries, largest first")
+	fs.BoolVar(&summarize, "s", false, "print only the total of each path")
+	fs.BoolVar(&oneFS, "x", false, "skip directories on other filesystems")
+	fs.BoolVar(&follow, "L", false, "follow symbolic links")
+	fs.IntVar(&workers, "workers", 0, "read `n` directories at a time; the default is the number of CPUs, and network filesystems may benefit from more")
+	modeFlags(fs, &opts)
+	outputFlags(fs, &opts.output, &opts.template)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, duUsage)
+		fs.PrintDefaults()
+	}
+
+	paths, err := parseArgs(fs, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+	if top < 0 {
+		fmt.Fprintf(stderr, "bytesize du: invalid --top %d\n", top)
+		return 2
+	}
+	out, err := newOutput(stdout, opts.output, opts.template)
+	if err != nil {
+		fmt.Fprintf(stderr, "bytesize du: %v\n", err)
+		return 2
+	}
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	walkOpts := []bytesizer.WalkOption{bytesizer.CountHardlinksOnce(), bytesizer.WithExclude(excludes...)}
+	if oneFS {
+		walkOpts = append(walkOpts, bytesizer.OneFilesystem())
+	}
+	if follow {
+		walkOpts = append(walkOpts, bytesizer.WithSymlinks(bytesizer.FollowSymlinks))
+	}
+	if workers > 0 {
+		walkOpts = append(walkOpts, bytesizer.WithWorkers(workers))
+	}
+
+	status := 0
+	for _, path := range paths {
+		var entries []duRecord
+		walk := walkOpts
+		if !summarize {
+			walk = append(walk[:len(walk):len(walk)], bytesizer.WithEntrySizes(func(name string, size bytesizer.ByteSize) {
+				entries = append(entries, newDuRecord(filepath.Join(path, name), size, opts))
+			}))
+		}
+
+		total, err := bytesizer.DirSize(context.Background(), path, walk...)
+		var werr *bytesizer.WalkError
+		switch {
+		case errors.As(err, &werr):
+			for _, err := range werr.Errors {
+				fmt.Fprintf(stderr, "bytesize du: %v\n", err)
+			}
+			status = 1
+		case err != nil:
+			fmt.Fprintf(stderr, "bytesize du: %v\n", err)
+			status = 1
+			continue
+		}
+
+		switch {
+		case top > 0:
+			sort.SliceStable(entries, func(i, j int) bool { return entries[i].Bytes > entries[j].Bytes })
+			if len(entries) > top {
+				entries = entries[:top]
+			}
+		case sorted:
+			sort.SliceStable(entries, func(i, j int) bool { return entries[i].Bytes < entries[j].Bytes })
+		}
+		for _, e := range append(entries, newDuRecord(path, total, opts)) {
+			if err := out.write(e); err != nil {
+				fmt.Fprintf(stderr, "bytesize du: %v\n", err)
+				return 1
+			}
+		}
+	}
+	return status
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDu(t *testing.T) {
+	dir := t.TempDir()
+	for name, size := range map[string]int{
+		"a/x.bin":   3 * 1024,
+		"b/c/y.bin": 10 * 1024,
+		"b/z.log":   1024,
+		"f.log":     100,
+	} {
+		p := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		assert.NoError(t, os.WriteFile(p, make([]byte, size), 0o644))
+	}
+	lines := func(lines ...string) string {
+		return strings.ReplaceAll(strings.Join(lines, "\n")+"\n", "DIR", dir)
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		status int
+		stdout string
+	}{
+		{
+			name:   "Entries",
+			args:   []string{dir},
+			stdout: lines("3KB\tDIR/a", "11KB\tDIR/b", "100B\tDIR/f.log", "14.1KB\tDIR"),
+		},
+		{
+			name:   "Sort",
+			args:   []string{"--sort", dir},
+			stdout: lines("100B\tDIR/f.log", "3KB\tDIR/a", "11KB\tDIR/b", "14.1KB\tDIR"),
+		},
+		{
+			name:   "Top",
+			args:   []string{dir, "--top", "2", "--iec"},
+			stdout: lines("11KiB\tDIR/b", "3KiB\tDIR/a", "14.1KiB\tDIR"),
+		},
+		{
+			name:   "Exclude",
+			args:   []string{"--exclude", "*.log", "--exclude", "/a", dir},
+			stdout: lines("10KB\tDIR/b", "10KB\tDIR"),
+		},
+		{
+			name:   "Summarize",
+			args:   []string{"-s", "--si", dir, filepath.Join(dir, "f.log")},
+			stdout: lines("14.44KB\tDIR", "100B\tDIR/f.log"),
+		},
+		{
+			name:   "Missing path",
+			args:   []string{"-s", filepath.Join(dir, "missing"), dir},
+			status: 1,
+			stdout: lines("14.1KB\tDIR"),
+		},
+		{
+			name:   "JSON",
+			args:   []string{"-s", "--output", "json", dir},
+			stdout: lines(`{"path":"DIR","bytes":14436,"value":"14.1KB"}`),
+		},
+		{
+			name:   "CSV",
+			args:   []string{"--top", "1", "--output", "csv", dir},
+			stdout: lines("path,bytes,value", "DIR/b,11264,11KB", "DIR,14436,14.1KB"),
+		},
+		{
+			name:   "Template",
+			args:   []string{"-s", "--output", "template", "--template", `{{.Bytes}} {{bytesize .Bytes}}`, dir},
+			stdout: lines("14436 14.10KB"),
+		},
+		{
+			name:   "Bad top",
+			args:   []string{"--top", "-1"},
+			status: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := run(append([]string{"du"}, tt.args...), nil, &stdout, &stderr)
+			assert.Equal(t, tt.status, status, stderr.String())
+			assert.Equal(t, tt.stdout, stdout.String())
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const humanizeUsage = `usage: bytesize humanize [flags]
+
+Reads text from standard input and replaces byte counts with humanized
+sizes, in the given fields or wherever a regular expression matches.
+Fields which are not plain numbers are left as they are.
+
+Flags:
+`
+
+// countPattern matches the plain numbers replaced by humanize.
+var countPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// fieldPattern matches fields separated by whitespace.
+var fieldPattern = regexp.MustCompile(`\S+`)
+
+func runHumanize(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var opts options
+	var fieldList, delimiter, pattern string
+	var header int
+	fs := flag.NewFlagSet("bytesize humanize", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&fieldList, "field", "1", "humanize the `fields` in this comma-separated list, counted from 1")
+	fs.StringVar(&delimiter, "delimiter", "", "fields are separated by `delim` instead of whitespace")
+	fs.StringVar(&pattern, "regex", "", "humanize the matches of `re`, or of its first group, instead of fields")
+	fs.IntVar(&header, "header", 0, "leave the first `n` lines as they are")
+	modeFlags(fs, &opts)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, humanizeUsage)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); errors.Is(err, flag.ErrHelp) {
+		return 0
+	} else if err != nil {
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "bytesize humanize: unexpected argument %q\n", fs.Arg(0))
+		return 2
+	}
+
+	h := humanizer{opts: opts, delimiter: delimiter}
+	if pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			fmt.Fprintf(stderr, "bytesize humanize: %v\n", err)
+			return 2
+		}
+		h.re = re
+	} else {
+		fields, err := parseFieldList(fieldList)
+		if err != nil {
+			fmt.Fprintf(stderr, "bytesize humanize: %v\n", err)
+			return 2
+		}
+		h.fields = fields
+	}
+
+	sc := bufio.NewScanner(stdin)
+	sc.Buffer(nil, 1024*1024)
+	w := bufio.NewWriter(stdout)
+	for line := 0; sc.Scan(); line++ {
+		text := sc.Text()
+		if line >= header {
+			text = h.line(text)
+		}
+		w.WriteString(text)
+		w.WriteByte('\n')
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(stderr, "bytesize humanize: %v\n", err)
+		return 1
+	}
+	if err := sc.Err(); err != nil {
+		fmt.Fprintf(stderr, "bytesize humanize: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parseFieldList parses a list of field numbers like "2,5".
+func parseFieldList(s string) (map[int]bool, error) {
+	fields := make(map[int]bool)
+	for _, f := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid field %q", f)
+		}
+		fields[n] = true
+	}
+	return fields, nil
+}
+
+// humanizer replaces byte counts in lines of text.
+type humanizer struct {
+	opts      options
+	fields    map[int]bool
+	delimiter string
+	re        *regexp.Regexp
+}
+
+func (h humanizer) line(s string) string {
+	switch {
+	case h.re != nil:
+		return h.replaceMatches(s)
+	case h.delimiter != "":
+		parts := strings.Split(s, h.delimiter)
+		for i, p := range parts {
+			if h.fields[i+1] {
+				parts[i] = h.count(p)
+			}
+		}
+		return strings.Join(parts, h.delimiter)
+	}
+
+	// Replace whitespace-separated fields in place, keeping the spacing.
+	var b strings.Builder
+	last := 0
+	for i, loc := range fieldPattern.FindAllStringIndex(s, -1) {
+		if !h.fields[i+1] {
+			continue
+		}
+		b.WriteString(s[last:loc[0]])
+		b.WriteString(h.count(s[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+func (h humanizer) replaceMatches(s string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range h.re.FindAllStringSubmatchIndex(s, -1) {
+		start, end := loc[0], loc[1]
+		if len(loc) >= 4 && loc[2] >= 0 {
+			start, end = loc[2], loc[3] // first group
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(h.count(s[start:end]))
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// count humanizes s if it is a plain number of bytes.
+func (h humanizer) count(s string) string {
+	if !countPattern.MatchString(s) {
+		return s
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return s
+	}
+	return humanize(v, h.opts)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHumanize(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		stdin  string
+		status int
+		stdout string
+	}{
+		{
+			name:   "Default first field",
+			stdin:  "1536 a.bin\n2048 b.bin\n",
+			stdout: "1.5KB a.bin\n2KB b.bin\n",
+		},
+		{
+			name:   "Whitespace fields keep spacing",
+			args:   []string{"--field", "5", "--header", "1"},
+			stdin:  "total 8\n-rw-r--r--  1 root root  1073741824 Jan  1 00:00 big\n",
+			stdout: "total 8\n-rw-r--r--  1 root root  1GB Jan  1 00:00 big\n",
+		},
+		{
+			name:   "CSV",
+			args:   []string{"--delimiter", ",", "--field", "2,3", "--header", "1", "--iec"},
+			stdin:  "name,size,used\nvol1,1099511627776,536870912\nvol2,n/a,100\n",
+			stdout: "name,size,used\nvol1,1TiB,512MiB\nvol2,n/a,100B\n",
+		},
+		{
+			name:   "Regex",
+			args:   []string{"--regex", `bytes=(\d+)`, "--si"},
+			stdin:  "GET /a bytes=1500000 status=200\nGET /b status=404\n",
+			stdout: "GET /a bytes=1.5MB status=200\nGET /b status=404\n",
+		},
+		{
+			name:   "Regex whole match",
+			args:   []string{"--regex", `\b\d{4,}\b`},
+			stdin:  "sent 4096 and 10240 in 3 parts\n",
+			stdout: "sent 4KB and 10KB in 3 parts\n",
+		},
+		{
+			name:   "Bad field",
+			args:   []string{"--field", "0"},
+			status: 2,
+		},
+		{
+			name:   "Bad regex",
+			args:   []string{"--regex", "("},
+			status: 2,
+		},
+		{
+			name:   "Extra argument",
+			args:   []string{"file.txt"},
+			status: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"humanize"}, tt.args...)
+			status := run(args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			assert.Equal(t, tt.status, status, stderr.String())
+			assert.Equal(t, tt.stdout, stdout.String())
+		})
+	}
+}
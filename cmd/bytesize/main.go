@@ -0,0 +1,323 @@
+// Command bytesize converts sizes between units, for use in shell scripts.
+//
+//	$ bytesize 1.5GiB --to MB
+//	1536
+//	$ bytesize --si 1.5GiB --to MB
+//	1610.612736
+//	$ du -sb /var/log | cut -f1 | bytesize --iec
+//	1.21GiB
+//
+// Sizes are read from the arguments, or from standard input separated by
+// spaces or newlines, and one result is printed per line. Without --to,
+// results are humanized.
+//
+// The humanize subcommand replaces byte counts in text, like numfmt:
+//
+//	$ ls -l | bytesize humanize --field 5
+//
+// The du subcommand prints directory usage:
+//
+//	$ bytesize du --top 10 --exclude .git/ ~/src
+//
+// Results can be printed as JSON, CSV or with a Go template using the
+// package's template functions:
+//
+//	$ bytesize --output template --template '{{.Input}} is {{formatSize .Bytes "KB"}}' 1.5MiB
+//	1.5MiB is 1536KB
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/iamlongalong/bytesizer"
+)
+
+const usage = `usage: bytesize [flags] [size ...]
+       bytesize humanize [flags]
+       bytesize du [flags] [path ...]
+
+Converts sizes such as 1.5GiB or 512MB. Without arguments, sizes are read
+from standard input. See "bytesize humanize -h" and "bytesize du -h"
+for the other modes.
+
+Flags:
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// options are the command line flags.
+type options struct {
+	to       string
+	si       bool
+	iec      bool
+	bits     bool
+	output   string
+	template string
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "humanize":
+			return runHumanize(args[1:], stdin, stdout, stderr)
+		case "du":
+			return runDu(args[1:], stdout, stderr)
+		}
+	}
+
+	var opts options
+	fs := flag.NewFlagSet("bytesize", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.to, "to", "", "print a plain number in `unit`, such as B, MB or MiB")
+	modeFlags(fs, &opts)
+	outputFlags(fs, &opts.output, &opts.template)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		fs.PrintDefaults()
+	}
+
+	sizes, err := parseArgs(fs, args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+	out, err := newOutput(stdout, opts.output, opts.template)
+	if err != nil {
+		fmt.Fprintf(stderr, "bytesize: %v\n", err)
+		return 2
+	}
+
+	var to float64
+	if opts.to != "" {
+		u, ok := lookupUnit(opts.to, opts)
+		if !ok {
+			fmt.Fprintf(stderr, "bytesize: unknown unit %q\n", opts.to)
+			return 2
+		}
+		to = u.size
+	}
+
+	next := func() (string, bool) {
+		if len(sizes) == 0 {
+			return "", false
+		}
+		s := sizes[0]
+		sizes = sizes[1:]
+		return s, true
+	}
+	if len(sizes) == 0 {
+		sc := bufio.NewScanner(stdin)
+		sc.Split(bufio.ScanWords)
+		next = func() (string, bool) {
+			if !sc.Scan() {
+				return "", false
+			}
+			return sc.Text(), true
+		}
+	}
+
+	status := 0
+	for s, ok := next(); ok; s, ok = next() {
+		if size, ok := sentinel(s); ok {
+			// Unlimited and Unknown are not numbers of bytes to convert.
+			if to != 0 || opts.output != "text" {
+				fmt.Fprintf(stderr, "bytesize: %q has no number of bytes\n", s)
+				status = 1
+				continue
+			}
+			if err := out.write(conversion{Input: s, Value: size.String()}); err != nil {
+				fmt.Fprintf(stderr, "bytesize: %v\n", err)
+				return 1
+			}
+			continue
+		}
+		v, err := parse(s, opts)
+		if err != nil {
+			fmt.Fprintf(stderr, "bytesize: %v\n", err)
+			status = 1
+			continue
+		}
+		c := conversion{Input: s, Bytes: v, Value: humanize(v, opts)}
+		if to != 0 {
+			c.Value = strconv.FormatFloat(v/to, 'f', -1, 64)
+		}
+		if err := out.write(c); err != nil {
+			fmt.Fprintf(stderr, "bytesize: %v\n", err)
+			return 1
+		}
+	}
+	return status
+}
+
+// modeFlags adds the flags selecting units to fs.
+func modeFlags(fs *flag.FlagSet, opts *options) {
+	fs.BoolVar(&opts.si, "si", false, "KB, MB, GB, TB and PB are powers of 1000 instead of 1024")
+	fs.BoolVar(&opts.iec, "iec", false, "humanize with KiB, MiB, GiB, TiB and PiB")
+	fs.BoolVar(&opts.bits, "bits", false, "units ending in a lowercase b are bits, and results are in bits")
+}
+
+// parseArgs parses flags given before or after the sizes, and returns the
+// sizes.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var sizes []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return sizes, nil
+		}
+		sizes = append(sizes, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// unit is a unit of the command line, in bytes.
+type unit struct {
+	name string
+	size float64
+}
+
+var prefixes = []string{"K", "M", "G", "T", "P"}
+
+// humanizeUnits returns the units used to humanize results, largest first.
+func humanizeUnits(opts options) []unit {
+	base, suffix := 1024.0, "B"
+	switch {
+	case opts.si:
+		base = 1000
+	case opts.iec:
+		suffix = "iB"
+	}
+	if opts.bits {
+		suffix = strings.Replace(suffix, "B", "b", 1)
+	}
+
+	one := 1.0
+	if opts.bits {
+		one = 1.0 / 8
+	}
+	units := []unit{{suffix[len(suffix)-1:], one}}
+	for i, p := range prefixes {
+		units = append(units, unit{p + suffix, one * math.Pow(base, float64(i+1))})
+	}
+	for i, j := 0, len(units)-1; i < j; i, j = i+1, j-1 {
+		units[i], units[j] = units[j], units[i]
+	}
+	return units
+}
+
+// lookupUnit returns the unit with the exact name.
+func lookupUnit(name string, opts options) (unit, bool) {
+	base := 1024.0
+	if opts.si {
+		base = 1000
+	}
+	size := 1.0
+	switch {
+	case name == "B":
+	case name == "b" && opts.bits:
+		size = 1.0 / 8
+	default:
+		n := name
+		if opts.bits {
+			n = strings.TrimSuffix(n, "it") // Mbit
+		}
+		if len(n) < 2 {
+			return unit{}, false
+		}
+		prefix, rest := n[:1], n[1:]
+		if prefix == "k" && opts.si && (rest == "B" || rest == "b") {
+			prefix = "K" // SI kilo
+		}
+		exp := strings.Index("KMGTP", prefix) + 1
+		switch {
+		case exp == 0:
+			return unit{}, false
+		case rest == "iB":
+			size = math.Pow(1024, float64(exp))
+		case rest == "ib" && opts.bits:
+			size = math.Pow(1024, float64(exp)) / 8
+		case rest == "B":
+			size = math.Pow(base, float64(exp))
+		case rest == "b" && opts.bits:
+			size = math.Pow(base, float64(exp)) / 8
+		default:
+			return unit{}, false
+		}
+	}
+	return unit{name, size}, true
+}
+
+// sentinel returns the sentinel s stands for, such as bytesizer.Unlimited
+// for "unlimited".
+func sentinel(s string) (bytesizer.ByteSize, bool) {
+	switch {
+	case strings.EqualFold(s, bytesizer.Unlimited.String()):
+		return bytesizer.Unlimited, true
+	case s == bytesizer.Unknown.String():
+		return bytesizer.Unknown, true
+	}
+	return 0, false
+}
+
+// parse parses a size into bytes. With --si or --bits, units are looked up
+// on the command line's own table; otherwise bytesizer.Parse is used.
+func parse(s string, opts options) (float64, error) {
+	if !opts.si && !opts.bits {
+		v, err := bytesizer.Parse(s)
+		if err != nil {
+			return 0, err
+		}
+		return float64(v), nil
+	}
+
+	num, name, err := bytesizer.SplitSize(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	if name == "" {
+		name = "B"
+	}
+	u, ok := lookupUnit(name, opts)
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q", s, name)
+	}
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	// The same range as bytesizer.Parse, keeping fractions of a byte.
+	if _, err := bytesizer.FromFloat(v * u.size); err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", s, err)
+	}
+	return v * u.size, nil
+}
+
+// humanize formats bytes with the largest unit that keeps the number at
+// least 1, with up to two decimals.
+func humanize(v float64, opts options) string {
+	units := humanizeUnits(opts)
+	u := units[len(units)-1]
+	for _, c := range units {
+		if math.Abs(v) >= c.size {
+			u = c
+			break
+		}
+	}
+	n := strconv.FormatFloat(v/u.size, 'f', 2, 64)
+	n = strings.TrimRight(strings.TrimRight(n, "0"), ".")
+	return n + u.name
+}
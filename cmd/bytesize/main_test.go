@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		stdin  string
+		status int
+		stdout string
+		stderr string
+	}{
+		{"Humanize", []string{"1536MB", "2048"}, "", 0, "1.5GB\n2KB\n", ""},
+		{"To", []string{"1.5GiB", "--to", "MB"}, "", 0, "1536\n", ""},
+		{"To bytes", []string{"--to=B", "1.5KB"}, "", 0, "1536\n", ""},
+		{"To SI", []string{"--si", "1.5GiB", "--to", "MB"}, "", 0, "1610.612736\n", ""},
+		{"SI humanize", []string{"--si", "1610612736"}, "", 0, "1.61GB\n", ""},
+		{"SI kilo", []string{"--si", "1.5kB", "--to", "B"}, "", 0, "1500\n", ""},
+		{"SI exponent", []string{"--si", "1e3KB", "--to", "B"}, "", 0, "1000000\n", ""},
+		{"Invalid SI number", []string{"--si", "1.2.3KB"}, "", 1, "", "invalid size \"1.2.3KB\""},
+		{"SI out of range", []string{"--si", "99999999999999999999PB"}, "", 1, "", "size out of range"},
+		{"Bits out of range", []string{"--bits", "99999999999999999999Pb"}, "", 1, "", "size out of range"},
+		{"IEC", []string{"--iec", "1536MB", "100"}, "", 0, "1.5GiB\n100B\n", ""},
+		{"Bits to bytes", []string{"--bits", "1Gb", "--to", "MB"}, "", 0, "128\n", ""},
+		{"Bytes to bits", []string{"--bits", "--si", "1MB", "--to", "Mbit"}, "", 0, "8\n", ""},
+		{"Bits humanize", []string{"--bits", "--si", "12.5MB"}, "", 0, "100Mb\n", ""},
+		{"Unlimited", []string{"unlimited", "1KB"}, "", 0, "unlimited\n1KB\n", ""},
+		{"Unknown", []string{"\u2013"}, "", 0, "\u2013\n", ""},
+		{"Unlimited to", []string{"--to", "B", "Unlimited", "1KB"}, "", 1, "1024\n", `"Unlimited" has no number of bytes`},
+		{"Unknown to", []string{"\u2013", "--to", "B"}, "", 1, "", "\"\u2013\" has no number of bytes"},
+		{"Unlimited JSON", []string{"--output", "json", "unlimited"}, "", 1, "", `"unlimited" has no number of bytes`},
+		{"Stdin", nil, "1KB\n2KB 3KB\n", 0, "1KB\n2KB\n3KB\n", ""},
+		{"Invalid size", []string{"1KB", "10XB", "2KB"}, "", 1, "1KB\n2KB\n", "invalid size \"10XB\""},
+		{"Invalid SI unit", []string{"--si", "10XB"}, "", 1, "", "unknown unit \"XB\""},
+		{"Unknown unit", []string{"--to", "kB", "1KB"}, "", 2, "", "unknown unit \"kB\""},
+		{"Bits need --bits", []string{"--to", "Mb", "1KB"}, "", 2, "", "unknown unit \"Mb\""},
+		{"JSON", []string{"--output", "json", "1.5KiB", "--to", "KB"}, "", 0, `{"input":"1.5KiB","bytes":1536,"value":"1.5"}` + "\n", ""},
+		{"CSV", []string{"--output", "csv"}, "1KB 1,5MB\n", 1, "input,bytes,value\n1KB,1024,1KB\n", `invalid size "1,5MB"`},
+		{"Template without template output", []string{"--output", "csv", "--template", "x"}, "", 2, "", "--template needs --output template"},
+		{"Template", []string{"--output", "template", "--template", `{{.Input}}: {{formatSize .Bytes "KB"}}`, "2MB"}, "", 0, "2MB: 2048KB\n", ""},
+		{"Template missing", []string{"--output", "template", "1KB"}, "", 2, "", "--output template needs --template"},
+		{"Template error", []string{"--output", "template", "--template", "{{", "1KB"}, "", 2, "", "unclosed action"},
+		{"Unknown output", []string{"--output", "yaml", "1KB"}, "", 2, "", `unknown output format "yaml"`},
+		{"Bad flag", []string{"--nope"}, "", 2, "", "usage: bytesize"},
+		{"Help", []string{"-h"}, "", 0, "", "usage: bytesize"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.stdout, stdout.String())
+			if tt.stderr == "" {
+				assert.Empty(t, stderr.String())
+			} else {
+				assert.Contains(t, stderr.String(), tt.stderr)
+			}
+		})
+	}
+}
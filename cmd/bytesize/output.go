@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"text/template"
+
+	"github.com/iamlongalong/bytesizer"
+)
+
+// record is a result printed by the command.
+type record interface {
+	// text returns the record in the default output.
+	text() string
+	// csvHeader returns the names of the CSV columns.
+	csvHeader() []string
+	// csvRow returns the record as CSV columns.
+	csvRow() []string
+}
+
+// conversion is a size converted from the command line or standard input.
+type conversion struct {
+	Input string  `json:"input"`
+	Bytes float64 `json:"bytes"`
+	Value string  `json:"value"`
+}
+
+func (c conversion) text() string { return c.Value }
+
+func (c conversion) csvHeader() []string { return []string{"input", "bytes", "value"} }
+
+func (c conversion) csvRow() []string {
+	return []string{c.Input, strconv.FormatFloat(c.Bytes, 'f', -1, 64), c.Value}
+}
+
+// duRecord is a line of du output.
+type duRecord struct {
+	Path  string `json:"path"`
+	Bytes int64  `json:"bytes"`
+	Value string `json:"value"`
+}
+
+func newDuRecord(path string, size bytesizer.ByteSize, opts options) duRecord {
+	return duRecord{Path: path, Bytes: int64(size), Value: humanize(float64(size), opts)}
+}
+
+func (u duRecord) text() string { return u.Value + "\t" + u.Path }
+
+func (u duRecord) csvHeader() []string { return []string{"path", "bytes", "value"} }
+
+func (u duRecord) csvRow() []string {
+	return []string{u.Path, strconv.FormatInt(u.Bytes, 10), u.Value}
+}
+
+// outputFlags adds the flags selecting the output format to fs.
+func outputFlags(fs *flag.FlagSet, format, text *string) {
+	fs.StringVar(format, "output", "text", "output `format`: text, json (one object per line), csv or template")
+	fs.StringVar(text, "template", "", "Go template `text` executed for each result with --output template, with the bytesizer template functions")
+}
+
+// output writes records in the chosen format.
+type output struct {
+	w      io.Writer
+	format string
+	tmpl   *template.Template
+	csv    *csv.Writer
+	header bool // whether the CSV header was written
+}
+
+// newOutput returns an output writing to w in format. text is the template
+// of the template format.
+func newOutput(w io.Writer, format, text string) (*output, error) {
+	o := &output{w: w, format: format}
+	switch format {
+	case "text", "json":
+	case "csv":
+		o.csv = csv.NewWriter(w)
+	case "template":
+		if text == "" {
+			return nil, fmt.Errorf("--output template needs --template")
+		}
+		t, err := template.New("output").Funcs(bytesizer.TemplateFuncs()).Parse(text)
+		if err != nil {
+			return nil, err
+		}
+		o.tmpl = t
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+	if text != "" && format != "template" {
+		return nil, fmt.Errorf("--template needs --output template")
+	}
+	return o, nil
+}
+
+// write writes r.
+func (o *output) write(r record) error {
+	switch o.format {
+	case "json":
+		return json.NewEncoder(o.w).Encode(r)
+	case "csv":
+		if !o.header {
+			o.header = true
+			if err := o.csv.Write(r.csvHeader()); err != nil {
+				return err
+			}
+		}
+		if err := o.csv.Write(r.csvRow()); err != nil {
+			return err
+		}
+		o.csv.Flush()
+		return o.csv.Error()
+	case "template":
+		if err := o.tmpl.Execute(o.w, r); err != nil {
+			return err
+		}
+	default:
+		if _, err := io.WriteString(o.w, r.text()); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(o.w, "\n")
+	return err
+}
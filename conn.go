@@ -0,0 +1,66 @@
+package bytesizer
+
+import (
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// Conn is a net.Conn that counts the bytes read and written through it.
+// The counters may be read while the connection is in use.
+type Conn struct {
+	net.Conn
+	start   time.Time
+	read    atomic.Int64
+	written atomic.Int64
+}
+
+// WrapConn returns a Conn counting the traffic on c.
+func WrapConn(c net.Conn) *Conn {
+	return &Conn{Conn: c, start: time.Now()}
+}
+
+// Read reads from the connection and adds the bytes read to the count.
+func (c *Conn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.read.Add(int64(n))
+	return n, err
+}
+
+// Write writes to the connection and adds the bytes written to the count.
+func (c *Conn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	c.written.Add(int64(n))
+	return n, err
+}
+
+// BytesRead returns the number of bytes read so far.
+func (c *Conn) BytesRead() ByteSize {
+	return ByteSize(c.read.Load())
+}
+
+// BytesWritten returns the number of bytes written so far.
+func (c *Conn) BytesWritten() ByteSize {
+	return ByteSize(c.written.Load())
+}
+
+// ConnStats is a snapshot of the traffic on a Conn.
+type ConnStats struct {
+	Read      ByteSize
+	Written   ByteSize
+	ReadRate  Rate // average since WrapConn
+	WriteRate Rate // average since WrapConn
+	Elapsed   time.Duration
+}
+
+// Stats returns a snapshot of the traffic on c.
+func (c *Conn) Stats() ConnStats {
+	s := ConnStats{
+		Read:    c.BytesRead(),
+		Written: c.BytesWritten(),
+		Elapsed: time.Since(c.start),
+	}
+	s.ReadRate = RateFor(s.Read, s.Elapsed)
+	s.WriteRate = RateFor(s.Written, s.Elapsed)
+	return s
+}
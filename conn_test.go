@@ -0,0 +1,42 @@
+package bytesizer
+
+import (
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapConn(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+
+	c := WrapConn(client)
+	defer c.Close()
+
+	go func() {
+		buf := make([]byte, 100)
+		_, _ = io.ReadFull(server, buf)
+		_, _ = server.Write(make([]byte, 2*KB))
+	}()
+
+	n, err := c.Write(make([]byte, 100))
+	assert.NoError(t, err)
+	assert.Equal(t, 100, n)
+
+	_, err = io.ReadFull(c, make([]byte, 2*KB))
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2*KB, c.BytesRead())
+	assert.Equal(t, ByteSize(100), c.BytesWritten())
+
+	stats := c.Stats()
+	assert.Equal(t, 2*KB, stats.Read)
+	assert.Equal(t, ByteSize(100), stats.Written)
+	assert.Greater(t, stats.Elapsed.Nanoseconds(), int64(0))
+	assert.Greater(t, float64(stats.ReadRate), float64(stats.WriteRate))
+
+	var _ net.Conn = c
+	assert.Equal(t, client.LocalAddr(), c.LocalAddr())
+}
@@ -0,0 +1,177 @@
+package bytesizer
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultPodInfoDir is where ReadContainerResources looks for Kubernetes
+// downward API files, following the Kubernetes documentation examples.
+const DefaultPodInfoDir = "/etc/podinfo"
+
+// Downward API file names read by ReadDownwardAPI. Mount them with a
+// resourceFieldRef each, e.g. path "mem_limit" for limits.memory.
+const (
+	PodInfoMemoryRequest  = "mem_request"
+	PodInfoMemoryLimit    = "mem_limit"
+	PodInfoStorageRequest = "ephemeral_storage_request"
+	PodInfoStorageLimit   = "ephemeral_storage_limit"
+)
+
+// ContainerResources are the memory and ephemeral storage a container was
+// given. A zero value is unknown, and a limit of Unlimited means no limit.
+type ContainerResources struct {
+	MemoryRequest  ByteSize
+	MemoryLimit    ByteSize
+	StorageRequest ByteSize
+	StorageLimit   ByteSize
+}
+
+// ReadContainerResources answers "how much was I given?" from the
+// Kubernetes downward API files in DefaultPodInfoDir, if present, and the
+// cgroup of the process, which Docker and Kubernetes both set. When both
+// report a memory limit, the smaller one wins. It returns an error only if
+// neither source can be read.
+func ReadContainerResources() (ContainerResources, error) {
+	res, podErr := ReadDownwardAPI(DefaultPodInfoDir)
+
+	cg, cgErr := CgroupResources()
+	if podErr != nil && cgErr != nil {
+		return ContainerResources{}, cgErr
+	}
+	if cg.MemoryLimit != 0 && (res.MemoryLimit == 0 || cg.MemoryLimit < res.MemoryLimit) {
+		res.MemoryLimit = cg.MemoryLimit
+	}
+	if res.MemoryRequest == 0 {
+		res.MemoryRequest = cg.MemoryRequest
+	}
+	return res, nil
+}
+
+// ReadDownwardAPI reads resource requests and limits from Kubernetes
+// downward API files in dir, named PodInfoMemoryRequest and so on. Missing
+// files are left zero. Values are Kubernetes quantities, so files must use
+// the default divisor of 1.
+//
+// Without a limit on the container, Kubernetes reports the allocatable
+// resources of the node as the limit.
+func ReadDownwardAPI(dir string) (ContainerResources, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return ContainerResources{}, err
+	}
+
+	var res ContainerResources
+	for _, f := range []struct {
+		name string
+		dst  *ByteSize
+	}{
+		{PodInfoMemoryRequest, &res.MemoryRequest},
+		{PodInfoMemoryLimit, &res.MemoryLimit},
+		{PodInfoStorageRequest, &res.StorageRequest},
+		{PodInfoStorageLimit, &res.StorageLimit},
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, f.name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return ContainerResources{}, err
+		}
+		v, err := ParseQuantity(strings.TrimSpace(string(data)))
+		if err != nil {
+			return ContainerResources{}, fmt.Errorf("%s: %w", f.name, err)
+		}
+		*f.dst = v
+	}
+	return res, nil
+}
+
+// CgroupResources returns the memory limit of the cgroup of the process,
+// like CgroupMemoryLimit, and its memory reservation as the request:
+// memory.low on cgroup v2 or memory.soft_limit_in_bytes on cgroup v1, as
+// set by "docker run --memory-reservation". Storage is not reported.
+func CgroupResources() (ContainerResources, error) {
+	f, err := os.Open("/proc/self/cgroup")
+	if err != nil {
+		return ContainerResources{}, err
+	}
+	defer f.Close()
+	return cgroupResources("/sys/fs/cgroup", f)
+}
+
+func cgroupResources(mount string, procCgroup io.ReadSeeker) (ContainerResources, error) {
+	limit, err := cgroupMemoryLimit(mount, procCgroup)
+	if err != nil {
+		return ContainerResources{}, err
+	}
+	if _, err := procCgroup.Seek(0, io.SeekStart); err != nil {
+		return ContainerResources{}, err
+	}
+	request, err := cgroupMemoryFile(mount, procCgroup, "memory.soft_limit_in_bytes", "memory.low")
+	if err != nil && !os.IsNotExist(err) {
+		return ContainerResources{}, err
+	}
+	if request == Unlimited {
+		request = 0 // no reservation
+	}
+	return ContainerResources{MemoryRequest: request, MemoryLimit: limit}, nil
+}
+
+// quantitySuffixes are the suffixes of Kubernetes quantities.
+var quantitySuffixes = map[string]float64{
+	"":   1,
+	"m":  1e-3,
+	"k":  1e3,
+	"M":  1e6,
+	"G":  1e9,
+	"T":  1e12,
+	"P":  1e15,
+	"E":  1e18,
+	"Ki": 1 << 10,
+	"Mi": 1 << 20,
+	"Gi": 1 << 30,
+	"Ti": 1 << 40,
+	"Pi": 1 << 50,
+	"Ei": 1 << 60,
+}
+
+// ParseQuantity parses a Kubernetes resource quantity such as "512Mi",
+// "1G", "129e6" or "128974848" into a ByteSize. Fractional bytes are
+// rounded up, as Kubernetes does.
+func ParseQuantity(s string) (ByteSize, error) {
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+		i++
+	}
+	num, suffix := s[:i], s[i:]
+
+	mult, ok := quantitySuffixes[suffix]
+	if !ok && len(suffix) > 1 && (suffix[0] == 'e' || suffix[0] == 'E') {
+		// decimal exponent, e.g. "129e6"
+		exp, err := strconv.Atoi(suffix[1:])
+		if err == nil {
+			mult, ok = math.Pow10(exp), true
+		}
+	}
+	if !ok {
+		return 0, fmt.Errorf("invalid quantity suffix in %q", s)
+	}
+
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quantity %q", s)
+	}
+	v = math.Ceil(v * mult)
+	if v >= math.MaxInt64 || v <= math.MinInt64 {
+		return 0, fmt.Errorf("quantity out of range: %q", s)
+	}
+	return ByteSize(v), nil
+}
@@ -0,0 +1,108 @@
+package bytesizer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expectErr bool
+		expected  ByteSize
+	}{
+		{"Bytes", "128974848", false, 128974848},
+		{"Binary", "512Mi", false, 512 * MB},
+		{"Binary fraction", "1.5Gi", false, ByteSize(1.5 * float64(GB))},
+		{"Decimal", "1G", false, 1000000000},
+		{"Kilo", "10k", false, 10000},
+		{"Exponent", "129e6", false, 129000000},
+		{"Exa", "1E", false, 1000000000000000000},
+		{"Milli rounds up", "1500m", false, 2},
+		{"Plus sign", "+1Ki", false, KB},
+		{"Empty", "", true, 0},
+		{"Unknown suffix", "1KB", true, 0},
+		{"Suffix only", "Mi", true, 0},
+		{"Out of range", "100Ei", true, 0},
+		{"Unknown", "-9223372036854775808", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := ParseQuantity(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, v)
+			}
+		})
+	}
+}
+
+func TestReadDownwardAPI(t *testing.T) {
+	dir := t.TempDir()
+	writeCgroupFile(t, filepath.Join(dir, PodInfoMemoryRequest), "268435456")
+	writeCgroupFile(t, filepath.Join(dir, PodInfoMemoryLimit), "536870912\n")
+	writeCgroupFile(t, filepath.Join(dir, PodInfoStorageLimit), "2Gi")
+
+	res, err := ReadDownwardAPI(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, ContainerResources{MemoryRequest: 256 * MB, MemoryLimit: 512 * MB, StorageLimit: 2 * GB}, res)
+
+	writeCgroupFile(t, filepath.Join(dir, PodInfoStorageRequest), "lots")
+	_, err = ReadDownwardAPI(dir)
+	assert.Error(t, err)
+
+	_, err = ReadDownwardAPI(filepath.Join(dir, "missing"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestCgroupResources(t *testing.T) {
+	tests := []struct {
+		name       string
+		procCgroup string
+		files      map[string]string
+		expected   ContainerResources
+	}{
+		{
+			name:       "v2",
+			procCgroup: "0::/\n",
+			files:      map[string]string{"memory.max": "1073741824\n", "memory.low": "268435456\n"},
+			expected:   ContainerResources{MemoryRequest: 256 * MB, MemoryLimit: GB},
+		},
+		{
+			name:       "v2 without memory.low",
+			procCgroup: "0::/\n",
+			files:      map[string]string{"memory.max": "max\n"},
+			expected:   ContainerResources{MemoryLimit: Unlimited},
+		},
+		{
+			name:       "v1 without reservation",
+			procCgroup: "4:memory:/docker/abc\n",
+			files: map[string]string{
+				"memory/docker/abc/memory.limit_in_bytes":      "536870912\n",
+				"memory/docker/abc/memory.soft_limit_in_bytes": "9223372036854771712\n",
+			},
+			expected: ContainerResources{MemoryLimit: 512 * MB},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mount := t.TempDir()
+			for name, content := range tt.files {
+				writeCgroupFile(t, filepath.Join(mount, name), content)
+			}
+
+			res, err := cgroupResources(mount, strings.NewReader(tt.procCgroup))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
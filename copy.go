@@ -0,0 +1,43 @@
+package bytesizer
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// CopyStats copies from src to dst like io.Copy, and returns the number of
+// bytes copied together with the average throughput.
+func CopyStats(dst io.Writer, src io.Reader) (ByteSize, Rate, error) {
+	return CopyStatsContext(context.Background(), dst, src)
+}
+
+// CopyStatsContext is like CopyStats, but stops with ctx.Err() when ctx is
+// done. Cancellation is checked between reads.
+func CopyStatsContext(ctx context.Context, dst io.Writer, src io.Reader) (ByteSize, Rate, error) {
+	start := time.Now()
+	n, err := io.Copy(dst, contextReader{ctx: ctx, r: src})
+	return ByteSize(n), RateFor(ByteSize(n), time.Since(start)), err
+}
+
+// CopyN copies n bytes from src to dst like io.CopyN, stopping with
+// ctx.Err() when ctx is done, and returns the number of bytes copied together
+// with the average throughput. It returns io.EOF if src ends early.
+func CopyN(ctx context.Context, dst io.Writer, src io.Reader, n ByteSize) (ByteSize, Rate, error) {
+	start := time.Now()
+	written, err := io.CopyN(dst, contextReader{ctx: ctx, r: src}, int64(n))
+	return ByteSize(written), RateFor(ByteSize(written), time.Since(start)), err
+}
+
+// contextReader is an io.Reader that fails with ctx.Err() once ctx is done.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,72 @@
+package bytesizer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyStats(t *testing.T) {
+	var buf bytes.Buffer
+	n, rate, err := CopyStats(&buf, strings.NewReader(strings.Repeat("x", int(MB))))
+	assert.NoError(t, err)
+	assert.Equal(t, MB, n)
+	assert.Equal(t, int(MB), buf.Len())
+	assert.Greater(t, float64(rate), 0.0)
+}
+
+func TestCopyStatsThroughput(t *testing.T) {
+	src := ThrottleReader(strings.NewReader(strings.Repeat("x", int(30*KB))), 100*KBps)
+	n, rate, err := CopyStats(io.Discard, src)
+	assert.NoError(t, err)
+	assert.Equal(t, 30*KB, n)
+	// The initial burst makes the copy a little faster than the limit.
+	assert.Greater(t, float64(rate), float64(50*KBps))
+	assert.Less(t, float64(rate), float64(200*KBps))
+}
+
+func TestCopyN(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		n         ByteSize
+		expected  ByteSize
+		expectErr error
+	}{
+		{"Exact", int(KB), KB, KB, nil},
+		{"Partial", int(2 * KB), KB, KB, nil},
+		{"Short source", 100, KB, 100, io.EOF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			n, _, err := CopyN(context.Background(), &buf, strings.NewReader(strings.Repeat("x", tt.size)), tt.n)
+			assert.Equal(t, tt.expectErr, err)
+			assert.Equal(t, tt.expected, n)
+			assert.Equal(t, int(tt.expected), buf.Len())
+		})
+	}
+}
+
+func TestCopyContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	src := ThrottleReader(strings.NewReader(strings.Repeat("x", int(MB))), 100*KBps)
+	n, _, err := CopyStatsContext(ctx, io.Discard, src)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, n, MB)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	n, _, err = CopyN(canceled, io.Discard, strings.NewReader("Hello"), 5)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, ByteSize(0), n)
+}
@@ -0,0 +1,18 @@
+package bytesizer
+
+import "time"
+
+// BillingMonth is the month of storage price lists: 730 hours, a twelfth of
+// a 365-day year.
+const BillingMonth = 730 * time.Hour
+
+// Cost returns the price of storing size for d, at pricePerUnit for each
+// unit stored for a BillingMonth. The result is in the currency of the
+// price. unit must be positive; pass KB to PB for prices per binary unit,
+// such as AWS's GB, and DecimalKB to DecimalPB for prices per decimal unit.
+//
+//	// 1.7TiB for 3 months at $0.023/GB-month
+//	usd := Cost(17*TB/10, 0.023, GB, 3*BillingMonth) // 120.12
+func Cost(size ByteSize, pricePerUnit float64, unit ByteSize, d time.Duration) float64 {
+	return float64(size) / float64(unit) * pricePerUnit * (float64(d) / float64(BillingMonth))
+}
@@ -0,0 +1,38 @@
+package bytesizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCost(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  ByteSize
+		price float64
+		unit  ByteSize
+		d     time.Duration
+		want  float64
+	}{
+		{"Binary GB", 17 * TB / 10, 0.023, GB, 3 * BillingMonth, 120.1152},
+		{"Decimal GB", 17 * TB / 10, 0.023, DecimalGB, 3 * BillingMonth, 128.9727},
+		{"Per TB", 500 * GB, 20, TB, BillingMonth, 9.765625},
+		{"Per hour", 100 * GB, 0.1, GB, time.Hour, 10.0 / 730},
+		{"A year", 730 * GB, 0.01, GB, 365 * 24 * time.Hour, 87.6},
+		{"Nothing stored", 0, 0.023, GB, BillingMonth, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, Cost(tt.size, tt.price, tt.unit, tt.d), 1e-4)
+		})
+	}
+}
+
+func TestDecimalUnits(t *testing.T) {
+	assert.Equal(t, ByteSize(1e9), DecimalGB)
+	assert.Equal(t, ByteSize(1e15), DecimalPB)
+	assert.Equal(t, "1.82TB", (2 * DecimalTB).String())
+}
@@ -0,0 +1,61 @@
+package bytesizer
+
+import "io"
+
+// CountingReader is an io.Reader that counts the bytes read through it.
+// Count may be called concurrently with Read.
+type CountingReader struct {
+	r io.Reader
+	n Counter
+}
+
+// NewCountingReader returns a CountingReader reading from r.
+func NewCountingReader(r io.Reader) *CountingReader {
+	return &CountingReader{r: r}
+}
+
+// TeeCount returns a CountingReader that writes everything it reads from r
+// to w, like io.TeeReader, and counts the bytes that went through.
+func TeeCount(r io.Reader, w io.Writer) *CountingReader {
+	return NewCountingReader(io.TeeReader(r, w))
+}
+
+// Read reads from the underlying reader and adds the bytes read to the count.
+func (c *CountingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(ByteSize(n))
+	return n, err
+}
+
+// Count returns the number of bytes read so far.
+func (c *CountingReader) Count() ByteSize {
+	return c.n.Load()
+}
+
+// CountingWriter is an io.Writer that counts the bytes written through it.
+// Count may be called concurrently with Write.
+type CountingWriter struct {
+	w io.Writer
+	n Counter
+}
+
+// NewCountingWriter returns a CountingWriter writing to w.
+// A nil w discards the data, which is useful to measure encoded sizes.
+func NewCountingWriter(w io.Writer) *CountingWriter {
+	if w == nil {
+		w = io.Discard
+	}
+	return &CountingWriter{w: w}
+}
+
+// Write writes to the underlying writer and adds the bytes written to the count.
+func (c *CountingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n.Add(ByteSize(n))
+	return n, err
+}
+
+// Count returns the number of bytes written so far.
+func (c *CountingWriter) Count() ByteSize {
+	return c.n.Load()
+}
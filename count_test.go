@@ -0,0 +1,78 @@
+package bytesizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountingReader(t *testing.T) {
+	r := NewCountingReader(strings.NewReader(strings.Repeat("x", int(1536*KB))))
+	n, err := io.Copy(io.Discard, r)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1536*KB), n)
+	assert.Equal(t, 1536*KB, r.Count())
+	assert.Equal(t, "1.5MB", r.Count().String())
+}
+
+func TestCountingWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewCountingWriter(&buf)
+	_, err := io.WriteString(w, "Hello ")
+	assert.NoError(t, err)
+	_, err = io.WriteString(w, "World!")
+	assert.NoError(t, err)
+	assert.Equal(t, Calc(buf.Bytes()), w.Count())
+	assert.Equal(t, ByteSize(12), w.Count())
+}
+
+func TestCountingWriterDiscard(t *testing.T) {
+	w := NewCountingWriter(nil)
+	assert.NoError(t, json.NewEncoder(w).Encode(map[string]int{"a": 1}))
+	assert.Equal(t, ByteSize(len("{\"a\":1}\n")), w.Count())
+}
+
+type errWriter struct{ n int }
+
+func (w errWriter) Write(p []byte) (int, error) {
+	return w.n, errors.New("short write")
+}
+
+func TestCountingWriterPartial(t *testing.T) {
+	w := NewCountingWriter(errWriter{n: 3})
+	_, err := w.Write([]byte("Hello"))
+	assert.Error(t, err)
+	assert.Equal(t, ByteSize(3), w.Count())
+}
+
+func TestTeeCount(t *testing.T) {
+	var buf bytes.Buffer
+	r := TeeCount(strings.NewReader("Hello World!"), &buf)
+	b, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello World!", string(b))
+	assert.Equal(t, "Hello World!", buf.String())
+	assert.Equal(t, ByteSize(12), r.Count())
+}
+
+func TestCountingWriterConcurrent(t *testing.T) {
+	w := NewCountingWriter(nil)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_, _ = w.Write(make([]byte, KB))
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 800*KB, w.Count())
+}
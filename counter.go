@@ -0,0 +1,47 @@
+package bytesizer
+
+import "sync/atomic"
+
+// Counter is a ByteSize that can be updated concurrently, for byte counts
+// shared between goroutines. The zero value is a counter at zero. A Counter
+// must not be copied after first use.
+//
+//	var received Counter
+//	go func() { received.Add(ByteSize(n)) }()
+//	log.Printf("received %s", &received)
+type Counter struct {
+	n atomic.Int64
+}
+
+// Add atomically adds delta to c and returns the new value.
+func (c *Counter) Add(delta ByteSize) ByteSize {
+	return ByteSize(c.n.Add(int64(delta)))
+}
+
+// Load atomically returns the value of c.
+func (c *Counter) Load() ByteSize {
+	return ByteSize(c.n.Load())
+}
+
+// Store atomically sets c to size.
+func (c *Counter) Store(size ByteSize) {
+	c.n.Store(int64(size))
+}
+
+// Swap atomically sets c to size and returns the previous value, e.g. to
+// read and reset a counter for each reporting interval.
+func (c *Counter) Swap(size ByteSize) ByteSize {
+	return ByteSize(c.n.Swap(int64(size)))
+}
+
+// CompareAndSwap atomically sets c to new if it is old, and reports
+// whether it did.
+func (c *Counter) CompareAndSwap(old, new ByteSize) bool {
+	return c.n.CompareAndSwap(int64(old), int64(new))
+}
+
+// String returns the value of c with an appropriate unit, like
+// ByteSize.String.
+func (c *Counter) String() string {
+	return c.Load().String()
+}
@@ -0,0 +1,43 @@
+package bytesizer
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounter(t *testing.T) {
+	var c Counter
+	assert.Equal(t, ByteSize(0), c.Load())
+	assert.Equal(t, "0B", c.String())
+
+	assert.Equal(t, MB, c.Add(MB))
+	assert.Equal(t, MB+MB/2, c.Add(MB/2))
+	assert.Equal(t, "1.5MB", c.String())
+
+	c.Store(GB)
+	assert.Equal(t, GB, c.Load())
+	assert.Equal(t, GB, c.Swap(0))
+	assert.Equal(t, ByteSize(0), c.Load())
+
+	assert.False(t, c.CompareAndSwap(KB, 2*KB))
+	assert.True(t, c.CompareAndSwap(0, 2*KB))
+	assert.Equal(t, 2*KB, c.Load())
+}
+
+func TestCounterConcurrent(t *testing.T) {
+	var c Counter
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.Add(KB)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 8000*KB, c.Load())
+}
@@ -0,0 +1,532 @@
+package bytesizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// SymlinkPolicy controls how DirSize treats symbolic links.
+type SymlinkPolicy int
+
+const (
+	// SkipSymlinks ignores symbolic links. This is the default.
+	SkipSymlinks SymlinkPolicy = iota
+	// CountSymlinks counts the size of the links themselves.
+	CountSymlinks
+	// FollowSymlinks counts what links point to. Each directory is walked
+	// at most once, so link cycles are safe.
+	FollowSymlinks
+)
+
+// WalkProgress is a snapshot of a DirSize walk in progress.
+type WalkProgress struct {
+	Files int64
+	Dirs  int64
+	Size  ByteSize
+}
+
+// WalkError collects the errors met while walking a tree, such as
+// unreadable directories. The walk carries on past them.
+type WalkError struct {
+	Errors []error
+}
+
+func (e *WalkError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	return fmt.Sprintf("%v (and %d more errors)", e.Errors[0], len(e.Errors)-1)
+}
+
+// Unwrap returns the collected errors.
+func (e *WalkError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is reports whether any collected error matches target. Before Go 1.20,
+// errors.Is does not follow Unwrap() []error and relies on it.
+func (e *WalkError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first collected error that matches target, like Is.
+func (e *WalkError) As(target any) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+type walkConfig struct {
+	workers     int
+	fanout      int
+	symlinks    SymlinkPolicy
+	stopOnError bool
+	progress    func(WalkProgress)
+	hardlinks   bool
+	oneFS       bool
+	maxDepth    int
+	excludes    []string
+	entrySizes  func(name string, size ByteSize)
+}
+
+// WalkOption configures DirSize.
+type WalkOption func(*walkConfig)
+
+// WithWorkers sets how many directories are read concurrently.
+// The default is the number of CPUs; values below 1 mean 1. Walking is
+// mostly waiting on the filesystem, so on network filesystems many more
+// workers than CPUs can help.
+func WithWorkers(n int) WalkOption {
+	return func(c *walkConfig) {
+		if n < 1 {
+			n = 1
+		}
+		c.workers = n
+	}
+}
+
+// WithFanout sets how many subdirectories of any one directory are walked
+// concurrently; the others are walked in turn by the worker reading the
+// directory. It keeps a directory with many subdirectories from taking all
+// the workers. The default, or an n below 1, is no limit but the workers.
+func WithFanout(n int) WalkOption {
+	return func(c *walkConfig) {
+		c.fanout = n
+	}
+}
+
+// WithSymlinks sets how symbolic links are treated.
+func WithSymlinks(policy SymlinkPolicy) WalkOption {
+	return func(c *walkConfig) {
+		c.symlinks = policy
+	}
+}
+
+// StopOnError makes DirSize stop at the first error and return it, instead
+// of collecting errors in a *WalkError.
+func StopOnError() WalkOption {
+	return func(c *walkConfig) {
+		c.stopOnError = true
+	}
+}
+
+// WithProgress sets a function called after each directory is read. Calls
+// are serialized, but may come from different goroutines.
+func WithProgress(fn func(WalkProgress)) WalkOption {
+	return func(c *walkConfig) {
+		c.progress = fn
+	}
+}
+
+// CountHardlinksOnce makes DirSize count a file with several hard links
+// once, like du does. Files are told apart by device and inode, which are
+// not available on Windows.
+func CountHardlinksOnce() WalkOption {
+	return func(c *walkConfig) {
+		c.hardlinks = true
+	}
+}
+
+// OneFilesystem makes DirSize skip directories on other filesystems than
+// path, like "du -x".
+func OneFilesystem() WalkOption {
+	return func(c *walkConfig) {
+		c.oneFS = true
+	}
+}
+
+// WithMaxDepth limits the walk to n levels of directories below path: files
+// deeper than that are not counted. 0 counts only the files directly in
+// path. The default, or a negative n, is no limit.
+func WithMaxDepth(n int) WalkOption {
+	return func(c *walkConfig) {
+		c.maxDepth = n
+	}
+}
+
+// WithExclude skips files and directories matching .gitignore-style
+// patterns, like "du --exclude". Patterns are matched against paths relative
+// to the walked directory: "*.log" matches at any depth, "/build" or
+// "cache/tmp" only from the root, "node_modules/" only directories, "**"
+// any number of directories, and "!keep.log" re-includes a file. It may be
+// given more than once.
+func WithExclude(patterns ...string) WalkOption {
+	return func(c *walkConfig) {
+		c.excludes = append(c.excludes, patterns...)
+	}
+}
+
+// WithEntrySizes sets a function called after the walk with the size of
+// each entry directly in path, in name order, like "du -d1". Excluded
+// entries and skipped symlinks are not reported.
+func WithEntrySizes(fn func(name string, size ByteSize)) WalkOption {
+	return func(c *walkConfig) {
+		c.entrySizes = fn
+	}
+}
+
+// DirSize returns the total apparent size of the regular files under path,
+// like "du -sb". If path is a file, its size is returned.
+//
+// Errors on individual entries do not stop the walk: DirSize returns the
+// size of everything it could read along with a *WalkError, unless
+// StopOnError is given. If ctx is done, DirSize stops within a few entries
+// and returns ctx.Err().
+func DirSize(ctx context.Context, path string, opts ...WalkOption) (ByteSize, error) {
+	cfg := walkConfig{workers: runtime.NumCPU(), maxDepth: -1}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	excludes, err := compileExcludes(cfg.excludes)
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	if !info.IsDir() {
+		return ByteSize(info.Size()), nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w := &walker{
+		cfg:      cfg,
+		excludes: excludes,
+		ctx:      ctx,
+		cancel:   cancel,
+		sem:      make(chan struct{}, cfg.workers-1),
+		visited:  make(map[string]bool),
+		links:    make(map[fileKey]bool),
+	}
+	if cfg.entrySizes != nil {
+		w.entries = make(map[string]int64)
+	}
+	if root, ok := fileID(info); ok {
+		w.dev = root.dev
+	}
+	w.subdir(path, "", 0, nil)
+	w.wg.Wait()
+	w.reportEntries()
+
+	size := ByteSize(w.size.Load())
+	switch {
+	case len(w.errs) > 0 && cfg.stopOnError:
+		return size, w.errs[0]
+	case ctx.Err() != nil:
+		return size, ctx.Err()
+	case len(w.errs) > 0:
+		return size, &WalkError{Errors: w.errs}
+	}
+	return size, nil
+}
+
+type walker struct {
+	cfg      walkConfig
+	excludes excluder
+	dev      uint64 // device of the root, for OneFilesystem
+	ctx      context.Context
+	cancel   context.CancelFunc
+	sem      chan struct{}
+	wg       sync.WaitGroup
+
+	size  atomic.Int64
+	files atomic.Int64
+	dirs  atomic.Int64
+
+	mu      sync.Mutex
+	errs    []error
+	visited map[string]bool
+	links   map[fileKey]bool
+	entries map[string]int64 // sizes of the root's entries, for WithEntrySizes
+}
+
+// subdir walks the directory at path, in a new goroutine if a worker is free
+// and fan, the fanout of the parent directory, has room. rel is its
+// slash-separated path relative to the root, and depth its level below the
+// root.
+func (w *walker) subdir(path, rel string, depth int, fan chan struct{}) {
+	if w.cfg.maxDepth >= 0 && depth > w.cfg.maxDepth {
+		return
+	}
+	if w.cfg.symlinks == FollowSymlinks && !w.visit(path) {
+		return
+	}
+	if fan != nil {
+		select {
+		case fan <- struct{}{}:
+		default:
+			w.dir(path, rel, depth)
+			return
+		}
+	}
+	select {
+	case w.sem <- struct{}{}:
+		w.wg.Add(1)
+		go func() {
+			defer func() {
+				<-w.sem
+				if fan != nil {
+					<-fan
+				}
+				w.wg.Done()
+			}()
+			w.dir(path, rel, depth)
+		}()
+	default:
+		if fan != nil {
+			<-fan
+		}
+		w.dir(path, rel, depth)
+	}
+}
+
+// visit reports whether the directory at path is seen for the first time.
+func (w *walker) visit(path string) bool {
+	real, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		w.fail(err)
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.visited[real] {
+		return false
+	}
+	w.visited[real] = true
+	return true
+}
+
+// readDirBatch is how many entries dir reads at a time, so that large
+// directories are not held in memory at once.
+const readDirBatch = 256
+
+func (w *walker) dir(path, rel string, depth int) {
+	if w.stopped() {
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		w.fail(err)
+	} else {
+		defer f.Close()
+		if !w.readDir(f, path, rel, depth) {
+			return
+		}
+	}
+	w.dirs.Add(1)
+	w.report()
+}
+
+// stopped reports whether the walk was canceled.
+func (w *walker) stopped() bool {
+	select {
+	case <-w.ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// readDir walks the entries of the directory f at path. It returns false
+// if the walk was canceled.
+func (w *walker) readDir(f *os.File, path, rel string, depth int) bool {
+	var fan chan struct{}
+	if w.cfg.fanout > 0 {
+		fan = make(chan struct{}, w.cfg.fanout)
+	}
+	for {
+		// ReadDir returns the entries read before an error, count those
+		// anyway.
+		entries, err := f.ReadDir(readDirBatch)
+		for _, e := range entries {
+			if w.stopped() {
+				return false
+			}
+			w.dirEntry(e, path, rel, depth, fan)
+		}
+		switch {
+		case err == io.EOF:
+			return true
+		case err != nil:
+			w.fail(err)
+			return true
+		}
+	}
+}
+
+// dirEntry counts or walks e, an entry of the directory at path.
+func (w *walker) dirEntry(e fs.DirEntry, path, rel string, depth int, fan chan struct{}) {
+	p := filepath.Join(path, e.Name())
+	r := e.Name()
+	if rel != "" {
+		r = rel + "/" + r
+	}
+	if w.excludes != nil && w.excludes.excluded(r, e.IsDir()) {
+		return
+	}
+	switch typ := e.Type(); {
+	case typ&fs.ModeSymlink != 0:
+		if w.cfg.symlinks != SkipSymlinks {
+			w.entry(r)
+		}
+		w.symlink(p, r, depth+1, fan)
+	case typ.IsDir():
+		if w.cfg.oneFS && !w.sameFS(e) {
+			return
+		}
+		w.entry(r)
+		w.subdir(p, r, depth+1, fan)
+	case typ.IsRegular():
+		info, err := e.Info()
+		if err != nil {
+			w.fail(err)
+			return
+		}
+		w.entry(r)
+		w.addFile(info, r)
+	}
+}
+
+// sameFS reports whether the directory e is on the same filesystem as the
+// root.
+func (w *walker) sameFS(e fs.DirEntry) bool {
+	info, err := e.Info()
+	if err != nil {
+		w.fail(err)
+		return false
+	}
+	id, ok := fileID(info)
+	return !ok || id.dev == w.dev
+}
+
+func (w *walker) symlink(path, rel string, depth int, fan chan struct{}) {
+	switch w.cfg.symlinks {
+	case CountSymlinks:
+		info, err := os.Lstat(path)
+		if err != nil {
+			w.fail(err)
+			return
+		}
+		w.add(info.Size(), rel)
+	case FollowSymlinks:
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			return // dangling link
+		}
+		if err != nil {
+			w.fail(err)
+			return
+		}
+		if info.IsDir() {
+			if id, ok := fileID(info); ok && w.cfg.oneFS && id.dev != w.dev {
+				return
+			}
+			w.subdir(path, rel, depth, fan)
+		} else if info.Mode().IsRegular() {
+			w.addFile(info, rel)
+		}
+	}
+}
+
+// addFile counts a regular file at rel, unless it is a hard link already
+// counted.
+func (w *walker) addFile(info fs.FileInfo, rel string) {
+	if w.cfg.hardlinks && linkCount(info) > 1 {
+		if id, ok := fileID(info); ok {
+			w.mu.Lock()
+			seen := w.links[id]
+			w.links[id] = true
+			w.mu.Unlock()
+			if seen {
+				return
+			}
+		}
+	}
+	w.add(info.Size(), rel)
+}
+
+// add counts a file of size bytes at rel.
+func (w *walker) add(size int64, rel string) {
+	w.size.Add(size)
+	w.files.Add(1)
+	if w.entries != nil {
+		if i := strings.IndexByte(rel, '/'); i >= 0 {
+			rel = rel[:i]
+		}
+		w.mu.Lock()
+		w.entries[rel] += size
+		w.mu.Unlock()
+	}
+}
+
+// entry records rel as an entry of the root for WithEntrySizes, if it is
+// one.
+func (w *walker) entry(rel string) {
+	if w.entries == nil || strings.IndexByte(rel, '/') >= 0 {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.entries[rel]; !ok {
+		w.entries[rel] = 0
+	}
+}
+
+func (w *walker) reportEntries() {
+	if w.entries == nil {
+		return
+	}
+	names := make([]string, 0, len(w.entries))
+	for name := range w.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		w.cfg.entrySizes(name, ByteSize(w.entries[name]))
+	}
+}
+
+func (w *walker) fail(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.errs = append(w.errs, err)
+	if w.cfg.stopOnError {
+		w.cancel()
+	}
+}
+
+func (w *walker) report() {
+	if w.cfg.progress == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.cfg.progress(WalkProgress{
+		Files: w.files.Load(),
+		Dirs:  w.dirs.Load(),
+		Size:  ByteSize(w.size.Load()),
+	})
+}
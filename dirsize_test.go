@@ -0,0 +1,352 @@
+package bytesizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTree creates files of the given sizes under dir.
+func writeTree(t *testing.T, dir string, files map[string]ByteSize) {
+	t.Helper()
+	for name, size := range files {
+		p := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		assert.NoError(t, os.WriteFile(p, make([]byte, size), 0o644))
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]ByteSize{
+		"a.bin":         KB,
+		"b/c.bin":       2 * KB,
+		"b/d/e.bin":     3 * KB,
+		"b/d/f/g/h.bin": 100,
+		"empty.bin":     0,
+	})
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "empty"), 0o755))
+
+	for _, workers := range []int{1, 2, 16} {
+		size, err := DirSize(context.Background(), dir, WithWorkers(workers))
+		assert.NoError(t, err)
+		assert.Equal(t, 6*KB+100, size)
+	}
+
+	size, err := DirSize(context.Background(), filepath.Join(dir, "b", "c.bin"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2*KB, size)
+
+	_, err = DirSize(context.Background(), filepath.Join(dir, "missing"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDirSizeExclude(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]ByteSize{
+		"a.bin":                KB,
+		"a.log":                2 * KB,
+		"keep.log":             3 * KB,
+		"node_modules/x/y.bin": 4 * KB,
+		"src/build/z.bin":      5 * KB,
+		"build/z.bin":          6 * KB,
+	})
+
+	size, err := DirSize(context.Background(), dir,
+		WithExclude("*.log", "!keep.log", "node_modules/"),
+		WithExclude("/build"),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, KB+3*KB+5*KB, size)
+
+	_, err = DirSize(context.Background(), dir, WithExclude("[a-"))
+	assert.Error(t, err)
+}
+
+func TestDirSizeMaxDepth(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]ByteSize{
+		"a.bin":     KB,
+		"b/c.bin":   2 * KB,
+		"b/d/e.bin": 3 * KB,
+	})
+
+	for depth, expected := range map[int]ByteSize{0: KB, 1: 3 * KB, 2: 6 * KB, -1: 6 * KB} {
+		size, err := DirSize(context.Background(), dir, WithMaxDepth(depth))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, size, "depth %d", depth)
+	}
+}
+
+func TestDirSizeHardlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("inodes are not available on windows")
+	}
+
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]ByteSize{
+		"a.bin":   KB,
+		"b/c.bin": 2 * KB,
+	})
+	assert.NoError(t, os.Link(filepath.Join(dir, "a.bin"), filepath.Join(dir, "b", "a.bin")))
+	assert.NoError(t, os.Link(filepath.Join(dir, "a.bin"), filepath.Join(dir, "a2.bin")))
+
+	size, err := DirSize(context.Background(), dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 5*KB, size)
+
+	size, err = DirSize(context.Background(), dir, CountHardlinksOnce())
+	assert.NoError(t, err)
+	assert.Equal(t, 3*KB, size)
+}
+
+func TestDirSizeOneFilesystem(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]ByteSize{"a/b.bin": KB})
+
+	size, err := DirSize(context.Background(), dir, OneFilesystem())
+	assert.NoError(t, err)
+	assert.Equal(t, KB, size)
+}
+
+func TestDirSizeSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on windows")
+	}
+
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]ByteSize{
+		"data/a.bin":  KB,
+		"other/b.bin": 2 * KB,
+	})
+	assert.NoError(t, os.Symlink(filepath.Join(dir, "other"), filepath.Join(dir, "data", "other")))
+	assert.NoError(t, os.Symlink(filepath.Join(dir, "other", "b.bin"), filepath.Join(dir, "data", "b.bin")))
+	assert.NoError(t, os.Symlink(dir, filepath.Join(dir, "other", "loop")))
+	assert.NoError(t, os.Symlink(filepath.Join(dir, "nowhere"), filepath.Join(dir, "data", "dangling")))
+
+	root := filepath.Join(dir, "data")
+
+	size, err := DirSize(context.Background(), root)
+	assert.NoError(t, err)
+	assert.Equal(t, KB, size)
+
+	linkSize := func(name string) ByteSize {
+		info, err := os.Lstat(filepath.Join(root, name))
+		assert.NoError(t, err)
+		return ByteSize(info.Size())
+	}
+	size, err = DirSize(context.Background(), root, WithSymlinks(CountSymlinks))
+	assert.NoError(t, err)
+	assert.Equal(t, KB+linkSize("other")+linkSize("b.bin")+linkSize("dangling"), size)
+
+	// data/a.bin, data/b.bin -> other/b.bin, and through the loop back to
+	// the top: data/a.bin again and other/b.bin, while each directory is
+	// walked only once.
+	size, err = DirSize(context.Background(), root, WithSymlinks(FollowSymlinks))
+	assert.NoError(t, err)
+	assert.Equal(t, KB+2*KB+2*KB, size)
+}
+
+func TestDirSizeProgress(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]ByteSize{
+		"a.bin":     KB,
+		"b/c.bin":   KB,
+		"b/d/e.bin": KB,
+	})
+
+	var last WalkProgress
+	calls := 0
+	_, err := DirSize(context.Background(), dir, WithProgress(func(p WalkProgress) {
+		calls++
+		last = p
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, WalkProgress{Files: 3, Dirs: 3, Size: 3 * KB}, last)
+}
+
+func TestDirSizeEntrySizes(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]ByteSize{
+		"a.bin":             KB,
+		"b/c.bin":           2 * KB,
+		"b/d/e.bin":         3 * KB,
+		"logs/x.log":        5 * KB,
+		"node_modules/m.js": 7 * KB,
+	})
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "empty"), 0o755))
+	if runtime.GOOS != "windows" {
+		assert.NoError(t, os.Symlink("b", filepath.Join(dir, "link")))
+	}
+
+	tests := []struct {
+		name string
+		opts []WalkOption
+		want map[string]ByteSize
+	}{
+		{
+			name: "All",
+			want: map[string]ByteSize{"a.bin": KB, "b": 5 * KB, "empty": 0, "logs": 5 * KB, "node_modules": 7 * KB},
+		},
+		{
+			name: "Exclude",
+			opts: []WalkOption{WithExclude("node_modules/", "*.log")},
+			want: map[string]ByteSize{"a.bin": KB, "b": 5 * KB, "empty": 0, "logs": 0},
+		},
+		{
+			name: "Max depth",
+			opts: []WalkOption{WithMaxDepth(1), WithExclude("/logs", "/node_modules")},
+			want: map[string]ByteSize{"a.bin": KB, "b": 2 * KB, "empty": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]ByteSize)
+			var names []string
+			opts := append(tt.opts, WithWorkers(4), WithEntrySizes(func(name string, size ByteSize) {
+				names = append(names, name)
+				got[name] = size
+			}))
+			_, err := DirSize(context.Background(), dir, opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.IsIncreasing(t, names)
+		})
+	}
+}
+
+func TestDirSizeCanceled(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]ByteSize{"a/b.bin": KB})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := DirSize(ctx, dir)
+	assert.Equal(t, context.Canceled, err)
+}
+
+// writeWideTree creates dirs directories under dir, each holding files
+// empty files, and returns the number of files.
+func writeWideTree(tb testing.TB, dir string, dirs, files int) int {
+	tb.Helper()
+	for i := 0; i < dirs; i++ {
+		d := filepath.Join(dir, fmt.Sprintf("d%03d", i))
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			tb.Fatal(err)
+		}
+		for j := 0; j < files; j++ {
+			if err := os.WriteFile(filepath.Join(d, fmt.Sprintf("f%03d", j)), nil, 0o644); err != nil {
+				tb.Fatal(err)
+			}
+		}
+	}
+	return dirs * files
+}
+
+func TestDirSizeConcurrency(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]ByteSize{
+		"a.bin":         KB,
+		"b/c.bin":       2 * KB,
+		"b/d/e.bin":     3 * KB,
+		"b/d/f/g/h.bin": 100,
+		"i/j.bin":       KB,
+	})
+	// More entries than a ReadDir batch.
+	files := writeWideTree(t, filepath.Join(dir, "k"), 3, readDirBatch+10)
+
+	tests := []struct {
+		name string
+		opts []WalkOption
+	}{
+		{"One worker", []WalkOption{WithWorkers(1)}},
+		{"Many workers", []WalkOption{WithWorkers(64)}},
+		{"Fanout", []WalkOption{WithWorkers(64), WithFanout(1)}},
+		{"No fanout limit", []WalkOption{WithFanout(0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var last WalkProgress
+			size, err := DirSize(context.Background(), dir, append(tt.opts, WithProgress(func(p WalkProgress) {
+				last = p
+			}))...)
+			assert.NoError(t, err)
+			assert.Equal(t, 7*KB+100, size)
+			assert.Equal(t, int64(5+files), last.Files)
+			assert.Equal(t, int64(10), last.Dirs)
+		})
+	}
+}
+
+func TestDirSizeCancelMidWalk(t *testing.T) {
+	dir := t.TempDir()
+	files := writeWideTree(t, dir, 20, 50)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var last WalkProgress
+	_, err := DirSize(ctx, dir, WithWorkers(1), WithProgress(func(p WalkProgress) {
+		last = p
+		cancel()
+	}))
+	assert.Equal(t, context.Canceled, err)
+	// The walk stops within the directory being read when canceled.
+	assert.Equal(t, int64(1), last.Dirs)
+	assert.Less(t, last.Files, int64(files))
+}
+
+func TestDirSizeErrors(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("needs unix permissions enforced")
+	}
+
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]ByteSize{
+		"a.bin":         KB,
+		"locked/b.bin":  KB,
+		"locked2/c.bin": KB,
+		"visible/d.bin": KB,
+	})
+	for _, name := range []string{"locked", "locked2"} {
+		p := filepath.Join(dir, name)
+		assert.NoError(t, os.Chmod(p, 0))
+		t.Cleanup(func() { _ = os.Chmod(p, 0o755) })
+	}
+
+	size, err := DirSize(context.Background(), dir)
+	var walkErr *WalkError
+	assert.True(t, errors.As(err, &walkErr))
+	assert.Len(t, walkErr.Errors, 2)
+	assert.Equal(t, 2*KB, size)
+
+	_, err = DirSize(context.Background(), dir, StopOnError(), WithWorkers(1))
+	assert.True(t, os.IsPermission(err))
+}
+
+func TestWalkError(t *testing.T) {
+	one := &WalkError{Errors: []error{errors.New("a")}}
+	assert.EqualError(t, one, "a")
+	two := &WalkError{Errors: []error{errors.New("a"), errors.New("b")}}
+	assert.EqualError(t, two, "a (and 1 more errors)")
+
+	pathErr := &fs.PathError{Op: "open", Path: "locked", Err: fs.ErrPermission}
+	err := fmt.Errorf("walk: %w", &WalkError{Errors: []error{errors.New("a"), pathErr}})
+	assert.True(t, errors.Is(err, fs.ErrPermission))
+	assert.False(t, errors.Is(err, fs.ErrNotExist))
+	var target *fs.PathError
+	assert.True(t, errors.As(err, &target))
+	assert.Same(t, pathErr, target)
+	assert.True(t, (&WalkError{Errors: []error{pathErr}}).Is(fs.ErrPermission))
+	assert.True(t, (&WalkError{Errors: []error{pathErr}}).As(&target))
+	assert.False(t, (&WalkError{}).As(&target))
+}
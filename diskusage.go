@@ -0,0 +1,35 @@
+package bytesizer
+
+// Usage describes the space of a file system.
+type Usage struct {
+	Total     ByteSize // size of the file system
+	Free      ByteSize // free space, including space reserved for the superuser
+	Available ByteSize // free space available to unprivileged users
+	Used      ByteSize // Total - Free
+}
+
+// UsedPercent returns Used as a percentage of Total.
+func (u Usage) UsedPercent() float64 {
+	if u.Total <= 0 {
+		return 0
+	}
+	return float64(u.Used) / float64(u.Total) * 100
+}
+
+// DiskUsage returns the usage of the file system containing path:
+//
+//	u, err := DiskUsage(dir)
+//	if err == nil && u.Available < 10*GB {
+//		return errors.New("not enough space to download")
+//	}
+//
+// It uses statfs on Linux, macOS and FreeBSD, and GetDiskFreeSpaceEx on
+// Windows. On other systems it returns an error.
+func DiskUsage(path string) (Usage, error) {
+	u, err := diskUsage(path)
+	if err != nil {
+		return Usage{}, err
+	}
+	u.Used = u.Total - u.Free
+	return u, nil
+}
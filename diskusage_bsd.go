@@ -0,0 +1,11 @@
+//go:build darwin || freebsd
+
+package bytesizer
+
+import "syscall"
+
+// statfsBlockSize returns the unit of the block counts of st, the
+// fundamental block size in Bsize.
+func statfsBlockSize(st *syscall.Statfs_t) ByteSize {
+	return ByteSize(st.Bsize)
+}
@@ -0,0 +1,10 @@
+package bytesizer
+
+import "syscall"
+
+// statfsBlockSize returns the unit of the block counts of st. Linux counts
+// them in fragments, whose size may differ from the preferred I/O size in
+// Bsize.
+func statfsBlockSize(st *syscall.Statfs_t) ByteSize {
+	return ByteSize(st.Frsize)
+}
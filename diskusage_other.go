@@ -0,0 +1,12 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package bytesizer
+
+import (
+	"errors"
+	"os"
+)
+
+func diskUsage(path string) (Usage, error) {
+	return Usage{}, &os.PathError{Op: "diskusage", Path: path, Err: errors.New("not supported on this platform")}
+}
@@ -0,0 +1,21 @@
+//go:build linux || darwin || freebsd
+
+package bytesizer
+
+import (
+	"os"
+	"syscall"
+)
+
+func diskUsage(path string) (Usage, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(path, &st); err != nil {
+		return Usage{}, &os.PathError{Op: "statfs", Path: path, Err: err}
+	}
+	bsize := statfsBlockSize(&st)
+	return Usage{
+		Total:     ByteSize(st.Blocks) * bsize,
+		Free:      ByteSize(st.Bfree) * bsize,
+		Available: ByteSize(st.Bavail) * bsize,
+	}, nil
+}
@@ -0,0 +1,27 @@
+package bytesizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskUsage(t *testing.T) {
+	u, err := DiskUsage(t.TempDir())
+	assert.NoError(t, err)
+	assert.Greater(t, u.Total, ByteSize(0))
+	assert.LessOrEqual(t, u.Free, u.Total)
+	assert.LessOrEqual(t, u.Available, u.Free)
+	assert.Equal(t, u.Total-u.Free, u.Used)
+	assert.True(t, u.UsedPercent() >= 0 && u.UsedPercent() <= 100)
+
+	_, err = DiskUsage(filepath.Join(t.TempDir(), "missing"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestUsageUsedPercent(t *testing.T) {
+	assert.Equal(t, 25.0, Usage{Total: 100 * GB, Used: 25 * GB}.UsedPercent())
+	assert.Equal(t, 0.0, Usage{}.UsedPercent())
+}
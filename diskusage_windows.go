@@ -0,0 +1,34 @@
+//go:build windows
+
+package bytesizer
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var getDiskFreeSpaceEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+func diskUsage(path string) (Usage, error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return Usage{}, &os.PathError{Op: "GetDiskFreeSpaceEx", Path: path, Err: err}
+	}
+
+	var available, total, free uint64
+	r, _, err := getDiskFreeSpaceEx.Call(
+		uintptr(unsafe.Pointer(p)),
+		uintptr(unsafe.Pointer(&available)),
+		uintptr(unsafe.Pointer(&total)),
+		uintptr(unsafe.Pointer(&free)),
+	)
+	if r == 0 {
+		return Usage{}, &os.PathError{Op: "GetDiskFreeSpaceEx", Path: path, Err: err}
+	}
+	return Usage{
+		Total:     ByteSize(total),
+		Free:      ByteSize(free),
+		Available: ByteSize(available),
+	}, nil
+}
@@ -0,0 +1,49 @@
+package bytesizer
+
+import (
+	"fmt"
+	"os"
+)
+
+// GetEnv returns the ByteSize held by the environment variable name.
+// It returns def when the variable is unset, empty or cannot be parsed.
+func GetEnv(name string, def ByteSize) ByteSize {
+	size, ok, err := LookupEnv(name)
+	if !ok || err != nil {
+		return def
+	}
+	return size
+}
+
+// LookupEnv parses the ByteSize held by the environment variable name.
+// ok reports whether the variable is set to a non-empty value; err is
+// non-nil when it is set but cannot be parsed.
+func LookupEnv(name string) (size ByteSize, ok bool, err error) {
+	s, ok := os.LookupEnv(name)
+	if !ok || s == "" {
+		return 0, false, nil
+	}
+	size, err = Parse(s)
+	if err != nil {
+		return 0, true, fmt.Errorf("env %s: %w", name, err)
+	}
+	return size, true, nil
+}
+
+// Set parses s with Parse and stores the result.
+// Together with String it makes *ByteSize a flag.Value and a kingpin.Value,
+// and it satisfies the Setter interface of github.com/kelseyhightower/envconfig.
+func (fs *ByteSize) Set(s string) error {
+	return fs.UnmarshalText([]byte(s))
+}
+
+// Decode parses s with Parse and stores the result.
+// It satisfies the Decoder interface of github.com/kelseyhightower/envconfig.
+//
+// Because of this method *ByteSize cannot also implement kong.MapperValue,
+// whose Decode takes a *kong.DecodeContext. It does not need to: kong maps
+// encoding.TextUnmarshaler fields before falling back to their kind, so
+// ByteSize fields (including `default:"100MB"` tags) are parsed by UnmarshalText.
+func (fs *ByteSize) Decode(s string) error {
+	return fs.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,80 @@
+package bytesizer
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/caarlos0/env/v10"
+	"github.com/kelseyhightower/envconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetEnv(t *testing.T) {
+	t.Setenv("BYTESIZER_MAX_BODY", "10MB")
+	t.Setenv("BYTESIZER_EMPTY", "")
+	t.Setenv("BYTESIZER_INVALID", "lots")
+
+	assert.Equal(t, 10*MB, GetEnv("BYTESIZER_MAX_BODY", KB))
+	assert.Equal(t, KB, GetEnv("BYTESIZER_EMPTY", KB))
+	assert.Equal(t, KB, GetEnv("BYTESIZER_INVALID", KB))
+	assert.Equal(t, KB, GetEnv("BYTESIZER_UNSET", KB))
+}
+
+func TestLookupEnv(t *testing.T) {
+	t.Setenv("BYTESIZER_MAX_BODY", "1.5KB")
+	t.Setenv("BYTESIZER_INVALID", "lots")
+
+	size, ok, err := LookupEnv("BYTESIZER_MAX_BODY")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, ByteSize(1536), size)
+
+	_, ok, err = LookupEnv("BYTESIZER_UNSET")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, ok, err = LookupEnv("BYTESIZER_INVALID")
+	assert.True(t, ok)
+	assert.ErrorContains(t, err, "BYTESIZER_INVALID")
+}
+
+func TestByteSizeFlagValue(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	size := 4 * KB
+	fs.Var(&size, "size", "size")
+
+	assert.NoError(t, fs.Parse([]string{"-size", "2MB"}))
+	assert.Equal(t, 2*MB, size)
+	assert.Error(t, fs.Parse([]string{"-size", "lots"}))
+}
+
+type envSpec struct {
+	MaxBody ByteSize `envconfig:"MAX_BODY" env:"MAX_BODY"`
+	Cache   ByteSize `envconfig:"CACHE" env:"CACHE"`
+}
+
+func TestEnvconfig(t *testing.T) {
+	t.Setenv("APP_MAX_BODY", "10MB")
+	t.Setenv("APP_CACHE", "2048")
+
+	var spec envSpec
+	assert.NoError(t, envconfig.Process("app", &spec))
+	assert.Equal(t, envSpec{MaxBody: 10 * MB, Cache: 2 * KB}, spec)
+
+	t.Setenv("APP_CACHE", "lots")
+	assert.Error(t, envconfig.Process("app", &spec))
+}
+
+func TestCaarlos0Env(t *testing.T) {
+	t.Setenv("MAX_BODY", "10MB")
+	t.Setenv("CACHE", "1.5KB")
+
+	var spec envSpec
+	assert.NoError(t, env.Parse(&spec))
+	assert.Equal(t, envSpec{MaxBody: 10 * MB, Cache: 1536}, spec)
+
+	t.Setenv("CACHE", "lots")
+	assert.Error(t, env.Parse(&spec))
+}
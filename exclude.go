@@ -0,0 +1,110 @@
+package bytesizer
+
+import (
+	"path"
+	"strings"
+)
+
+// excludePattern is one compiled .gitignore-style pattern.
+type excludePattern struct {
+	segments []string // split on "/", "**" matches any number of segments
+	anchored bool     // contains a slash, so it matches from the walk root
+	dirOnly  bool     // ends with a slash
+	negate   bool     // starts with "!"
+}
+
+// excluder matches slash-separated paths relative to the walk root against
+// .gitignore-style patterns. The last matching pattern wins.
+type excluder []excludePattern
+
+// compileExcludes parses patterns in .gitignore syntax: blank lines and
+// lines starting with "#" are ignored, "!" negates, a trailing "/" only
+// matches directories, a pattern containing a "/" is relative to the root,
+// and "**" matches any number of directories.
+func compileExcludes(patterns []string) (excluder, error) {
+	var ex excluder
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" || strings.HasPrefix(p, "#") {
+			continue
+		}
+
+		var pat excludePattern
+		if p[0] == '!' {
+			pat.negate = true
+			p = p[1:]
+		}
+		if s, ok := trimSuffix(p, "/"); ok {
+			pat.dirOnly = true
+			p = s
+		}
+		if strings.Contains(p, "/") {
+			pat.anchored = true
+			p = strings.TrimPrefix(p, "/")
+		}
+		if p == "" {
+			continue
+		}
+		pat.segments = strings.Split(p, "/")
+
+		// Check the syntax now so bad patterns fail before the walk.
+		for _, seg := range pat.segments {
+			if _, err := path.Match(seg, ""); err != nil {
+				return nil, err
+			}
+		}
+		ex = append(ex, pat)
+	}
+	return ex, nil
+}
+
+// excluded reports whether rel, a slash-separated path relative to the walk
+// root, is excluded.
+func (ex excluder) excluded(rel string, isDir bool) bool {
+	excluded := false
+	parts := strings.Split(rel, "/")
+	for _, pat := range ex {
+		if pat.dirOnly && !isDir || excluded != pat.negate {
+			continue
+		}
+		if pat.matches(parts) {
+			excluded = !pat.negate
+		}
+	}
+	return excluded
+}
+
+func (p excludePattern) matches(parts []string) bool {
+	if p.anchored {
+		return matchSegments(p.segments, parts)
+	}
+	// Unanchored patterns match at any depth.
+	for i := range parts {
+		if matchSegments(p.segments, parts[i:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchSegments matches a whole path against pattern segments.
+func matchSegments(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchSegments(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], parts[1:])
+}
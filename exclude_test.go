@@ -0,0 +1,44 @@
+package bytesizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExcluder(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"Basename at root", []string{"*.log"}, "a.log", false, true},
+		{"Basename nested", []string{"*.log"}, "x/y/a.log", false, true},
+		{"Basename no match", []string{"*.log"}, "a.txt", false, false},
+		{"Anchored", []string{"/build"}, "build", true, true},
+		{"Anchored nested", []string{"/build"}, "src/build", true, false},
+		{"Slash anchors", []string{"cache/tmp"}, "cache/tmp", true, true},
+		{"Slash anchors nested", []string{"cache/tmp"}, "x/cache/tmp", true, false},
+		{"Dir only matches dir", []string{"node_modules/"}, "a/node_modules", true, true},
+		{"Dir only skips file", []string{"node_modules/"}, "a/node_modules", false, false},
+		{"Double star", []string{"a/**/z.bin"}, "a/b/c/z.bin", false, true},
+		{"Double star zero dirs", []string{"a/**/z.bin"}, "a/z.bin", false, true},
+		{"Leading double star", []string{"**/tmp"}, "x/y/tmp", true, true},
+		{"Negation", []string{"*.log", "!keep.log"}, "keep.log", false, false},
+		{"Negation then exclude", []string{"*.log", "!keep.log", "keep*"}, "keep.log", false, true},
+		{"Comments and blanks", []string{"# *.log", "", "  "}, "a.log", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ex, err := compileExcludes(tt.patterns)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, ex.excluded(tt.path, tt.isDir))
+		})
+	}
+
+	_, err := compileExcludes([]string{"[a-"})
+	assert.Error(t, err)
+}
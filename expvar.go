@@ -0,0 +1,51 @@
+package bytesizer
+
+import (
+	"encoding/json"
+	"expvar"
+)
+
+// expvarValue is the JSON form of a size published through expvar.
+type expvarValue struct {
+	Bytes int64  `json:"bytes"`
+	Human string `json:"human"`
+}
+
+func newExpvarValue(size ByteSize) expvarValue {
+	return expvarValue{Bytes: int64(size), Human: size.String()}
+}
+
+// Var is an expvar.Var holding a ByteSize that can be updated concurrently,
+// with the methods of Counter. It is published as a JSON object with the
+// raw and humanized size, e.g. {"bytes":1572864,"human":"1.5MB"}.
+//
+// ByteSize itself is not a valid expvar.Var: its String method does not
+// produce JSON. Use Var for counters and Func for computed sizes.
+type Var struct {
+	Counter
+}
+
+var _ expvar.Var = (*Var)(nil)
+
+// NewVar creates a new Var and publishes it under name, like expvar.NewInt.
+func NewVar(name string) *Var {
+	v := new(Var)
+	expvar.Publish(name, v)
+	return v
+}
+
+// String implements expvar.Var, returning v as a JSON object.
+func (v *Var) String() string {
+	b, _ := json.Marshal(newExpvarValue(v.Load()))
+	return string(b)
+}
+
+// Func returns an expvar.Var that reports the size returned by f in the same
+// JSON form as Var, for sizes computed on demand:
+//
+//	expvar.Publish("cache_bytes", bytesizer.Func(cache.Size))
+func Func(f func() ByteSize) expvar.Func {
+	return func() interface{} {
+		return newExpvarValue(f())
+	}
+}
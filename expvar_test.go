@@ -0,0 +1,45 @@
+package bytesizer
+
+import (
+	"encoding/json"
+	"expvar"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVar(t *testing.T) {
+	v := NewVar("bytesizer_test_var")
+	assert.Same(t, v, expvar.Get("bytesizer_test_var"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 128; j++ {
+				v.Add(KB)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, MB, v.Load())
+	assert.Equal(t, `{"bytes":1048576,"human":"1MB"}`, v.String())
+
+	v.Store(1536 * KB)
+	assert.Equal(t, 1536*KB+KB, v.Add(KB))
+	assert.True(t, json.Valid([]byte(v.String())))
+}
+
+func TestFunc(t *testing.T) {
+	size := 1536 * KB
+	expvar.Publish("bytesizer_test_func", Func(func() ByteSize { return size }))
+
+	got := expvar.Get("bytesizer_test_func").String()
+	assert.Equal(t, `{"bytes":1572864,"human":"1.5MB"}`, got)
+
+	size = 0
+	assert.Equal(t, `{"bytes":0,"human":"0B"}`, expvar.Get("bytesizer_test_func").String())
+}
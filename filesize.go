@@ -0,0 +1,48 @@
+package bytesizer
+
+import (
+	"io/fs"
+	"os"
+)
+
+// defaultBlockSize is the allocation unit assumed when the platform does
+// not report the blocks used by a file.
+const defaultBlockSize = 4 * KB
+
+// FileSize returns the apparent size of the file at path, as shown by
+// "ls -l". Symbolic links are followed.
+func FileSize(path string) (ByteSize, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return ByteSize(info.Size()), nil
+}
+
+// FileSizeOnDisk returns the space the file at path takes on disk, as shown
+// by "du". It can be smaller than FileSize for sparse or compressed files,
+// and larger for small files. Symbolic links are followed.
+//
+// On Unix systems it is derived from st_blocks. Elsewhere it is the apparent
+// size rounded up to a 4KB block.
+func FileSizeOnDisk(path string) (ByteSize, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return sizeOnDisk(info), nil
+}
+
+// sizeOnDisk returns the size used by the file described by info.
+func sizeOnDisk(info fs.FileInfo) ByteSize {
+	if size, ok := allocatedSize(info); ok {
+		return size
+	}
+	size := ByteSize(info.Size())
+	return (size + defaultBlockSize - 1) / defaultBlockSize * defaultBlockSize
+}
+
+// fileKey identifies a file independently of its path.
+type fileKey struct {
+	dev, ino uint64
+}
@@ -0,0 +1,20 @@
+//go:build !unix
+
+package bytesizer
+
+import "io/fs"
+
+// allocatedSize is not available on this platform.
+func allocatedSize(info fs.FileInfo) (ByteSize, bool) {
+	return 0, false
+}
+
+// fileID is not available on this platform.
+func fileID(info fs.FileInfo) (fileKey, bool) {
+	return fileKey{}, false
+}
+
+// linkCount is not available on this platform, every file has one link.
+func linkCount(info fs.FileInfo) uint64 {
+	return 1
+}
@@ -0,0 +1,60 @@
+package bytesizer
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileSize(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "small.bin")
+	assert.NoError(t, os.WriteFile(p, make([]byte, 100), 0o644))
+
+	size, err := FileSize(p)
+	assert.NoError(t, err)
+	assert.Equal(t, ByteSize(100), size)
+
+	onDisk, err := FileSizeOnDisk(p)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, onDisk, size)
+
+	_, err = FileSize(filepath.Join(t.TempDir(), "missing"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = FileSizeOnDisk(filepath.Join(t.TempDir(), "missing"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestFileSizeOnDiskSparse(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sparse files need explicit support on windows")
+	}
+
+	p := filepath.Join(t.TempDir(), "sparse.bin")
+	f, err := os.Create(p)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Truncate(int64(64*MB)))
+	assert.NoError(t, f.Close())
+
+	size, err := FileSize(p)
+	assert.NoError(t, err)
+	assert.Equal(t, 64*MB, size)
+
+	onDisk, err := FileSizeOnDisk(p)
+	assert.NoError(t, err)
+	assert.Less(t, onDisk, size)
+}
+
+func TestSizeOnDiskFallback(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "f.bin")
+	assert.NoError(t, os.WriteFile(p, make([]byte, 5000), 0o644))
+	info, err := os.Stat(p)
+	assert.NoError(t, err)
+	assert.Equal(t, 8*KB, sizeOnDisk(fileInfoWithoutSys{info}))
+}
+
+type fileInfoWithoutSys struct{ os.FileInfo }
+
+func (fileInfoWithoutSys) Sys() interface{} { return nil }
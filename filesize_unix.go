@@ -0,0 +1,37 @@
+//go:build unix
+
+package bytesizer
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// allocatedSize returns the space allocated to a file, from st_blocks, which
+// counts 512-byte blocks on every Unix system.
+func allocatedSize(info fs.FileInfo) (ByteSize, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return ByteSize(st.Blocks) * 512, true
+}
+
+// fileID returns the device and inode of a file, which identify it across
+// hard links.
+func fileID(info fs.FileInfo) (fileKey, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileKey{}, false
+	}
+	return fileKey{dev: uint64(st.Dev), ino: uint64(st.Ino)}, true
+}
+
+// linkCount returns the number of hard links to a file.
+func linkCount(info fs.FileInfo) uint64 {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 1
+	}
+	return uint64(st.Nlink)
+}
@@ -0,0 +1,50 @@
+package bytesizer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	// ErrNotFinite is returned for a NaN or infinite float64 given as a
+	// size or rate.
+	ErrNotFinite = errors.New("size is not a finite number")
+	// ErrOutOfRange is returned for a size that does not fit in a ByteSize.
+	ErrOutOfRange = errors.New("size out of range")
+)
+
+// FromFloat converts a number of bytes to a ByteSize, dropping any fraction
+// of a byte. Unlike a conversion, it returns an error wrapping ErrNotFinite
+// for NaN and infinities, and ErrOutOfRange beyond the range of ByteSize,
+// -Unlimited to Unlimited.
+func FromFloat(bytes float64) (ByteSize, error) {
+	switch {
+	case math.IsNaN(bytes) || math.IsInf(bytes, 0):
+		return 0, fmt.Errorf("%w: %v", ErrNotFinite, bytes)
+	case bytes >= -float64(math.MinInt) || bytes <= float64(math.MinInt):
+		return 0, fmt.Errorf("%w: %v", ErrOutOfRange, bytes)
+	}
+	return ByteSize(bytes), nil
+}
+
+// MulFloat returns fs times f, such as a size grown by 1.5, with the errors
+// of FromFloat.
+func (fs ByteSize) MulFloat(f float64) (ByteSize, error) {
+	return FromFloat(float64(fs) * f)
+}
+
+// clampFloat converts a number of bytes to a ByteSize for results that
+// cannot fail: NaN is 0, and numbers beyond the range of ByteSize are
+// Unlimited or -Unlimited.
+func clampFloat(bytes float64) ByteSize {
+	switch {
+	case math.IsNaN(bytes):
+		return 0
+	case bytes >= float64(Unlimited):
+		return Unlimited
+	case bytes <= -float64(Unlimited):
+		return -Unlimited
+	}
+	return ByteSize(bytes)
+}
@@ -0,0 +1,156 @@
+package bytesizer
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromFloat(t *testing.T) {
+	tests := []struct {
+		name string
+		in   float64
+		want ByteSize
+		err  error
+	}{
+		{"Whole", 1024, KB, nil},
+		{"Fraction dropped", 1.9, 1, nil},
+		{"Negative fraction dropped", -1.9, -1, nil},
+		{"Largest", 1 << 62, 1 << 62, nil},
+		{"Smallest", -(1 << 62), -(1 << 62), nil},
+		{"Unknown", math.MinInt64, 0, ErrOutOfRange},
+		{"NaN", math.NaN(), 0, ErrNotFinite},
+		{"Inf", math.Inf(1), 0, ErrNotFinite},
+		{"Negative Inf", math.Inf(-1), 0, ErrNotFinite},
+		{"Too large", 1 << 63, 0, ErrOutOfRange},
+		{"Too small", -1e19, 0, ErrOutOfRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FromFloat(tt.in)
+			if tt.err != nil {
+				assert.True(t, errors.Is(err, tt.err), "got %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMulFloat(t *testing.T) {
+	got, err := (2 * GB).MulFloat(1.5)
+	assert.NoError(t, err)
+	assert.Equal(t, 3*GB, got)
+
+	_, err = GB.MulFloat(math.NaN())
+	assert.True(t, errors.Is(err, ErrNotFinite))
+	_, err = PB.MulFloat(1e6)
+	assert.True(t, errors.Is(err, ErrOutOfRange))
+}
+
+func TestNonFinite(t *testing.T) {
+	for _, s := range []string{"NaNKB", "InfMB", "-InfGB", "+InfB", "Infinity", "nan"} {
+		t.Run(s, func(t *testing.T) {
+			_, err := Parse(s)
+			assert.Error(t, err)
+			_, err = ParseRate(s + "/s")
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := Parse("NaNKB")
+	assert.True(t, errors.Is(err, ErrNotFinite))
+	_, err = Parse("1e30PB")
+	assert.True(t, errors.Is(err, ErrOutOfRange))
+	_, err = Parse("1e400")
+	assert.True(t, errors.Is(err, ErrOutOfRange))
+	_, err = ParseRate("1e300PB/1ns")
+	assert.True(t, errors.Is(err, ErrOutOfRange))
+
+	assert.Equal(t, "NaN", formatString(math.NaN(), "MB", 2))
+	assert.Equal(t, "+Inf", string(AppendSize(nil, math.Inf(1), "GB", 2)))
+	assert.Equal(t, "-Inf", string(AppendSize(nil, math.Inf(-1), "GB", 2)))
+	assert.Equal(t, "NaN", Rate(math.NaN()).String())
+	assert.Equal(t, "+Inf", Rate(math.Inf(1)).String())
+
+	_, err = Rate(math.Inf(1)).MarshalText()
+	assert.True(t, errors.Is(err, ErrNotFinite))
+
+	assert.Equal(t, Unlimited, Rate(math.Inf(1)).Over(time.Second))
+	assert.Equal(t, -Unlimited, Rate(math.Inf(-1)).Over(time.Second))
+	assert.Equal(t, ByteSize(0), Rate(math.NaN()).Over(time.Second))
+
+	_, err = NewWatermarkPercent(GB, math.NaN(), 50, nil)
+	assert.True(t, errors.Is(err, ErrNotFinite))
+}
+
+// TestDecodeFloat checks that the decoders accepting float numbers of bytes
+// return the errors of FromFloat.
+func TestDecodeFloat(t *testing.T) {
+	le := func(f float64) []byte {
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, math.Float64bits(f))
+		return b
+	}
+	be := func(prefix byte, f float64) []byte {
+		b := []byte{prefix, 0, 0, 0, 0, 0, 0, 0, 0}
+		binary.BigEndian.PutUint64(b[1:], math.Float64bits(f))
+		return b
+	}
+	tmpl := func(v any) error {
+		t := template.Must(template.New("").Funcs(TemplateFuncs()).Parse(`{{bytesize .}}`))
+		return t.Execute(io.Discard, v)
+	}
+
+	tests := []struct {
+		name   string
+		decode func(f float64) error
+	}{
+		{"JSON", func(f float64) error {
+			var size ByteSize
+			return size.UnmarshalJSON([]byte(strconv.FormatFloat(f, 'g', -1, 64)))
+		}},
+		{"BSON", func(f float64) error {
+			var size ByteSize
+			return size.UnmarshalBSONValue(bsonDouble, le(f))
+		}},
+		{"CBOR", func(f float64) error {
+			var size ByteSize
+			return size.UnmarshalCBOR(be(0xfb, f))
+		}},
+		{"MessagePack", func(f float64) error {
+			var size ByteSize
+			return size.UnmarshalMsgpack(be(0xcb, f))
+		}},
+		{"SQL", func(f float64) error {
+			var size ByteSize
+			return size.Scan(f)
+		}},
+		{"mapstructure", func(f float64) error {
+			_, err := toByteSize(f)
+			return err
+		}},
+		{"Template", func(f float64) error { return tmpl(f) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.NoError(t, tt.decode(1536))
+			assert.ErrorIs(t, tt.decode(1e30), ErrOutOfRange)
+			assert.ErrorIs(t, tt.decode(-1e30), ErrOutOfRange)
+			if tt.name != "JSON" { // JSON has no NaN or infinities
+				assert.ErrorIs(t, tt.decode(math.NaN()), ErrNotFinite)
+				assert.ErrorIs(t, tt.decode(math.Inf(1)), ErrNotFinite)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package bytesizer
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// SizeSample is a size measured at a point in time, such as the usage of a
+// volume.
+type SizeSample struct {
+	Time time.Time
+	Size ByteSize
+}
+
+// GrowthModel is the shape of the curve fitted by FitGrowth.
+type GrowthModel int
+
+const (
+	// LinearGrowth fits a straight line: the size grows by the same number
+	// of bytes each day.
+	LinearGrowth GrowthModel = iota
+	// ExponentialGrowth fits an exponential curve: the size grows by the
+	// same percentage each day.
+	ExponentialGrowth
+)
+
+// Forecast projects sizes from a curve fitted to samples by FitGrowth.
+//
+//	f, err := FitGrowth(samples, LinearGrowth)
+//	if err != nil {
+//		return err
+//	}
+//	if full, ok := f.When(2 * TB); ok {
+//		log.Printf("volume full by %s", full.Format("2006-01-02"))
+//	}
+type Forecast struct {
+	model  GrowthModel
+	origin time.Time
+	// With t the seconds since origin, the size is a + b*t for linear
+	// growth, and exp(a + b*t) for exponential growth.
+	a, b float64
+}
+
+// FitGrowth fits model to samples by least squares. It needs at least two
+// samples at different times, and for ExponentialGrowth, sizes above zero.
+func FitGrowth(samples []SizeSample, model GrowthModel) (*Forecast, error) {
+	if len(samples) < 2 {
+		return nil, errors.New("forecast needs at least two samples")
+	}
+
+	origin := samples[0].Time
+	for _, s := range samples {
+		if s.Time.Before(origin) {
+			origin = s.Time
+		}
+	}
+
+	var n, sx, sy, sxx, sxy float64
+	for _, s := range samples {
+		x := s.Time.Sub(origin).Seconds()
+		y := float64(s.Size)
+		if model == ExponentialGrowth {
+			if s.Size <= 0 {
+				return nil, errors.New("exponential forecast needs sizes above zero")
+			}
+			y = math.Log(y)
+		}
+		n++
+		sx += x
+		sy += y
+		sxx += x * x
+		sxy += x * y
+	}
+
+	d := n*sxx - sx*sx
+	if d == 0 {
+		return nil, errors.New("forecast needs samples at different times")
+	}
+	b := (n*sxy - sx*sy) / d
+	a := (sy - b*sx) / n
+	return &Forecast{model: model, origin: origin, a: a, b: b}, nil
+}
+
+// SizeAt returns the projected size at t.
+func (f *Forecast) SizeAt(t time.Time) ByteSize {
+	x := f.a + f.b*t.Sub(f.origin).Seconds()
+	if f.model == ExponentialGrowth {
+		x = math.Exp(x)
+	}
+	return clampFloat(math.Round(x))
+}
+
+// When returns the time at which the projected size reaches size, which may
+// be in the past. It returns false if the projection never reaches it, such
+// as when usage is flat or shrinking.
+func (f *Forecast) When(size ByteSize) (time.Time, bool) {
+	y := float64(size)
+	if f.model == ExponentialGrowth {
+		if size <= 0 {
+			return time.Time{}, false
+		}
+		y = math.Log(y)
+	}
+	if f.b <= 0 {
+		return time.Time{}, false
+	}
+	secs := (y - f.a) / f.b
+	if math.Abs(secs) > float64(math.MaxInt64)/float64(time.Second) {
+		return time.Time{}, false
+	}
+	return f.origin.Add(time.Duration(secs * float64(time.Second))), true
+}
+
+// GrowthRate returns how fast the projected size grows at t.
+func (f *Forecast) GrowthRate(t time.Time) Rate {
+	if f.model == ExponentialGrowth {
+		return Rate(f.b * float64(f.SizeAt(t)))
+	}
+	return Rate(f.b)
+}
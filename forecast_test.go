@@ -0,0 +1,86 @@
+package bytesizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFitGrowth(t *testing.T) {
+	day := 24 * time.Hour
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	samples := func(sizes ...ByteSize) []SizeSample {
+		var s []SizeSample
+		for i, size := range sizes {
+			s = append(s, SizeSample{Time: start.Add(time.Duration(i) * day), Size: size})
+		}
+		return s
+	}
+
+	t.Run("Linear", func(t *testing.T) {
+		f, err := FitGrowth(samples(100*GB, 110*GB, 120*GB, 130*GB), LinearGrowth)
+		assert.NoError(t, err)
+		assert.Equal(t, 200*GB, f.SizeAt(start.Add(10*day)))
+
+		when, ok := f.When(TB)
+		assert.True(t, ok)
+		assert.WithinDuration(t, start.Add(92*day+9*time.Hour+36*time.Minute), when, time.Second)
+		assert.InDelta(t, float64(10*GB)/86400, float64(f.GrowthRate(start)), 1e-6)
+	})
+
+	t.Run("Noisy", func(t *testing.T) {
+		f, err := FitGrowth(samples(100*GB, 112*GB, 118*GB, 130*GB), LinearGrowth)
+		assert.NoError(t, err)
+		assert.InDelta(t, float64(130*GB), float64(f.SizeAt(start.Add(3*day))), float64(GB))
+	})
+
+	t.Run("Exponential", func(t *testing.T) {
+		f, err := FitGrowth(samples(GB, 2*GB, 4*GB, 8*GB), ExponentialGrowth)
+		assert.NoError(t, err)
+		assert.InDelta(t, float64(64*GB), float64(f.SizeAt(start.Add(6*day))), 1e3)
+
+		when, ok := f.When(TB)
+		assert.True(t, ok)
+		assert.WithinDuration(t, start.Add(10*day), when, time.Second)
+		assert.InDelta(t, float64(8*GB)*0.693/86400, float64(f.GrowthRate(start.Add(3*day))), 1e3)
+	})
+
+	t.Run("Unordered", func(t *testing.T) {
+		s := samples(100*GB, 110*GB, 120*GB)
+		s[0], s[2] = s[2], s[0]
+		f, err := FitGrowth(s, LinearGrowth)
+		assert.NoError(t, err)
+		assert.Equal(t, 100*GB, f.SizeAt(start))
+	})
+
+	t.Run("Shrinking", func(t *testing.T) {
+		f, err := FitGrowth(samples(3*GB, 2*GB, GB), LinearGrowth)
+		assert.NoError(t, err)
+		_, ok := f.When(10 * GB)
+		assert.False(t, ok)
+	})
+
+	t.Run("Overflow", func(t *testing.T) {
+		f, err := FitGrowth(samples(GB, 4*GB), ExponentialGrowth)
+		assert.NoError(t, err)
+		assert.Equal(t, Unlimited, f.SizeAt(start.Add(1000*day)))
+	})
+
+	errTests := []struct {
+		name    string
+		samples []SizeSample
+		model   GrowthModel
+		wantErr string
+	}{
+		{"One sample", samples(GB), LinearGrowth, "at least two samples"},
+		{"Same time", []SizeSample{{start, GB}, {start, 2 * GB}}, LinearGrowth, "different times"},
+		{"Zero size", samples(0, GB), ExponentialGrowth, "sizes above zero"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FitGrowth(tt.samples, tt.model)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
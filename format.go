@@ -0,0 +1,280 @@
+package bytesizer
+
+import (
+	"io"
+	"math"
+	"strconv"
+	"sync"
+)
+
+// FormatOption configures FormatAll and FormatAllTo.
+type FormatOption func(*formatConfig)
+
+type formatConfig struct {
+	unit      ByteSize // 0 to pick a unit for each size, like String
+	precision int
+	boundary  bool
+	locale    *Locale // nil for String's English
+	long      bool    // long unit names, for Humanize
+	bidi      BidiMode
+}
+
+// WithUnit formats all sizes in unit, one of Byte to PB, like Format.
+func WithUnit(unit ByteSize) FormatOption {
+	return func(c *formatConfig) {
+		c.unit = unit
+	}
+}
+
+// WithPrecision sets the most decimals printed, 2 by default. Trailing
+// zeros are dropped as in String. A negative n prints as many as needed,
+// up to ten like AppendSize, so sizes far below the unit round to 0: 1B in
+// PB is "0PB".
+func WithPrecision(n int) FormatOption {
+	return func(c *formatConfig) {
+		c.precision = n
+	}
+}
+
+// BoundaryAware prints more decimals than the precision when rounding would
+// make a size look like a whole number of units when it is not, so that
+// sizes just over or under a unit boundary stand out: 1025KB is "1.001MB"
+// instead of "1.00MB", and 1GB-1 "1023.999999MB" instead of "1024.00MB".
+func BoundaryAware() FormatOption {
+	return func(c *formatConfig) {
+		c.boundary = true
+	}
+}
+
+// formatUnit is a unit with its size converted ahead of time.
+type formatUnit struct {
+	size float64
+	name string
+}
+
+// formatUnits are the units, largest first.
+var formatUnits = func() []formatUnit {
+	fu := make([]formatUnit, len(units))
+	for i, u := range units {
+		fu[len(units)-1-i] = formatUnit{float64(u.size), u.unitName}
+	}
+	return fu
+}()
+
+func newFormatConfig(opts []FormatOption) formatConfig {
+	if len(opts) == 0 {
+		return formatConfig{precision: 2}
+	}
+	c := &formatConfig{precision: 2}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return *c
+}
+
+// appendSize appends size to dst as configured.
+func (c formatConfig) appendSize(dst []byte, size ByteSize) []byte {
+	if c.bidi != BidiNone {
+		return c.appendBidi(dst, size)
+	}
+	if c.locale != nil {
+		return c.appendLocalized(dst, size)
+	}
+	switch size {
+	case Unlimited:
+		return append(dst, unlimitedName...)
+	case Unknown:
+		return append(dst, unknownName...)
+	}
+	v := float64(size)
+	u, ok := formatUnit{}, false
+	if c.unit != 0 {
+		for _, fu := range formatUnits {
+			if fu.size == float64(c.unit) {
+				u, ok = fu, true
+				break
+			}
+		}
+		// Like Format, other units fall back to picking one.
+	}
+	if !ok {
+		u = formatUnits[len(formatUnits)-1]
+		for _, fu := range formatUnits {
+			if math.Abs(v) >= fu.size {
+				u = fu
+				break
+			}
+		}
+	}
+	if u.size == 1 {
+		// Exact, where a float64 would round sizes beyond 2^53.
+		dst = strconv.AppendInt(dst, int64(size), 10)
+		return append(dst, u.name...)
+	}
+	v /= u.size
+	prec := c.precision
+	if c.boundary {
+		prec = boundaryPrecision(v, prec)
+	}
+	if math.Abs(float64(size)) >= 0x1p63-u.size {
+		v = roundInRange(size, ByteSize(u.size), v, prec)
+	}
+	return AppendSize(dst, v, u.name, prec)
+}
+
+// roundInRange returns v, size in unit, to be rounded to prec decimals like
+// AppendSize, but truncated toward zero if rounding up would print a size
+// beyond the range of ByteSize, which Parse would reject. Near that range,
+// a float64 cannot tell size from the next unit, so the whole units and the
+// fraction are split in integers first.
+func roundInRange(size, unit ByteSize, v float64, prec int) float64 {
+	d := decimalPlaces(v)
+	if prec >= 0 && d > prec {
+		d = prec
+	}
+	p := math.Pow(10, float64(d))
+	if n := math.Round(v*p) / p * float64(unit); n < 0x1p63 && n > -0x1p63 {
+		return v
+	}
+	if d = prec; d < 0 {
+		d = maxDecimalPlaces
+	}
+	p = math.Pow(10, float64(d))
+	frac := float64(size%unit) / float64(unit)
+	if w := float64(size/unit) + math.Trunc(frac*p)/p; math.Abs(w)*float64(unit) < 0x1p63 {
+		return w
+	}
+	// No room for a fraction in a float64.
+	return float64(size / unit)
+}
+
+// boundaryPrecision returns the decimals, from prec up to maxDecimalPlaces,
+// needed for v not to round to a whole number, unless it is one.
+func boundaryPrecision(v float64, prec int) int {
+	if prec < 0 || v == math.Trunc(v) {
+		return prec
+	}
+	for ; prec < maxDecimalPlaces; prec++ {
+		p := math.Pow(10, float64(prec))
+		if r := math.Round(v*p) / p; r != math.Trunc(r) {
+			break
+		}
+	}
+	return prec
+}
+
+// Formatter formats sizes with a fixed set of options, for the options of
+// FormatAll one size at a time. Create one with NewFormatter.
+//
+//	f := NewFormatter(WithUnit(MB), BoundaryAware())
+//	label := f.Format(size)
+type Formatter struct {
+	c formatConfig
+}
+
+// NewFormatter returns a Formatter formatting sizes like String, or as set
+// by opts.
+func NewFormatter(opts ...FormatOption) Formatter {
+	return Formatter{c: newFormatConfig(opts)}
+}
+
+// Format returns size formatted.
+func (f Formatter) Format(size ByteSize) string {
+	var buf [32]byte
+	return string(f.c.appendSize(buf[:0], size))
+}
+
+// AppendFormat appends size formatted to dst and returns the extended
+// buffer.
+func (f Formatter) AppendFormat(dst []byte, size ByteSize) []byte {
+	return f.c.appendSize(dst, size)
+}
+
+// AppendFormat appends fs formatted in unit to dst, like Format, and returns
+// the extended buffer. A unit of 0 picks one like String. It does not
+// allocate if dst has room.
+//
+//	buf = size.AppendFormat(buf, MB)
+func (fs ByteSize) AppendFormat(dst []byte, unit ByteSize) []byte {
+	return formatConfig{unit: unit, precision: 2}.appendSize(dst, fs)
+}
+
+// formatBuffer holds the memory reused by FormatAll and FormatAllTo.
+type formatBuffer struct {
+	b    []byte
+	ends []int
+}
+
+var formatBuffers = sync.Pool{
+	New: func() any { return new(formatBuffer) },
+}
+
+// maxPooledFormatBuffer is the largest buffer kept for reuse, so one huge
+// report does not pin memory.
+const maxPooledFormatBuffer = 1 << 20
+
+func putFormatBuffer(fb *formatBuffer) {
+	if cap(fb.b) <= maxPooledFormatBuffer && cap(fb.ends) <= maxPooledFormatBuffer/8 {
+		formatBuffers.Put(fb)
+	}
+}
+
+// FormatAll formats sizes like String, or as set by opts, for reports
+// formatting many sizes at once. It reuses buffers between calls and
+// allocates the text of all the sizes together, so the returned strings
+// share memory: keeping any of them keeps the others.
+//
+//	cells := FormatAll(sizes, WithUnit(MB), WithPrecision(1))
+func FormatAll(sizes []ByteSize, opts ...FormatOption) []string {
+	c := newFormatConfig(opts)
+	fb := formatBuffers.Get().(*formatBuffer)
+	defer putFormatBuffer(fb)
+
+	b, ends := fb.b[:0], fb.ends[:0]
+	for _, s := range sizes {
+		b = c.appendSize(b, s)
+		ends = append(ends, len(b))
+	}
+	fb.b, fb.ends = b, ends
+
+	all := string(b)
+	out := make([]string, len(sizes))
+	start := 0
+	for i, end := range ends {
+		out[i] = all[start:end]
+		start = end
+	}
+	return out
+}
+
+// FormatAllTo writes sizes to w like FormatAll, each followed by suffix,
+// such as "\n". It returns the number of bytes written.
+func FormatAllTo(w io.Writer, sizes []ByteSize, suffix string, opts ...FormatOption) (int, error) {
+	const flushAt = 32 << 10
+
+	c := newFormatConfig(opts)
+	fb := formatBuffers.Get().(*formatBuffer)
+	defer putFormatBuffer(fb)
+
+	written := 0
+	b := fb.b[:0]
+	for _, s := range sizes {
+		b = c.appendSize(b, s)
+		b = append(b, suffix...)
+		if len(b) >= flushAt {
+			n, err := w.Write(b)
+			written += n
+			if err != nil {
+				fb.b = b
+				return written, err
+			}
+			b = b[:0]
+		}
+	}
+	fb.b = b
+	if len(b) == 0 {
+		return written, nil
+	}
+	n, err := w.Write(b)
+	return written + n, err
+}
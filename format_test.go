@@ -0,0 +1,114 @@
+package bytesizer
+
+import (
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatAll(t *testing.T) {
+	sizes := []ByteSize{0, 532, 1536 * KB, 1025 * KB, 3*GB + 123456789, 2 * PB, -2048}
+
+	tests := []struct {
+		name string
+		opts []FormatOption
+		want []string
+	}{
+		{"Default", nil, []string{"0B", "532B", "1.5MB", "1.00MB", "3.11GB", "2PB", "-2KB"}},
+		{"Unit", []FormatOption{WithUnit(KB)}, []string{"0KB", "0.52KB", "1536KB", "1025KB", "3266291.27KB", "2199023255552KB", "-2KB"}},
+		{"Precision", []FormatOption{WithPrecision(0)}, []string{"0B", "532B", "2MB", "1MB", "3GB", "2PB", "-2KB"}},
+		{"Any precision", []FormatOption{WithPrecision(-1)}, []string{"0B", "532B", "1.5MB", "1.0009765625MB", "3.1149780946GB", "2PB", "-2KB"}},
+		{"Unknown unit", []FormatOption{WithUnit(1000)}, []string{"0B", "532B", "1.5MB", "1.00MB", "3.11GB", "2PB", "-2KB"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatAll(sizes, tt.opts...))
+
+			var b strings.Builder
+			n, err := FormatAllTo(&b, sizes, "\n", tt.opts...)
+			assert.NoError(t, err)
+			assert.Equal(t, strings.Join(tt.want, "\n")+"\n", b.String())
+			assert.Equal(t, b.Len(), n)
+		})
+	}
+
+	assert.Empty(t, FormatAll(nil))
+}
+
+func TestFormatter(t *testing.T) {
+	tests := []struct {
+		name string
+		size ByteSize
+		opts []FormatOption
+		want string
+	}{
+		{"Default", 1536 * KB, nil, "1.5MB"},
+		{"Rounded keeps decimals", 1025 * KB, nil, "1.00MB"},
+		{"Unit", 1536 * KB, []FormatOption{WithUnit(KB)}, "1536KB"},
+		{"Boundary just over", 1025 * KB, []FormatOption{BoundaryAware()}, "1.001MB"},
+		{"Boundary just under", GB - 1, []FormatOption{BoundaryAware()}, "1023.999999MB"},
+		{"Boundary just under a unit", GB - 1, []FormatOption{BoundaryAware(), WithUnit(GB)}, "0.999999999GB"},
+		{"Boundary whole", 2 * MB, []FormatOption{BoundaryAware()}, "2MB"},
+		{"Boundary not near", 1536 * KB, []FormatOption{BoundaryAware()}, "1.5MB"},
+		{"Boundary small difference", MB + 3*MB/1000, []FormatOption{BoundaryAware()}, "1.003MB"},
+		{"Boundary no decimals", 1025 * KB, []FormatOption{BoundaryAware(), WithPrecision(0)}, "1.001MB"},
+		{"Boundary any precision", 1025 * KB, []FormatOption{BoundaryAware(), WithPrecision(-1)}, "1.0009765625MB"},
+		{"Any precision cap", 1, []FormatOption{WithUnit(PB), WithPrecision(-1)}, "0PB"},
+		{"Boundary bytes", 1023, []FormatOption{BoundaryAware()}, "1023B"},
+		{"Boundary beyond ten decimals", PB + 1, []FormatOption{BoundaryAware()}, "1PB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFormatter(tt.opts...)
+			assert.Equal(t, tt.want, f.Format(tt.size))
+			assert.Equal(t, "x="+tt.want, string(f.AppendFormat([]byte("x="), tt.size)))
+			assert.Equal(t, []string{tt.want}, FormatAll([]ByteSize{tt.size}, tt.opts...))
+		})
+	}
+}
+
+func TestFormatAllMatchesString(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	sizes := make([]ByteSize, 10000)
+	for i := range sizes {
+		sizes[i] = ByteSize(r.Int63n(int64(4 * PB)))
+	}
+
+	for i, s := range FormatAll(sizes) {
+		assert.Equal(t, sizes[i].String(), s)
+	}
+	for i, s := range FormatAll(sizes, WithUnit(MB)) {
+		assert.Equal(t, sizes[i].Format(MB), s)
+	}
+	for _, size := range sizes[:1000] {
+		assert.Equal(t, size.String(), string(size.AppendFormat(nil, 0)))
+		assert.Equal(t, size.Format(GB), string(size.AppendFormat(nil, GB)))
+	}
+	assert.Equal(t, "size=1.5KB", string((KB+KB/2).AppendFormat([]byte("size="), KB)))
+}
+
+type failingWriter struct {
+	n int // bytes accepted before failing
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		n := w.n
+		w.n = 0
+		return n, errors.New("disk full")
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestFormatAllToError(t *testing.T) {
+	sizes := make([]ByteSize, 20000)
+	n, err := FormatAllTo(&failingWriter{n: 40000}, sizes, "\n")
+	assert.EqualError(t, err, "disk full")
+	assert.Equal(t, 40000, n)
+}
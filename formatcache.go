@@ -0,0 +1,73 @@
+package bytesizer
+
+import (
+	"container/list"
+	"sync"
+)
+
+// CachedFormatter formats sizes like FormatAll and remembers the most
+// recently used results, for programs that format the same sizes over and
+// over, such as the bucket labels of a dashboard. Results are cached by size
+// and options, and the least recently used are dropped beyond the capacity.
+// It is safe for concurrent use.
+//
+//	labels := NewCachedFormatter(64)
+//	...
+//	label := labels.Format(bucket, WithPrecision(0))
+type CachedFormatter struct {
+	capacity int
+
+	mu      sync.Mutex
+	entries map[formatKey]*list.Element
+	lru     list.List // of *formatEntry, most recently used first
+}
+
+// formatKey identifies a cached result.
+type formatKey struct {
+	size ByteSize
+	c    formatConfig
+}
+
+type formatEntry struct {
+	key formatKey
+	s   string
+}
+
+// NewCachedFormatter returns a formatter caching up to capacity results,
+// or 256 if capacity is not positive.
+func NewCachedFormatter(capacity int) *CachedFormatter {
+	if capacity <= 0 {
+		capacity = 256
+	}
+	return &CachedFormatter{capacity: capacity, entries: make(map[formatKey]*list.Element)}
+}
+
+// Format returns size formatted like String, or as set by opts, from the
+// cache if it is there. Cached results do not allocate.
+func (f *CachedFormatter) Format(size ByteSize, opts ...FormatOption) string {
+	key := formatKey{size: size, c: newFormatConfig(opts)}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if e, ok := f.entries[key]; ok {
+		f.lru.MoveToFront(e)
+		return e.Value.(*formatEntry).s
+	}
+
+	var buf [32]byte
+	s := string(key.c.appendSize(buf[:0], size))
+	if f.lru.Len() >= f.capacity {
+		oldest := f.lru.Back()
+		f.lru.Remove(oldest)
+		delete(f.entries, oldest.Value.(*formatEntry).key)
+	}
+	f.entries[key] = f.lru.PushFront(&formatEntry{key: key, s: s})
+	return s
+}
+
+// Len returns the number of cached results.
+func (f *CachedFormatter) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lru.Len()
+}
@@ -0,0 +1,68 @@
+package bytesizer
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedFormatter(t *testing.T) {
+	tests := []struct {
+		name string
+		size ByteSize
+		opts []FormatOption
+		want string
+	}{
+		{"Default", 1536 * KB, nil, "1.5MB"},
+		{"Unit", 1536 * KB, []FormatOption{WithUnit(KB)}, "1536KB"},
+		{"Precision", 1536 * KB, []FormatOption{WithPrecision(0)}, "2MB"},
+		{"Rounded keeps decimals", 1025 * KB, nil, "1.00MB"},
+		{"Negative", -2048, nil, "-2KB"},
+	}
+
+	f := NewCachedFormatter(0)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, f.Format(tt.size, tt.opts...))
+			assert.Equal(t, tt.want, f.Format(tt.size, tt.opts...), "cached")
+		})
+	}
+	assert.Equal(t, len(tests), f.Len())
+}
+
+func TestCachedFormatterEvicts(t *testing.T) {
+	f := NewCachedFormatter(2)
+	f.Format(KB)
+	f.Format(MB)
+	f.Format(KB) // KB is now the most recently used
+	f.Format(GB) // evicts MB
+	assert.Equal(t, 2, f.Len())
+
+	f.mu.Lock()
+	_, kb := f.entries[formatKey{KB, formatConfig{precision: 2}}]
+	_, mb := f.entries[formatKey{MB, formatConfig{precision: 2}}]
+	f.mu.Unlock()
+	assert.True(t, kb)
+	assert.False(t, mb)
+
+	assert.Equal(t, "1MB", f.Format(MB))
+	assert.Equal(t, 2, f.Len())
+}
+
+func TestCachedFormatterConcurrent(t *testing.T) {
+	f := NewCachedFormatter(8)
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				size := ByteSize((g+i)%16) * MB
+				assert.Equal(t, size.String(), f.Format(size))
+			}
+		}(g)
+	}
+	wg.Wait()
+	assert.Equal(t, 8, f.Len())
+}
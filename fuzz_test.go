@@ -0,0 +1,87 @@
+package bytesizer
+
+import (
+	"math"
+	"testing"
+)
+
+// The fuzz targets enforce that no string makes Parse panic, and that
+// formatted sizes always parse back. Their seed corpus is in
+// testdata/fuzz; run them with, e.g.:
+//
+//	go test -run '^$' -fuzz FuzzParse
+
+func FuzzParse(f *testing.F) {
+	for _, s := range []string{
+		"", "0", "1024", "10KB", "1.5GiB", "512mb", "-2KB", "+3MB", "1e3",
+		"8191PB", "8192PB", "-8192PB", "99999999999999999999", "NaNKB",
+		"InfMB", "1e400", ".5KB", "KB", "1XB", "1iB", "1.5", "0x10",
+	} {
+		f.Add(s)
+	}
+
+	compound := NewParser(AllowCompound())
+	lenient := NewParser(AllowSpaces(), AllowCompound())
+	f.Fuzz(func(t *testing.T, s string) {
+		_, _ = compound.Parse(s)
+		_, _ = lenient.Parse(s)
+
+		size, err := Parse(s)
+		if err != nil {
+			return
+		}
+		if _, err := sentinels.Parse(size.String()); err != nil {
+			t.Errorf("Parse(%q) = %d, whose String %q does not parse: %v", s, int(size), size.String(), err)
+		}
+		if got, err := sentinels.Parse(size.FormatExact()); err != nil || got != size {
+			t.Errorf("Parse(%q) = %d, whose FormatExact %q parses as %d, %v", s, int(size), size.FormatExact(), int(got), err)
+		}
+	})
+}
+
+func FuzzFormatRoundTrip(f *testing.F) {
+	for _, n := range []int64{
+		0, 1, 1023, 1024, 1025 * 1024, 1536 * 1024, -1536, 1<<53 + 1,
+		math.MaxInt64, math.MaxInt64 - 1<<40, math.MinInt64,
+	} {
+		f.Add(n)
+	}
+
+	f.Fuzz(func(t *testing.T, n int64) {
+		size := ByteSize(n)
+
+		s := size.String()
+		got, err := sentinels.Parse(s)
+		if err != nil {
+			t.Fatalf("String of %d is %q, which does not parse: %v", n, s, err)
+		}
+		// String rounds to two decimals of its unit, or truncates the
+		// largest sizes, and Parse drops fractions of a byte.
+		if diff := math.Abs(float64(got) - float64(size)); diff > math.Abs(float64(size))*0.01+1 {
+			t.Errorf("String of %d is %q, which parses as %d", n, s, int(got))
+		}
+
+		if got, err := sentinels.Parse(size.FormatExact()); err != nil || got != size {
+			t.Errorf("FormatExact of %d is %q, which parses as %d, %v", n, size.FormatExact(), int(got), err)
+		}
+
+		for _, u := range units {
+			if _, err := sentinels.Parse(size.Format(u.size)); err != nil {
+				t.Errorf("Format(%s) of %d is %q, which does not parse: %v", u.unitName, n, size.Format(u.size), err)
+			}
+		}
+		for _, opt := range []FormatOption{WithPrecision(0), WithPrecision(-1), BoundaryAware()} {
+			if s := NewFormatter(opt).Format(size); !parses(s) {
+				t.Errorf("Formatter of %d gives %q, which does not parse", n, s)
+			}
+		}
+	})
+}
+
+// sentinels parses sizes formatted by the package, which may be sentinels.
+var sentinels = NewParser(AllowSentinels())
+
+func parses(s string) bool {
+	_, err := sentinels.Parse(s)
+	return err == nil
+}
@@ -0,0 +1,15 @@
+package bytesizer
+
+import "encoding/gob"
+
+// ByteSize is registered with encoding/gob so it can be sent as the concrete
+// value of an interface field, and so is Rate.
+//
+// Wire stability: gob encodes ByteSize through MarshalBinary, i.e. as a
+// zig-zag varint of the number of bytes. That format is part of the public
+// API and will not change, so gob streams written by one version of this
+// package can be decoded by any later version.
+func init() {
+	gob.Register(ByteSize(0))
+	gob.Register(Rate(0))
+}
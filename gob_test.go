@@ -0,0 +1,44 @@
+package bytesizer
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type gobJob struct {
+	Name  string
+	Limit ByteSize
+	Extra interface{}
+}
+
+func TestByteSizeGobRoundTrip(t *testing.T) {
+	in := gobJob{Name: "backup", Limit: 10 * GB, Extra: 1536 * KB}
+
+	var buf bytes.Buffer
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(in))
+
+	var out gobJob
+	assert.NoError(t, gob.NewDecoder(&buf).Decode(&out))
+	assert.Equal(t, in, out)
+}
+
+// TestByteSizeGobWireFormat guards the documented wire-stability guarantee:
+// the golden stream was written by an earlier version and must keep decoding.
+func TestByteSizeGobWireFormat(t *testing.T) {
+	golden := []byte{
+		0x13, 0x7f, 0x06, 0x01, 0x01, 0x08, 0x42, 0x79, 0x74, 0x65, 0x53, 0x69, 0x7a, 0x65,
+		0x01, 0xff, 0x80, 0x00, 0x00, 0x00, 0x08, 0xff, 0x80, 0x00, 0x04, 0x80, 0x80, 0x80, 0x01,
+	}
+
+	var size ByteSize
+	assert.NoError(t, gob.NewDecoder(bytes.NewReader(golden)).Decode(&size))
+	assert.Equal(t, MB, size)
+
+	// the value itself is carried as its MarshalBinary form
+	var buf bytes.Buffer
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(MB))
+	assert.True(t, bytes.HasSuffix(buf.Bytes(), []byte{0x04, 0x80, 0x80, 0x80, 0x01}))
+}
@@ -0,0 +1,68 @@
+package bytesizer
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strconv"
+)
+
+// goMemLimitUnits are the suffixes of the GOMEMLIMIT syntax, largest first.
+var goMemLimitUnits = []struct {
+	size ByteSize
+	name string
+}{
+	{TB, "TiB"}, {GB, "GiB"}, {MB, "MiB"}, {KB, "KiB"}, {Byte, "B"},
+}
+
+// ParseGoMemLimit parses a memory limit in the syntax of the GOMEMLIMIT
+// environment variable: a non-negative integer with an optional B, KiB, MiB,
+// GiB or TiB suffix, such as "512MiB", or "off" for no limit, which returns
+// Unlimited.
+func ParseGoMemLimit(s string) (ByteSize, error) {
+	if s == "off" {
+		return Unlimited, nil
+	}
+
+	num, unit := s, Byte
+	for _, u := range goMemLimitUnits {
+		if n, ok := trimSuffix(s, u.name); ok {
+			num, unit = n, u.size
+			break
+		}
+	}
+
+	// ParseUint also rejects signs, as the runtime does.
+	n, err := strconv.ParseUint(num, 10, 63)
+	if err != nil || ByteSize(n) > Unlimited/unit {
+		return 0, fmt.Errorf("invalid GOMEMLIMIT %q", s)
+	}
+	return ByteSize(n) * unit, nil
+}
+
+// FormatGoMemLimit formats size in the syntax of GOMEMLIMIT, using the
+// largest suffix that represents it exactly, e.g. "512MiB". Unlimited is
+// formatted as "off".
+func FormatGoMemLimit(size ByteSize) string {
+	if size == Unlimited {
+		return "off"
+	}
+	for _, u := range goMemLimitUnits {
+		if size%u.size == 0 && size != 0 {
+			return strconv.FormatInt(int64(size/u.size), 10) + u.name
+		}
+	}
+	return strconv.FormatInt(int64(size), 10) + "B"
+}
+
+// SetGoMemLimit sets the soft memory limit of the runtime, like
+// debug.SetMemoryLimit, and returns the previous limit. Unlimited removes
+// the limit. A negative size only returns the current limit.
+func SetGoMemLimit(size ByteSize) ByteSize {
+	return ByteSize(debug.SetMemoryLimit(int64(size)))
+}
+
+// GoMemLimit returns the current soft memory limit of the runtime, which is
+// Unlimited unless one was set.
+func GoMemLimit() ByteSize {
+	return SetGoMemLimit(-1)
+}
@@ -0,0 +1,81 @@
+package bytesizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGoMemLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expectErr bool
+		expected  ByteSize
+	}{
+		{"Bytes", "1048576", false, MB},
+		{"B suffix", "1024B", false, KB},
+		{"KiB", "64KiB", false, 64 * KB},
+		{"MiB", "512MiB", false, 512 * MB},
+		{"GiB", "2GiB", false, 2 * GB},
+		{"TiB", "1TiB", false, TB},
+		{"Off", "off", false, Unlimited},
+		{"Zero", "0", false, 0},
+		{"Decimal", "1.5GiB", true, 0},
+		{"SI suffix", "512MB", true, 0},
+		{"Lowercase", "512mib", true, 0},
+		{"Negative", "-1", true, 0},
+		{"Plus sign", "+1GiB", true, 0},
+		{"Space", "512 MiB", true, 0},
+		{"Empty", "", true, 0},
+		{"Suffix only", "MiB", true, 0},
+		{"Overflow", "9000000TiB", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size, err := ParseGoMemLimit(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, size)
+			}
+		})
+	}
+}
+
+func TestFormatGoMemLimit(t *testing.T) {
+	tests := []struct {
+		size     ByteSize
+		expected string
+	}{
+		{0, "0B"},
+		{1000, "1000B"},
+		{1536 * KB, "1536KiB"},
+		{512 * MB, "512MiB"},
+		{2 * TB, "2TiB"},
+		{Unlimited, "off"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			s := FormatGoMemLimit(tt.size)
+			assert.Equal(t, tt.expected, s)
+
+			size, err := ParseGoMemLimit(s)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.size, size)
+		})
+	}
+}
+
+func TestSetGoMemLimit(t *testing.T) {
+	orig := GoMemLimit()
+	defer SetGoMemLimit(orig)
+
+	SetGoMemLimit(512 * MB)
+	assert.Equal(t, 512*MB, GoMemLimit())
+	assert.Equal(t, 512*MB, SetGoMemLimit(Unlimited))
+	assert.Equal(t, Unlimited, GoMemLimit())
+}
@@ -0,0 +1,24 @@
+package bytesizer
+
+import (
+	"io"
+	"strconv"
+)
+
+// MarshalGQL writes the ByteSize as a GraphQL string in its text form, e.g. "1.5GB".
+// Together with UnmarshalGQL it lets ByteSize be bound to a custom scalar in gqlgen.
+func (fs ByteSize) MarshalGQL(w io.Writer) {
+	text, _ := fs.MarshalText()
+	_, _ = io.WriteString(w, strconv.Quote(string(text)))
+}
+
+// UnmarshalGQL decodes the ByteSize from a GraphQL input value, which may be
+// a size string accepted by Parse or a number of bytes.
+func (fs *ByteSize) UnmarshalGQL(v interface{}) error {
+	size, err := toByteSize(v)
+	if err != nil {
+		return err
+	}
+	*fs = size
+	return nil
+}
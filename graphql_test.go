@@ -0,0 +1,66 @@
+package bytesizer
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// gqlScalar mirrors the graphql.Marshaler and graphql.Unmarshaler interfaces of gqlgen.
+type gqlScalar interface {
+	MarshalGQL(w io.Writer)
+	UnmarshalGQL(v interface{}) error
+}
+
+var _ gqlScalar = (*ByteSize)(nil)
+
+func TestByteSizeMarshalGQL(t *testing.T) {
+	tests := []struct {
+		size     ByteSize
+		expected string
+	}{
+		{1536 * KB, `"1.5MB"`},
+		{1025 * KB, `"1049600B"`},
+		{0, `"0B"`},
+	}
+
+	for _, tt := range tests {
+		var b strings.Builder
+		tt.size.MarshalGQL(&b)
+		assert.Equal(t, tt.expected, b.String())
+	}
+}
+
+func TestByteSizeUnmarshalGQL(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     interface{}
+		expectErr bool
+		expected  ByteSize
+	}{
+		{"String", "1.5GB", false, ByteSize(1.5 * float64(GB))},
+		{"Int", 2048, false, 2 * KB},
+		{"Int64", int64(MB), false, MB},
+		{"Float64", 1536.0, false, 1536},
+		{"json.Number", json.Number("4096"), false, 4 * KB},
+		{"Invalid string", "lots", true, 0},
+		{"Bool", true, true, 0},
+		{"Nil", nil, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var size ByteSize
+			err := size.UnmarshalGQL(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, size)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package bytesizer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// HTTPSizes holds the sizes of an HTTP exchange. Header sizes count the
+// header fields as written on the wire ("Name: value\r\n"), without the
+// request or status line.
+type HTTPSizes struct {
+	RequestHeader  ByteSize
+	RequestBody    ByteSize
+	ResponseHeader ByteSize
+	ResponseBody   ByteSize
+}
+
+// Total returns the sum of all sizes.
+func (s HTTPSizes) Total() ByteSize {
+	return s.RequestHeader + s.RequestBody + s.ResponseHeader + s.ResponseBody
+}
+
+type httpSizesKey struct{}
+
+// WithHTTPSizes returns a context carrying an HTTPSizes which RoundTripper
+// fills in for requests made with that context.
+func WithHTTPSizes(ctx context.Context) (context.Context, *HTTPSizes) {
+	sizes := new(HTTPSizes)
+	return context.WithValue(ctx, httpSizesKey{}, sizes), sizes
+}
+
+// HTTPSizesFromContext returns the HTTPSizes carried by ctx, or nil.
+func HTTPSizesFromContext(ctx context.Context) *HTTPSizes {
+	sizes, _ := ctx.Value(httpSizesKey{}).(*HTTPSizes)
+	return sizes
+}
+
+// headerSize returns the size of h as written on the wire.
+func headerSize(h http.Header) ByteSize {
+	var n int
+	for name, values := range h {
+		for _, v := range values {
+			n += len(name) + len(": ") + len(v) + len("\r\n")
+		}
+	}
+	return ByteSize(n)
+}
+
+type roundTripper struct {
+	next http.RoundTripper
+	fn   func(*http.Request, HTTPSizes)
+}
+
+// RoundTripper returns an http.RoundTripper that measures the requests it
+// sends through next (http.DefaultTransport if nil). The sizes are final
+// once the response body is closed, or when the request fails. They are
+// then passed to fn, if not nil, and stored in the HTTPSizes of the request
+// context created by WithHTTPSizes, if any.
+func RoundTripper(next http.RoundTripper, fn func(req *http.Request, sizes HTTPSizes)) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &roundTripper{next: next, fn: fn}
+}
+
+func (t *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody *countingBody
+	if req.Body != nil && req.Body != http.NoBody {
+		reqBody = &countingBody{ReadCloser: req.Body}
+		r := *req
+		r.Body = reqBody
+		req = &r
+	}
+
+	sizes := HTTPSizes{RequestHeader: headerSize(req.Header)}
+	finish := func(respBody *countingBody) {
+		sizes.RequestBody = reqBody.count()
+		sizes.ResponseBody = respBody.count()
+		if p := HTTPSizesFromContext(req.Context()); p != nil {
+			*p = sizes
+		}
+		if t.fn != nil {
+			t.fn(req, sizes)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		finish(nil)
+		return resp, err
+	}
+
+	sizes.ResponseHeader = headerSize(resp.Header)
+	respBody := &countingBody{ReadCloser: resp.Body}
+	respBody.onClose = func() { finish(respBody) }
+	resp.Body = respBody
+	return resp, nil
+}
+
+// countingBody is an io.ReadCloser that counts the bytes read and calls
+// onClose once, on the first Close.
+type countingBody struct {
+	io.ReadCloser
+	n       atomic.Int64
+	once    sync.Once
+	onClose func()
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n.Add(int64(n))
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	err := b.ReadCloser.Close()
+	if b.onClose != nil {
+		b.once.Do(b.onClose)
+	}
+	return err
+}
+
+// count returns the bytes read from b, which may be nil.
+func (b *countingBody) count() ByteSize {
+	if b == nil {
+		return 0
+	}
+	return ByteSize(b.n.Load())
+}
+
+// SizeHandler returns an http.Handler that measures the requests served by
+// next. Once next returns, the sizes are passed to fn, if not nil, and
+// stored in the HTTPSizes of the request context created by WithHTTPSizes,
+// if any, so that an outer middleware can log them.
+func SizeHandler(next http.Handler, fn func(r *http.Request, sizes HTTPSizes)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sizes := HTTPSizes{RequestHeader: headerSize(r.Header)}
+
+		var body *countingBody
+		if r.Body != nil && r.Body != http.NoBody {
+			body = &countingBody{ReadCloser: r.Body}
+			r2 := *r
+			r2.Body = body
+			r = &r2
+		}
+		sw := &sizeResponseWriter{ResponseWriter: w}
+
+		next.ServeHTTP(sw, r)
+
+		if !sw.wroteHeader {
+			sw.header = headerSize(w.Header())
+		}
+		sizes.RequestBody = body.count()
+		sizes.ResponseHeader = sw.header
+		sizes.ResponseBody = sw.written
+		if p := HTTPSizesFromContext(r.Context()); p != nil {
+			*p = sizes
+		}
+		if fn != nil {
+			fn(r, sizes)
+		}
+	})
+}
+
+// sizeResponseWriter counts the bytes of a response.
+type sizeResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+	header      ByteSize
+	written     ByteSize
+}
+
+func (w *sizeResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.header = headerSize(w.Header())
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *sizeResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.written += ByteSize(n)
+	return n, err
+}
+
+// Flush implements http.Flusher if the underlying writer does.
+func (w *sizeResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (w *sizeResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,123 @@
+package bytesizer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderSize(t *testing.T) {
+	h := http.Header{}
+	h.Set("Content-Type", "text/plain")
+	h.Add("X-Tag", "a")
+	h.Add("X-Tag", "bc")
+	assert.Equal(t, ByteSize(len("Content-Type: text/plain\r\nX-Tag: a\r\nX-Tag: bc\r\n")), headerSize(h))
+}
+
+func TestRoundTripper(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		_, _ = w.Write(make([]byte, 2*KB))
+	}))
+	defer srv.Close()
+
+	var got HTTPSizes
+	calls := 0
+	client := &http.Client{Transport: RoundTripper(nil, func(req *http.Request, sizes HTTPSizes) {
+		calls++
+		got = sizes
+	})}
+
+	ctx, sizes := WithHTTPSizes(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL, strings.NewReader(strings.Repeat("x", 100)))
+	assert.NoError(t, err)
+	req.Header.Set("X-Request-Id", "42")
+
+	resp, err := client.Do(req)
+	assert.NoError(t, err)
+	_, err = io.Copy(io.Discard, resp.Body)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, got, *sizes)
+	assert.Equal(t, ByteSize(100), got.RequestBody)
+	assert.Equal(t, ByteSize(len("X-Request-Id: 42\r\n")), got.RequestHeader)
+	assert.Equal(t, 2*KB, got.ResponseBody)
+	assert.Equal(t, headerSize(resp.Header), got.ResponseHeader)
+	assert.Equal(t, got.RequestHeader+100+got.ResponseHeader+2*KB, got.Total())
+}
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestRoundTripperError(t *testing.T) {
+	var got *HTTPSizes
+	client := &http.Client{Transport: RoundTripper(failingTransport{}, func(req *http.Request, sizes HTTPSizes) {
+		got = &sizes
+	})}
+
+	_, err := client.Get("http://example.invalid/")
+	assert.Error(t, err)
+	assert.NotNil(t, got)
+	assert.Equal(t, ByteSize(0), got.ResponseBody)
+}
+
+func TestSizeHandler(t *testing.T) {
+	var got HTTPSizes
+	h := SizeHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(make([]byte, KB))
+		_, _ = w.Write(make([]byte, KB))
+	}), func(r *http.Request, sizes HTTPSizes) {
+		got = sizes
+	})
+
+	// An outer middleware collecting the sizes through the context.
+	var fromContext *HTTPSizes
+	outer := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, sizes := WithHTTPSizes(r.Context())
+		h.ServeHTTP(w, r.WithContext(ctx))
+		fromContext = sizes
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(strings.Repeat("x", 300)))
+	req.Header.Set("X-Request-Id", "42")
+	rec := httptest.NewRecorder()
+	outer.ServeHTTP(rec, req)
+
+	assert.Equal(t, 2*KB, got.ResponseBody)
+	assert.Equal(t, ByteSize(300), got.RequestBody)
+	assert.Equal(t, ByteSize(len("X-Request-Id: 42\r\n")), got.RequestHeader)
+	assert.Equal(t, ByteSize(len("Content-Type: application/octet-stream\r\n")), got.ResponseHeader)
+	assert.Equal(t, got, *fromContext)
+	assert.Equal(t, 2*KB, ByteSize(rec.Body.Len()))
+}
+
+func TestSizeHandlerNoBody(t *testing.T) {
+	var got HTTPSizes
+	h := SizeHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Empty", "1")
+		w.(http.Flusher).Flush()
+	}), func(r *http.Request, sizes HTTPSizes) {
+		got = sizes
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, ByteSize(0), got.RequestBody)
+	assert.Equal(t, ByteSize(0), got.ResponseBody)
+	assert.Equal(t, ByteSize(len("X-Empty: 1\r\n")), got.ResponseHeader)
+	assert.True(t, rec.Flushed)
+}
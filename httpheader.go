@@ -0,0 +1,225 @@
+package bytesizer
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ParseContentLength parses the value of a Content-Length header.
+// It accepts only a non-negative decimal number, as RFC 9110 requires.
+func ParseContentLength(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s[0] == '+' {
+		return 0, fmt.Errorf("invalid Content-Length %q", s)
+	}
+	n, err := strconv.ParseUint(s, 10, 63)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Content-Length %q", s)
+	}
+	return ByteSize(n), nil
+}
+
+// FormatContentLength formats size as the value of a Content-Length header.
+func FormatContentLength(size ByteSize) string {
+	return strconv.FormatInt(int64(size), 10)
+}
+
+// ContentLength returns the size given by the Content-Length header of h,
+// and false if the header is absent.
+func ContentLength(h http.Header) (ByteSize, bool, error) {
+	v := h.Get("Content-Length")
+	if v == "" {
+		return 0, false, nil
+	}
+	size, err := ParseContentLength(v)
+	return size, err == nil, err
+}
+
+// ContentRange is the value of a Content-Range header for byte ranges,
+// such as "bytes 0-499/1234".
+type ContentRange struct {
+	// Start and End are the positions of the first and last bytes of the
+	// range, inclusive. Both are -1 for an unsatisfied range ("bytes */1234").
+	Start ByteSize
+	End   ByteSize
+	// Size is the complete length of the representation, -1 if unknown
+	// ("bytes 0-499/*").
+	Size ByteSize
+}
+
+// Length returns the number of bytes in the range.
+func (c ContentRange) Length() ByteSize {
+	if c.Start < 0 {
+		return 0
+	}
+	return c.End - c.Start + 1
+}
+
+// String formats c as the value of a Content-Range header.
+func (c ContentRange) String() string {
+	size := "*"
+	if c.Size >= 0 {
+		size = strconv.FormatInt(int64(c.Size), 10)
+	}
+	if c.Start < 0 {
+		return "bytes */" + size
+	}
+	return fmt.Sprintf("bytes %d-%d/%s", c.Start, c.End, size)
+}
+
+// ParseContentRange parses the value of a Content-Range header for bytes.
+func ParseContentRange(s string) (ContentRange, error) {
+	invalid := fmt.Errorf("invalid Content-Range %q", s)
+
+	spec, ok := trimPrefix(strings.TrimSpace(s), "bytes ")
+	if !ok {
+		return ContentRange{}, invalid
+	}
+	rng, size, ok := strings.Cut(spec, "/")
+	if !ok {
+		return ContentRange{}, invalid
+	}
+
+	c := ContentRange{Start: -1, End: -1, Size: -1}
+	if size != "*" {
+		n, err := ParseContentLength(size)
+		if err != nil {
+			return ContentRange{}, invalid
+		}
+		c.Size = n
+	}
+
+	if rng == "*" {
+		if c.Size < 0 {
+			return ContentRange{}, invalid
+		}
+		return c, nil
+	}
+
+	first, last, ok := strings.Cut(rng, "-")
+	if !ok {
+		return ContentRange{}, invalid
+	}
+	start, err := ParseContentLength(first)
+	if err != nil {
+		return ContentRange{}, invalid
+	}
+	end, err := ParseContentLength(last)
+	if err != nil || end < start || c.Size >= 0 && end >= c.Size {
+		return ContentRange{}, invalid
+	}
+	c.Start, c.End = start, end
+	return c, nil
+}
+
+// trimPrefix returns s without prefix, and whether s started with it.
+func trimPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+// ErrRangeNotSatisfiable is returned by ParseRange when none of the ranges
+// overlaps the content. Servers should answer 416 Range Not Satisfiable
+// with a Content-Range of "bytes */size".
+var ErrRangeNotSatisfiable = errors.New("range not satisfiable")
+
+// Range is a byte range of some content, resolved against its size.
+type Range struct {
+	Start ByteSize // first byte
+	End   ByteSize // last byte, inclusive
+}
+
+// Length returns the number of bytes in the range.
+func (r Range) Length() ByteSize {
+	return r.End - r.Start + 1
+}
+
+// ContentRange returns the Content-Range of r within content of the given size.
+func (r Range) ContentRange(size ByteSize) ContentRange {
+	return ContentRange{Start: r.Start, End: r.End, Size: size}
+}
+
+// RangesLength returns the number of bytes covered by ranges together,
+// not counting the overhead of a multipart/byteranges response.
+func RangesLength(ranges []Range) ByteSize {
+	var n ByteSize
+	for _, r := range ranges {
+		n += r.Length()
+	}
+	return n
+}
+
+// ParseRange parses the value of a Range header, such as
+// "bytes=0-1023,2048-" or "bytes=-500", against content of the given size.
+// Open and suffix ranges are resolved, and ranges are clipped to the
+// content. Ranges that start past the end are dropped; if none is left,
+// ParseRange returns ErrRangeNotSatisfiable. An empty header returns no
+// ranges and no error.
+func ParseRange(s string, size ByteSize) ([]Range, error) {
+	if s == "" {
+		return nil, nil
+	}
+	invalid := fmt.Errorf("invalid Range %q", s)
+
+	specs, ok := trimPrefix(s, "bytes=")
+	if !ok {
+		return nil, invalid
+	}
+
+	var ranges []Range
+	for _, spec := range strings.Split(specs, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+		first, last, ok := strings.Cut(spec, "-")
+		if !ok {
+			return nil, invalid
+		}
+
+		var r Range
+		if first == "" {
+			// A suffix range: the last n bytes.
+			n, err := ParseContentLength(last)
+			if err != nil {
+				return nil, invalid
+			}
+			if n == 0 || size == 0 {
+				continue
+			}
+			if n > size {
+				n = size
+			}
+			r = Range{Start: size - n, End: size - 1}
+		} else {
+			start, err := ParseContentLength(first)
+			if err != nil {
+				return nil, invalid
+			}
+			r = Range{Start: start, End: size - 1}
+			if last != "" {
+				end, err := ParseContentLength(last)
+				if err != nil || end < start {
+					return nil, invalid
+				}
+				if end < r.End {
+					r.End = end
+				}
+			}
+			if start >= size {
+				continue
+			}
+		}
+		ranges = append(ranges, r)
+	}
+
+	if len(ranges) == 0 {
+		return nil, ErrRangeNotSatisfiable
+	}
+	return ranges, nil
+}
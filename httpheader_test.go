@@ -0,0 +1,145 @@
+package bytesizer
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseContentLength(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expectErr bool
+		expected  ByteSize
+	}{
+		{"Zero", "0", false, 0},
+		{"Number", "1048576", false, MB},
+		{"Spaces", " 42 ", false, 42},
+		{"Empty", "", true, 0},
+		{"Negative", "-1", true, 0},
+		{"Plus sign", "+1", true, 0},
+		{"Unit", "1MB", true, 0},
+		{"List", "42, 42", true, 0},
+		{"Overflow", "9223372036854775808", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			size, err := ParseContentLength(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, size)
+			}
+		})
+	}
+
+	assert.Equal(t, "1048576", FormatContentLength(MB))
+}
+
+func TestContentLength(t *testing.T) {
+	h := http.Header{}
+	_, ok, err := ContentLength(h)
+	assert.False(t, ok)
+	assert.NoError(t, err)
+
+	h.Set("Content-Length", "2048")
+	size, ok, err := ContentLength(h)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*KB, size)
+
+	h.Set("Content-Length", "lots")
+	_, ok, err = ContentLength(h)
+	assert.False(t, ok)
+	assert.Error(t, err)
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expectErr bool
+		expected  ContentRange
+	}{
+		{"Range", "bytes 0-499/1234", false, ContentRange{Start: 0, End: 499, Size: 1234}},
+		{"Last byte", "bytes 1233-1233/1234", false, ContentRange{Start: 1233, End: 1233, Size: 1234}},
+		{"Unknown size", "bytes 0-499/*", false, ContentRange{Start: 0, End: 499, Size: -1}},
+		{"Unsatisfied", "bytes */1234", false, ContentRange{Start: -1, End: -1, Size: 1234}},
+		{"Unsatisfied unknown size", "bytes */*", true, ContentRange{}},
+		{"Other unit", "items 0-1/2", true, ContentRange{}},
+		{"End before start", "bytes 500-499/1234", true, ContentRange{}},
+		{"End past size", "bytes 0-1234/1234", true, ContentRange{}},
+		{"Missing size", "bytes 0-499", true, ContentRange{}},
+		{"Missing end", "bytes 0-/1234", true, ContentRange{}},
+		{"Negative", "bytes -1-499/1234", true, ContentRange{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseContentRange(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, c)
+				assert.Equal(t, tt.input, c.String())
+			}
+		})
+	}
+}
+
+func TestContentRangeLength(t *testing.T) {
+	assert.Equal(t, ByteSize(500), ContentRange{Start: 0, End: 499, Size: 1234}.Length())
+	assert.Equal(t, ByteSize(0), ContentRange{Start: -1, End: -1, Size: 1234}.Length())
+}
+
+func TestParseRange(t *testing.T) {
+	const size = 10 * KB
+	tests := []struct {
+		name      string
+		input     string
+		expectErr error
+		expected  []Range
+	}{
+		{"Empty", "", nil, nil},
+		{"First KB", "bytes=0-1023", nil, []Range{{0, 1023}}},
+		{"Open", "bytes=2048-", nil, []Range{{2048, size - 1}}},
+		{"Suffix", "bytes=-500", nil, []Range{{size - 500, size - 1}}},
+		{"Multiple", "bytes=0-1023, 2048-", nil, []Range{{0, 1023}, {2048, size - 1}}},
+		{"Clipped end", "bytes=9000-20000", nil, []Range{{9000, size - 1}}},
+		{"Suffix larger than content", "bytes=-20000", nil, []Range{{0, size - 1}}},
+		{"Past the end dropped", "bytes=0-9,20000-", nil, []Range{{0, 9}}},
+		{"Not satisfiable", "bytes=20000-", ErrRangeNotSatisfiable, nil},
+		{"Zero suffix", "bytes=-0", ErrRangeNotSatisfiable, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranges, err := ParseRange(tt.input, size)
+			assert.Equal(t, tt.expectErr, err)
+			assert.Equal(t, tt.expected, ranges)
+		})
+	}
+}
+
+func TestParseRangeInvalid(t *testing.T) {
+	for _, input := range []string{"items=0-1", "bytes=1", "bytes=5-1", "bytes=a-b", "bytes=--1", "bytes=0-1MB"} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseRange(input, KB)
+			assert.Error(t, err)
+			assert.NotEqual(t, ErrRangeNotSatisfiable, err)
+		})
+	}
+}
+
+func TestRangeMath(t *testing.T) {
+	ranges, err := ParseRange("bytes=0-1023,2048-", 10*KB)
+	assert.NoError(t, err)
+	assert.Equal(t, KB, ranges[0].Length())
+	assert.Equal(t, 9*KB, RangesLength(ranges))
+	assert.Equal(t, "bytes 2048-10239/10240", ranges[1].ContentRange(10*KB).String())
+}
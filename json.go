@@ -0,0 +1,97 @@
+package bytesizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// MarshalJSON encodes the ByteSize as a JSON number of bytes.
+func (fs ByteSize) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(fs), 10), nil
+}
+
+// UnmarshalJSON decodes a ByteSize from either a JSON number (raw bytes)
+// or a JSON string in any format accepted by Parse, e.g. "1.5GB".
+// A JSON null leaves the value unchanged.
+func (fs *ByteSize) UnmarshalJSON(data []byte) error {
+	v, err := unmarshalJSONSize(data)
+	if err != nil {
+		return err
+	}
+	if v != nil {
+		*fs = *v
+	}
+	return nil
+}
+
+// ByteSizeString is a ByteSize which is encoded to JSON as a humanized
+// string such as "1.5GB". It accepts both numbers and strings on input.
+type ByteSizeString ByteSize
+
+// MarshalJSON encodes the value as a JSON string using ByteSize.MarshalText,
+// so that it decodes back to the same value: 1025KB is "1049600B" rather
+// than the "1.00MB" of String.
+func (s ByteSizeString) MarshalJSON() ([]byte, error) {
+	text, err := ByteSize(s).MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON decodes the value from a JSON number or string.
+func (s *ByteSizeString) UnmarshalJSON(data []byte) error {
+	return (*ByteSize)(s).UnmarshalJSON(data)
+}
+
+// ByteSizeNumber is a ByteSize which is encoded to JSON as a number of bytes.
+// It accepts both numbers and strings on input.
+type ByteSizeNumber ByteSize
+
+// MarshalJSON encodes the value as a JSON number of bytes.
+func (n ByteSizeNumber) MarshalJSON() ([]byte, error) {
+	return ByteSize(n).MarshalJSON()
+}
+
+// UnmarshalJSON decodes the value from a JSON number or string.
+func (n *ByteSizeNumber) UnmarshalJSON(data []byte) error {
+	return (*ByteSize)(n).UnmarshalJSON(data)
+}
+
+// unmarshalJSONSize decodes a JSON number or string into a ByteSize.
+// It returns nil without an error for a JSON null.
+func unmarshalJSONSize(data []byte) (*ByteSize, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil, nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, err
+		}
+		v, err := Parse(s)
+		if err != nil {
+			return nil, err
+		}
+		return &v, nil
+	}
+
+	if i, err := strconv.ParseInt(string(data), 10, 64); err == nil {
+		v := ByteSize(i)
+		return &v, nil
+	}
+
+	var f float64
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid byte size: %s", data)
+	}
+	v, err := FromFloat(f)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
@@ -0,0 +1,104 @@
+package bytesizer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByteSizeMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected string
+	}{
+		{"ByteSize", 1536 * KB, "1572864"},
+		{"ByteSize zero", ByteSize(0), "0"},
+		{"ByteSizeNumber", ByteSizeNumber(2 * MB), "2097152"},
+		{"ByteSizeString", ByteSizeString(1536 * KB), `"1.5MB"`},
+		{"ByteSizeString bytes", ByteSizeString(532), `"532B"`},
+		{"ByteSizeString not a whole unit", ByteSizeString(1025 * KB), `"1049600B"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(b))
+		})
+	}
+}
+
+func TestByteSizeStringRoundTrip(t *testing.T) {
+	for _, size := range []ByteSize{1025 * KB, 1536*KB + 1, -1025 * KB, Unlimited - 1, 3} {
+		b, err := json.Marshal(ByteSizeString(size))
+		assert.NoError(t, err)
+		var got ByteSizeString
+		assert.NoError(t, json.Unmarshal(b, &got))
+		assert.Equal(t, ByteSizeString(size), got, "%s", b)
+	}
+}
+
+func TestByteSizeUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expectErr bool
+		expected  ByteSize
+	}{
+		{"Number", `1048576`, false, MB},
+		{"Float number", `1536.0`, false, 1536},
+		{"Exponent number", `1e3`, false, 1000},
+		{"String", `"1.5GB"`, false, ByteSize(1.5 * float64(GB))},
+		{"String bytes", `"10B"`, false, 10},
+		{"Null", `null`, false, 7},
+		{"Invalid string", `"10XB"`, true, 0},
+		{"Bool", `true`, true, 0},
+		{"Object", `{}`, true, 0},
+		{"Out of range", `1e30`, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var size ByteSize = 7
+			var str ByteSizeString = 7
+			var num ByteSizeNumber = 7
+
+			errs := []error{
+				json.Unmarshal([]byte(tt.input), &size),
+				json.Unmarshal([]byte(tt.input), &str),
+				json.Unmarshal([]byte(tt.input), &num),
+			}
+			for _, err := range errs {
+				if tt.expectErr {
+					assert.Error(t, err)
+				} else {
+					assert.NoError(t, err)
+				}
+			}
+			if !tt.expectErr {
+				assert.Equal(t, tt.expected, size)
+				assert.Equal(t, tt.expected, ByteSize(str))
+				assert.Equal(t, tt.expected, ByteSize(num))
+			}
+		})
+	}
+}
+
+func TestByteSizeJSONStruct(t *testing.T) {
+	type config struct {
+		Raw   ByteSize       `json:"raw"`
+		Human ByteSizeString `json:"human"`
+		Num   ByteSizeNumber `json:"num"`
+	}
+
+	var c config
+	err := json.Unmarshal([]byte(`{"raw":"10MB","human":2048,"num":"1KB"}`), &c)
+	assert.NoError(t, err)
+	assert.Equal(t, config{Raw: 10 * MB, Human: ByteSizeString(2 * KB), Num: ByteSizeNumber(KB)}, c)
+
+	b, err := json.Marshal(c)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"raw":10485760,"human":"2KB","num":1024}`, string(b))
+}
@@ -0,0 +1,59 @@
+package bytesizer
+
+import "encoding/json"
+
+// sizePattern is the ECMA-262 pattern of the size strings accepted by Parse.
+const sizePattern = `^([+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][+-]?[0-9]+)?(([KMGTP]i?)?[Bb])?)$`
+
+func integerSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "integer",
+		"format":      "int64",
+		"description": "Number of bytes.",
+		"examples":    []interface{}{1048576},
+	}
+}
+
+func stringSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "string",
+		"pattern":     sizePattern,
+		"description": "Size with an optional unit (B, KB, MB, GB, TB, PB; 1KB = 1024B, and KiB to PiB as synonyms). A number without a unit is a count of bytes.",
+		"examples":    []interface{}{"512B", "10MB", "1.5GB"},
+	}
+}
+
+// JSONSchema returns the JSON Schema of a ByteSize: a number of bytes, or a size string accepted by Parse.
+func (ByteSize) JSONSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"oneOf": []interface{}{integerSchema(), stringSchema()},
+	}
+}
+
+// JSONSchemaBytes returns JSONSchema encoded as JSON.
+// It implements the RawExposer interface of github.com/swaggest/jsonschema-go.
+func (fs ByteSize) JSONSchemaBytes() ([]byte, error) {
+	return json.Marshal(fs.JSONSchema())
+}
+
+// JSONSchema returns the JSON Schema of a ByteSizeString: a size string accepted by Parse.
+func (ByteSizeString) JSONSchema() map[string]interface{} {
+	return stringSchema()
+}
+
+// JSONSchemaBytes returns JSONSchema encoded as JSON.
+// It implements the RawExposer interface of github.com/swaggest/jsonschema-go.
+func (s ByteSizeString) JSONSchemaBytes() ([]byte, error) {
+	return json.Marshal(s.JSONSchema())
+}
+
+// JSONSchema returns the JSON Schema of a ByteSizeNumber: a number of bytes.
+func (ByteSizeNumber) JSONSchema() map[string]interface{} {
+	return integerSchema()
+}
+
+// JSONSchemaBytes returns JSONSchema encoded as JSON.
+// It implements the RawExposer interface of github.com/swaggest/jsonschema-go.
+func (n ByteSizeNumber) JSONSchemaBytes() ([]byte, error) {
+	return json.Marshal(n.JSONSchema())
+}
@@ -0,0 +1,70 @@
+package bytesizer
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/swaggest/jsonschema-go"
+)
+
+func TestSizePattern(t *testing.T) {
+	pattern := regexp.MustCompile(sizePattern)
+
+	for _, s := range []string{"512B", "10MB", "1.5GB", "1.5Gb", "4096", ".5KB", "1.KB", "-1B", "+2PB", "512MiB", "1.5GiB", "1e3KB", "2.5E-1MB"} {
+		assert.True(t, pattern.MatchString(s), s)
+		_, err := Parse(s)
+		assert.NoError(t, err, s)
+	}
+	for _, s := range []string{"", "MB", "10XB", "1.5 GB", "1.5gb", "ten", "1iB", "1kiB", "1MIB", "1e", "1eKB", "unlimited", "Unlimited", "–", "-"} {
+		assert.False(t, pattern.MatchString(s), s)
+		_, err := Parse(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestJSONSchemaBytes(t *testing.T) {
+	b, err := ByteSize(0).JSONSchemaBytes()
+	assert.NoError(t, err)
+
+	var schema struct {
+		OneOf []struct {
+			Type     string        `json:"type"`
+			Pattern  string        `json:"pattern"`
+			Examples []interface{} `json:"examples"`
+		} `json:"oneOf"`
+	}
+	assert.NoError(t, json.Unmarshal(b, &schema))
+	assert.Len(t, schema.OneOf, 2)
+	assert.Equal(t, "integer", schema.OneOf[0].Type)
+	assert.Equal(t, "string", schema.OneOf[1].Type)
+	assert.Equal(t, sizePattern, schema.OneOf[1].Pattern)
+
+	// every example must be accepted by the decoder
+	for _, example := range append(schema.OneOf[0].Examples, schema.OneOf[1].Examples...) {
+		raw, _ := json.Marshal(example)
+		var size ByteSize
+		assert.NoError(t, json.Unmarshal(raw, &size), "%s", raw)
+	}
+}
+
+func TestJSONSchemaSwaggest(t *testing.T) {
+	type request struct {
+		MaxBody ByteSize       `json:"maxBody"`
+		Chunk   ByteSizeString `json:"chunk"`
+		Limit   ByteSizeNumber `json:"limit"`
+	}
+
+	reflector := jsonschema.Reflector{}
+	schema, err := reflector.Reflect(request{})
+	assert.NoError(t, err)
+
+	defs := schema.Definitions
+	assert.Equal(t, "#/definitions/BytesizerByteSize", *schema.Properties["maxBody"].TypeObject.Ref)
+	assert.Len(t, defs["BytesizerByteSize"].TypeObject.OneOf, 2)
+	assert.Equal(t, jsonschema.String.Type(), *defs["BytesizerByteSizeString"].TypeObject.Type)
+	assert.Equal(t, sizePattern, *defs["BytesizerByteSizeString"].TypeObject.Pattern)
+	assert.Equal(t, jsonschema.Integer.Type(), *defs["BytesizerByteSizeNumber"].TypeObject.Type)
+	assert.Equal(t, "int64", *defs["BytesizerByteSizeNumber"].TypeObject.Format)
+}
@@ -0,0 +1,54 @@
+package bytesizer
+
+import (
+	"io"
+	"testing"
+
+	"github.com/alecthomas/kingpin/v2"
+	"github.com/alecthomas/kong"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKong(t *testing.T) {
+	var cli struct {
+		MaxSize ByteSize  `default:"100MB"`
+		Chunk   ByteSize  `short:"c"`
+		Limit   *ByteSize `optional:""`
+	}
+
+	parser, err := kong.New(&cli, kong.Exit(func(int) {}), kong.Writers(io.Discard, io.Discard))
+	assert.NoError(t, err)
+
+	_, err = parser.Parse([]string{"-c", "4KB"})
+	assert.NoError(t, err)
+	assert.Equal(t, 100*MB, cli.MaxSize)
+	assert.Equal(t, 4*KB, cli.Chunk)
+	assert.Nil(t, cli.Limit)
+
+	_, err = parser.Parse([]string{"--max-size=1.5GB", "--limit", "2048"})
+	assert.NoError(t, err)
+	assert.Equal(t, ByteSize(1.5*float64(GB)), cli.MaxSize)
+	assert.Equal(t, 2*KB, *cli.Limit)
+
+	_, err = parser.Parse([]string{"--max-size", "lots"})
+	assert.Error(t, err)
+}
+
+func TestKingpin(t *testing.T) {
+	app := kingpin.New("app", "")
+	app.Terminate(nil)
+	app.UsageWriter(io.Discard)
+	app.ErrorWriter(io.Discard)
+
+	var maxSize, chunk ByteSize
+	app.Flag("max-size", "").Default("100MB").SetValue(&maxSize)
+	app.Flag("chunk", "").Short('c').SetValue(&chunk)
+
+	_, err := app.Parse([]string{"-c", "4KB"})
+	assert.NoError(t, err)
+	assert.Equal(t, 100*MB, maxSize)
+	assert.Equal(t, 4*KB, chunk)
+
+	_, err = app.Parse([]string{"--max-size", "lots"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,97 @@
+package bytesizer
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SizeLabels shows a size the way disk vendors label it, in powers of 1000,
+// and the way operating systems report it, in powers of 1024, to explain
+// why a "2TB" drive shows up as 1.82TiB.
+type SizeLabels struct {
+	Size ByteSize
+	// Decimal is the size in powers of 1000, e.g. "2TB".
+	Decimal string
+	// Binary is the size in powers of 1024, e.g. "1.82TiB".
+	Binary string
+	// Discrepancy is how much smaller, in percent, the number of the
+	// decimal label's unit is when read as a power of 1024: about 2.34% for
+	// KB, 4.63% for MB, 6.87% for GB and 9.05% for TB.
+	Discrepancy float64
+}
+
+var (
+	decimalUnits = []struct {
+		size ByteSize
+		name string
+	}{
+		{Byte, "B"}, {DecimalKB, "KB"}, {DecimalMB, "MB"}, {DecimalGB, "GB"}, {DecimalTB, "TB"}, {DecimalPB, "PB"},
+	}
+	binaryUnitNames = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+)
+
+// DecimalToBinary parses s as a vendor label, where "TB" and the other
+// units are powers of 1000, and returns its labels.
+//
+//	l, err := DecimalToBinary("2TB")
+//	fmt.Println(l) // 2TB is 1.82TiB, 9.05% less
+func DecimalToBinary(s string) (SizeLabels, error) {
+	i := strings.IndexFunc(s, func(r rune) bool { return r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' })
+	num, unit := s, "B"
+	if i >= 0 {
+		num, unit = s[:i], s[i:]
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(num), 64)
+	if err != nil {
+		return SizeLabels{}, fmt.Errorf("invalid size %q", s)
+	}
+	for _, u := range decimalUnits {
+		if strings.EqualFold(u.name, unit) {
+			size, err := FromFloat(math.Round(v * float64(u.size)))
+			if err != nil {
+				return SizeLabels{}, fmt.Errorf("invalid size %q: %w", s, err)
+			}
+			return NewSizeLabels(size), nil
+		}
+	}
+	return SizeLabels{}, fmt.Errorf("invalid decimal size unit: %v", unit)
+}
+
+// BinaryToDecimal parses s as an operating system reports sizes, where
+// "TB" and "TiB" are powers of 1024 as in Parse, and returns its labels.
+func BinaryToDecimal(s string) (SizeLabels, error) {
+	size, err := Parse(s)
+	if err != nil {
+		return SizeLabels{}, err
+	}
+	return NewSizeLabels(size), nil
+}
+
+// NewSizeLabels returns the labels of size.
+func NewSizeLabels(size ByteSize) SizeLabels {
+	l := SizeLabels{Size: size}
+
+	exp := 0
+	for exp < len(decimalUnits)-1 && math.Abs(float64(size)) >= float64(decimalUnits[exp+1].size) {
+		exp++
+	}
+	l.Decimal = formatString(float64(size)/float64(decimalUnits[exp].size), decimalUnits[exp].name, 2)
+	l.Discrepancy = (1 - math.Pow(1000.0/1024, float64(exp))) * 100
+
+	bexp := 0
+	for bexp < len(units)-1 && math.Abs(float64(size)) >= float64(units[bexp+1].size) {
+		bexp++
+	}
+	l.Binary = formatString(float64(size)/float64(units[bexp].size), binaryUnitNames[bexp], 2)
+	return l
+}
+
+// String returns a summary such as "2TB is 1.82TiB, 9.05% less".
+func (l SizeLabels) String() string {
+	if l.Discrepancy == 0 {
+		return fmt.Sprintf("%s is %s", l.Decimal, l.Binary)
+	}
+	return fmt.Sprintf("%s is %s, %s less", l.Decimal, l.Binary, formatString(l.Discrepancy, "%", 2))
+}
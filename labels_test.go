@@ -0,0 +1,71 @@
+package bytesizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecimalToBinary(t *testing.T) {
+	tests := []struct {
+		in          string
+		wantSize    ByteSize
+		wantBinary  string
+		wantDecimal string
+		wantDiff    float64
+		wantString  string
+	}{
+		{"2TB", 2 * DecimalTB, "1.82TiB", "2TB", 9.05, "2TB is 1.82TiB, 9.05% less"},
+		{"500GB", 500 * DecimalGB, "465.66GiB", "500GB", 6.87, "500GB is 465.66GiB, 6.87% less"},
+		{"1.5 tb", 15 * DecimalTB / 10, "1.36TiB", "1.5TB", 9.05, "1.5TB is 1.36TiB, 9.05% less"},
+		{"64kb", 64 * DecimalKB, "62.5KiB", "64KB", 2.34, "64KB is 62.5KiB, 2.34% less"},
+		{"512", 512, "512B", "512B", 0, "512B is 512B"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			l, err := DecimalToBinary(tt.in)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantSize, l.Size)
+			assert.Equal(t, tt.wantDecimal, l.Decimal)
+			assert.Equal(t, tt.wantBinary, l.Binary)
+			assert.InDelta(t, tt.wantDiff, l.Discrepancy, 0.01)
+			assert.Equal(t, tt.wantString, l.String())
+		})
+	}
+
+	for _, in := range []string{"", "TB", "2XB", "2TiB"} {
+		_, err := DecimalToBinary(in)
+		assert.Error(t, err, in)
+	}
+
+	_, err := DecimalToBinary("99999999PB")
+	assert.ErrorIs(t, err, ErrOutOfRange)
+	_, err = DecimalToBinary("-99999999PB")
+	assert.ErrorIs(t, err, ErrOutOfRange)
+}
+
+func TestBinaryToDecimal(t *testing.T) {
+	tests := []struct {
+		in          string
+		wantDecimal string
+		wantBinary  string
+	}{
+		{"1.5TiB", "1.65TB", "1.5TiB"},
+		{"1.5TB", "1.65TB", "1.5TiB"},
+		{"4GiB", "4.29GB", "4GiB"},
+		{"1000KiB", "1.02MB", "1000KiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			l, err := BinaryToDecimal(tt.in)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantDecimal, l.Decimal)
+			assert.Equal(t, tt.wantBinary, l.Binary)
+		})
+	}
+
+	_, err := BinaryToDecimal("2XB")
+	assert.Error(t, err)
+}
@@ -0,0 +1,79 @@
+package bytesizer
+
+import (
+	"fmt"
+	"io"
+)
+
+// ErrSizeExceeded is returned when more data than Limit went through a
+// size-limited reader or writer. Read is the number of bytes seen when the
+// limit was detected, which is at least Limit+1.
+type ErrSizeExceeded struct {
+	Limit ByteSize
+	Read  ByteSize
+}
+
+func (e *ErrSizeExceeded) Error() string {
+	return fmt.Sprintf("size exceeded %s limit", e.Limit)
+}
+
+// LimitedReader reads from R until Max bytes have been read. Unlike
+// io.LimitedReader it does not report a silent EOF at the limit: if R holds
+// more than Max bytes, Read returns an *ErrSizeExceeded.
+type LimitedReader struct {
+	R   io.Reader
+	Max ByteSize
+	n   ByteSize
+}
+
+// LimitReader returns a LimitedReader that reads at most max bytes from r
+// and fails with an *ErrSizeExceeded if r holds more.
+func LimitReader(r io.Reader, max ByteSize) *LimitedReader {
+	return &LimitedReader{R: r, Max: max}
+}
+
+// Read reads from R. Once Max bytes have been read, it probes R for one more
+// byte and returns an *ErrSizeExceeded if there is one.
+func (l *LimitedReader) Read(p []byte) (int, error) {
+	if l.n > l.Max {
+		return 0, &ErrSizeExceeded{Limit: l.Max, Read: l.n}
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	// Read one byte past the limit to tell reaching it from exceeding it.
+	// remaining+1 is at most len(p), so it cannot overflow for Unlimited.
+	if remaining := l.Max - l.n; remaining < ByteSize(len(p)) {
+		p = p[:remaining+1]
+	}
+	n, err := l.R.Read(p)
+	l.n += ByteSize(n)
+	if l.n > l.Max {
+		n -= int(l.n - l.Max)
+		return n, &ErrSizeExceeded{Limit: l.Max, Read: l.n}
+	}
+	return n, err
+}
+
+// LimitedWriter writes to W until Max bytes have been written. A write that
+// would go past the limit writes nothing and returns an *ErrSizeExceeded.
+type LimitedWriter struct {
+	W   io.Writer
+	Max ByteSize
+	n   ByteSize
+}
+
+// LimitWriter returns a LimitedWriter that writes at most max bytes to w.
+func LimitWriter(w io.Writer, max ByteSize) *LimitedWriter {
+	return &LimitedWriter{W: w, Max: max}
+}
+
+// Write writes p to W if it fits in the remaining limit.
+func (l *LimitedWriter) Write(p []byte) (int, error) {
+	if l.n+ByteSize(len(p)) > l.Max {
+		return 0, &ErrSizeExceeded{Limit: l.Max, Read: l.n + ByteSize(len(p))}
+	}
+	n, err := l.W.Write(p)
+	l.n += ByteSize(n)
+	return n, err
+}
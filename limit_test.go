@@ -0,0 +1,73 @@
+package bytesizer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitReader(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		max       ByteSize
+		expectErr bool
+	}{
+		{"Under limit", 100, KB, false},
+		{"At limit", int(KB), KB, false},
+		{"Over limit by one", int(KB) + 1, KB, true},
+		{"Over limit", int(2 * KB), KB, true},
+		{"Zero limit empty", 0, 0, false},
+		{"Zero limit", 1, 0, true},
+		{"Unlimited", int(KB), Unlimited, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := io.ReadAll(LimitReader(strings.NewReader(strings.Repeat("x", tt.size)), tt.max))
+			if tt.expectErr {
+				var exceeded *ErrSizeExceeded
+				assert.True(t, errors.As(err, &exceeded))
+				assert.Equal(t, tt.max, exceeded.Limit)
+				assert.Greater(t, exceeded.Read, tt.max)
+				assert.Equal(t, int(tt.max), len(b))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.size, len(b))
+			}
+		})
+	}
+}
+
+func TestErrSizeExceeded(t *testing.T) {
+	_, err := io.ReadAll(LimitReader(strings.NewReader(strings.Repeat("x", int(30*MB))), 25*MB))
+	assert.EqualError(t, err, "size exceeded 25MB limit")
+}
+
+func TestLimitReaderAfterExceeded(t *testing.T) {
+	r := LimitReader(strings.NewReader("Hello World!"), 5)
+	_, err := io.ReadAll(r)
+	assert.Error(t, err)
+	n, err := r.Read(make([]byte, 10))
+	assert.Equal(t, 0, n)
+	assert.Error(t, err)
+}
+
+func TestLimitWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := LimitWriter(&buf, 10)
+
+	_, err := w.Write([]byte("Hello"))
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("World!"))
+	var exceeded *ErrSizeExceeded
+	assert.True(t, errors.As(err, &exceeded))
+	assert.Equal(t, ErrSizeExceeded{Limit: 10, Read: 11}, *exceeded)
+	_, err = w.Write([]byte("World"))
+	assert.NoError(t, err)
+	assert.Equal(t, "HelloWorld", buf.String())
+}
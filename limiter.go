@@ -0,0 +1,174 @@
+package bytesizer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket limiting bytes to a Rate, like the limiter of
+// golang.org/x/time/rate but in bytes. The bucket refills at the rate and
+// holds at most burst bytes, the most that can be taken at once. It is safe
+// for concurrent use, so one Limiter can shape several transfers together.
+//
+// A rate of zero or less means no limit.
+type Limiter struct {
+	mu     sync.Mutex
+	rate   Rate
+	burst  ByteSize
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns a Limiter allowing rate, with bursts of up to burst
+// bytes. The bucket starts full.
+//
+//	l := NewLimiter(10*MBps, MB)
+//	for chunk := range chunks {
+//		if err := l.WaitN(ctx, ByteSize(len(chunk))); err != nil {
+//			return err
+//		}
+//		send(chunk)
+//	}
+func NewLimiter(rate Rate, burst ByteSize) *Limiter {
+	return &Limiter{rate: rate, burst: burst, tokens: float64(burst), last: time.Now()}
+}
+
+// Limit returns the rate.
+func (l *Limiter) Limit() Rate {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// Burst returns the burst size.
+func (l *Limiter) Burst() ByteSize {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.burst
+}
+
+// SetLimit changes the rate.
+func (l *Limiter) SetLimit(rate Rate) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.advance(time.Now())
+	l.rate = rate
+}
+
+// SetBurst changes the burst size.
+func (l *Limiter) SetBurst(burst ByteSize) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.advance(time.Now())
+	l.burst = burst
+	if l.tokens > float64(burst) {
+		l.tokens = float64(burst)
+	}
+}
+
+// AllowN reports whether n bytes may be sent now, and takes them if so.
+func (l *Limiter) AllowN(n ByteSize) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate <= 0 {
+		return true
+	}
+	l.advance(time.Now())
+	if float64(n) > l.tokens {
+		return false
+	}
+	l.tokens -= float64(n)
+	return true
+}
+
+// WaitN blocks until n bytes may be sent, and takes them. It fails if n
+// exceeds the burst size, or if ctx is done or its deadline would pass
+// before then; the bytes are not taken in that case.
+func (l *Limiter) WaitN(ctx context.Context, n ByteSize) error {
+	if n > l.Burst() && l.Limit() > 0 {
+		return fmt.Errorf("%s exceeds limiter burst %s", n, l.Burst())
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d := l.reserve(n)
+	if d == 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		l.cancel(n)
+		return fmt.Errorf("waiting %s for %s would exceed the context deadline", d, n)
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		l.cancel(n)
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// reserve takes n tokens, going into debt if needed, and returns how long
+// to wait before using them.
+func (l *Limiter) reserve(n ByteSize) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.rate <= 0 {
+		return 0
+	}
+	l.advance(time.Now())
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / float64(l.rate) * float64(time.Second))
+}
+
+// cancel gives back n tokens taken by reserve but not used.
+func (l *Limiter) cancel(n ByteSize) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.advance(time.Now())
+	l.tokens += float64(n)
+	if l.tokens > float64(l.burst) {
+		l.tokens = float64(l.burst)
+	}
+}
+
+// advance refills the bucket up to now. Call with l.mu held.
+func (l *Limiter) advance(now time.Time) {
+	if l.rate > 0 {
+		l.tokens += now.Sub(l.last).Seconds() * float64(l.rate)
+		if l.tokens > float64(l.burst) {
+			l.tokens = float64(l.burst)
+		}
+	}
+	l.last = now
+}
+
+// wait takes n tokens, blocking until they are available or ctx is done.
+// Unlike WaitN, n may exceed the burst size, and tokens are kept on
+// cancellation, as the bytes have already been transferred.
+func (l *Limiter) wait(ctx context.Context, n int) error {
+	d := l.reserve(ByteSize(n))
+	if d == 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,76 @@
+package bytesizer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiterAllowN(t *testing.T) {
+	l := NewLimiter(KBps, 2*KB)
+	assert.Equal(t, KBps, l.Limit())
+	assert.Equal(t, 2*KB, l.Burst())
+
+	assert.True(t, l.AllowN(KB))
+	assert.True(t, l.AllowN(KB))
+	assert.False(t, l.AllowN(100))
+	assert.False(t, l.AllowN(3*KB))
+
+	l.SetLimit(MBps)
+	time.Sleep(10 * time.Millisecond)
+	assert.True(t, l.AllowN(KB))
+
+	l.SetBurst(KB)
+	assert.Equal(t, KB, l.Burst())
+	assert.False(t, l.AllowN(2*KB))
+}
+
+func TestLimiterWaitN(t *testing.T) {
+	l := NewLimiter(100*KBps, 10*KB)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, l.WaitN(context.Background(), 10*KB))
+	}
+	elapsed := time.Since(start)
+	// 30KB at 100KB/s, less the 10KB initial burst.
+	assert.GreaterOrEqual(t, elapsed, 150*time.Millisecond)
+	assert.Less(t, elapsed, 2*time.Second)
+
+	assert.Error(t, l.WaitN(context.Background(), 11*KB))
+}
+
+func TestLimiterWaitNContext(t *testing.T) {
+	l := NewLimiter(KBps, KB)
+	assert.True(t, l.AllowN(KB))
+
+	// The deadline is too close: fail at once, without taking tokens.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := l.WaitN(ctx, KB)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	assert.True(t, errors.Is(l.WaitN(ctx, 1), context.Canceled))
+
+	// Canceled while waiting: the tokens are given back.
+	l = NewLimiter(10*KBps, KB)
+	assert.True(t, l.AllowN(KB))
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	assert.True(t, errors.Is(l.WaitN(ctx, KB), context.Canceled))
+	time.Sleep(30 * time.Millisecond)
+	assert.True(t, l.AllowN(300))
+}
+
+func TestLimiterUnlimited(t *testing.T) {
+	l := NewLimiter(0, 0)
+	assert.True(t, l.AllowN(GB))
+	assert.NoError(t, l.WaitN(context.Background(), GB))
+}
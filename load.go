@@ -0,0 +1,137 @@
+package bytesizer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Load fills the ByteSize fields of the struct pointed to by dst according
+// to their `bytesize` struct tags, combining environment lookup, defaults
+// and range validation in one pass. The tag is a comma-separated list of
+// options:
+//
+//	env=NAME      read the value from the environment variable NAME
+//	default=10MB  value to use when the field is zero and the variable is unset
+//	min=1KB       smallest accepted value
+//	max=1GB       largest accepted value
+//
+// For example:
+//
+//	type Config struct {
+//		MaxBody ByteSize `bytesize:"env=MAX_BODY,default=10MB,min=1KB,max=1GB"`
+//	}
+//
+//	var cfg Config
+//	err := bytesizer.Load(&cfg)
+//
+// Nested structs and non-nil pointers to structs are walked as well.
+// Fields without a tag are left untouched. Load returns the first error
+// encountered, prefixed with the path of the offending field.
+func Load(dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bytesizer: Load needs a non-nil pointer to a struct, got %T", dst)
+	}
+	return loadStruct(v.Elem(), "")
+}
+
+func loadStruct(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		path := prefix + field.Name
+
+		tag, ok := field.Tag.Lookup("bytesize")
+		if !ok {
+			if err := loadNested(value, path); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if typ := value.Type(); typ != byteSizeType && typ != byteSizeStringType && typ != byteSizeNumberType {
+			return fmt.Errorf("bytesizer: field %s: bytesize tag on non-ByteSize type %s", path, value.Type())
+		}
+		size := value.Convert(byteSizeType).Interface().(ByteSize)
+		size, err := loadField(size, tag)
+		if err != nil {
+			return fmt.Errorf("bytesizer: field %s: %w", path, err)
+		}
+		value.Set(reflect.ValueOf(size).Convert(value.Type()))
+	}
+	return nil
+}
+
+// loadNested walks into struct and non-nil pointer-to-struct fields.
+func loadNested(v reflect.Value, path string) error {
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	return loadStruct(v, path+".")
+}
+
+// loadField applies the options of a bytesize tag to the current value of a field.
+func loadField(size ByteSize, tag string) (ByteSize, error) {
+	var env, def, min, max string
+	for _, opt := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(opt), "=")
+		switch key {
+		case "env":
+			env = value
+		case "default":
+			def = value
+		case "min":
+			min = value
+		case "max":
+			max = value
+		case "":
+		default:
+			return 0, fmt.Errorf("unknown bytesize tag option %q", key)
+		}
+	}
+
+	fromEnv := false
+	if env != "" {
+		v, ok, err := LookupEnv(env)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			size, fromEnv = v, true
+		}
+	}
+	if !fromEnv && size == 0 && def != "" {
+		v, err := Parse(def)
+		if err != nil {
+			return 0, fmt.Errorf("invalid default: %w", err)
+		}
+		size = v
+	}
+
+	if min != "" {
+		v, err := Parse(min)
+		if err != nil {
+			return 0, fmt.Errorf("invalid min: %w", err)
+		}
+		if size < v {
+			return 0, fmt.Errorf("%s is below the minimum of %s", size, v)
+		}
+	}
+	if max != "" {
+		v, err := Parse(max)
+		if err != nil {
+			return 0, fmt.Errorf("invalid max: %w", err)
+		}
+		if size > v {
+			return 0, fmt.Errorf("%s is above the maximum of %s", size, v)
+		}
+	}
+	return size, nil
+}
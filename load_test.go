@@ -0,0 +1,77 @@
+package bytesizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type loadServer struct {
+	MaxBody ByteSize       `bytesize:"env=BYTESIZER_MAX_BODY,default=10MB,min=1KB,max=1GB"`
+	Buffer  ByteSizeString `bytesize:"default=4KB"`
+	Set     ByteSize       `bytesize:"default=4KB"`
+	Free    ByteSize
+}
+
+type loadConfig struct {
+	Server  loadServer
+	Cache   *loadServer
+	Missing *loadServer
+	Name    string
+	hidden  ByteSize `bytesize:"default=1KB"`
+}
+
+func TestLoad(t *testing.T) {
+	t.Setenv("BYTESIZER_MAX_BODY", "")
+
+	cfg := loadConfig{
+		Server: loadServer{Set: 2 * KB},
+		Cache:  &loadServer{},
+	}
+	assert.NoError(t, Load(&cfg))
+
+	assert.Equal(t, loadServer{MaxBody: 10 * MB, Buffer: ByteSizeString(4 * KB), Set: 2 * KB}, cfg.Server)
+	assert.Equal(t, loadServer{MaxBody: 10 * MB, Buffer: ByteSizeString(4 * KB), Set: 4 * KB}, *cfg.Cache)
+	assert.Nil(t, cfg.Missing)
+	assert.Equal(t, ByteSize(0), cfg.hidden)
+}
+
+func TestLoadEnv(t *testing.T) {
+	t.Setenv("BYTESIZER_MAX_BODY", "25MB")
+
+	cfg := loadServer{MaxBody: 5 * MB}
+	assert.NoError(t, Load(&cfg))
+	assert.Equal(t, 25*MB, cfg.MaxBody, "the environment wins over the current value")
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		dst  interface{}
+		err  string
+	}{
+		{"Nil", "", nil, "non-nil pointer to a struct"},
+		{"Not a pointer", "", loadServer{}, "non-nil pointer to a struct"},
+		{"Invalid env", "lots", &loadServer{}, "field MaxBody: env BYTESIZER_MAX_BODY"},
+		{"Below min", "512B", &loadServer{}, "field MaxBody: 512B is below the minimum of 1KB"},
+		{"Above max", "2GB", &loadConfig{}, "field Server.MaxBody: 2GB is above the maximum of 1GB"},
+		{"Invalid default", "", &struct {
+			Size ByteSize `bytesize:"default=lots"`
+		}{}, "field Size: invalid default"},
+		{"Unknown option", "", &struct {
+			Size ByteSize `bytesize:"dflt=1KB"`
+		}{}, `field Size: unknown bytesize tag option "dflt"`},
+		{"Wrong type", "", &struct {
+			Size int64 `bytesize:"default=1KB"`
+		}{}, "field Size: bytesize tag on non-ByteSize type int64"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BYTESIZER_MAX_BODY", tt.env)
+			err := Load(tt.dst)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
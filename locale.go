@@ -0,0 +1,236 @@
+package bytesizer
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Locale is how a language writes sizes: the names of the units and the
+// conventions of its numbers. The built-in locales are "en", "fr", "de",
+// "es", "zh" (Simplified Chinese) and "ja"; RegisterLocale adds others or
+// replaces them.
+type Locale struct {
+	// Units are the abbreviations of Byte, KB, MB, GB, TB and PB, such as
+	// "o", "Ko" and "Mo" in French.
+	Units [6]string
+	// Names are the long names of the units for Humanize, such as "byte"
+	// and "kilobyte".
+	Names [6]string
+	// PluralNames are the long names for plural quantities, such as
+	// "bytes". Languages without plural forms leave them empty.
+	PluralNames [6]string
+	// Plural reports whether the quantity n, which is not negative, takes
+	// the PluralNames. If it is nil, every n but 1 does, as in English.
+	Plural func(n float64) bool
+	// Decimal separates the whole part of a number from its fraction, "."
+	// if empty.
+	Decimal string
+	// Space separates the number from the unit, such as a no-break space.
+	Space string
+	// NameSpace separates the number from the long names of Humanize, such
+	// as a space. Japanese sets none, as in "1.5メガバイト".
+	NameSpace string
+	// Unlimited is the name of Unlimited, "unlimited" if empty.
+	Unlimited string
+}
+
+// English writes sizes like String: "1.5MB", or "1.5 megabytes" with
+// Humanize.
+var English = Locale{
+	Units:       [6]string{"B", "KB", "MB", "GB", "TB", "PB"},
+	Names:       [6]string{"byte", "kilobyte", "megabyte", "gigabyte", "terabyte", "petabyte"},
+	PluralNames: [6]string{"bytes", "kilobytes", "megabytes", "gigabytes", "terabytes", "petabytes"},
+	Decimal:     ".",
+	NameSpace:   " ",
+	Unlimited:   unlimitedName,
+}
+
+var locales = struct {
+	sync.RWMutex
+	m map[string]*Locale
+}{m: map[string]*Locale{
+	"en": &English,
+	"fr": {
+		Units:       [6]string{"o", "Ko", "Mo", "Go", "To", "Po"},
+		Names:       [6]string{"octet", "kilooctet", "mégaoctet", "gigaoctet", "téraoctet", "pétaoctet"},
+		PluralNames: [6]string{"octets", "kilooctets", "mégaoctets", "gigaoctets", "téraoctets", "pétaoctets"},
+		Plural:      func(n float64) bool { return n >= 2 },
+		Decimal:     ",",
+		Space:       "\u00a0",
+		NameSpace:   "\u00a0",
+		Unlimited:   "illimité",
+	},
+	"de": {
+		Units:     [6]string{"B", "KB", "MB", "GB", "TB", "PB"},
+		Names:     [6]string{"Byte", "Kilobyte", "Megabyte", "Gigabyte", "Terabyte", "Petabyte"},
+		Decimal:   ",",
+		Space:     "\u00a0",
+		NameSpace: "\u00a0",
+		Unlimited: "unbegrenzt",
+	},
+	"es": {
+		Units:       [6]string{"B", "KB", "MB", "GB", "TB", "PB"},
+		Names:       [6]string{"byte", "kilobyte", "megabyte", "gigabyte", "terabyte", "petabyte"},
+		PluralNames: [6]string{"bytes", "kilobytes", "megabytes", "gigabytes", "terabytes", "petabytes"},
+		Decimal:     ",",
+		Space:       "\u00a0",
+		NameSpace:   "\u00a0",
+		Unlimited:   "ilimitado",
+	},
+	// Sizes in Chinese and Japanese keep Latin abbreviations next to the
+	// number, as in "1.5MB", while the long names are in Han or katakana.
+	"zh": {
+		Units:     [6]string{"B", "KB", "MB", "GB", "TB", "PB"},
+		Names:     [6]string{"字节", "千字节", "兆字节", "吉字节", "太字节", "拍字节"},
+		Decimal:   ".",
+		NameSpace: " ",
+		Unlimited: "无限制",
+	},
+	"ja": {
+		Units:     [6]string{"B", "KB", "MB", "GB", "TB", "PB"},
+		Names:     [6]string{"バイト", "キロバイト", "メガバイト", "ギガバイト", "テラバイト", "ペタバイト"},
+		Decimal:   ".",
+		Unlimited: "無制限",
+	},
+}}
+
+// RegisterLocale sets the locale of the language tag, such as "pt" or
+// "pt-BR", for apps shipping their own catalogs. It is safe to call while
+// sizes are formatted, but options made by WithLocale before keep the
+// previous locale.
+func RegisterLocale(tag string, l Locale) {
+	locales.Lock()
+	defer locales.Unlock()
+	locales.m[normalizeTag(tag)] = &l
+}
+
+// LookupLocale returns the locale of the language tag. A tag without its
+// own locale gets that of its language, so "fr-CA" gets "fr". The second
+// result is false if there is none at all.
+func LookupLocale(tag string) (Locale, bool) {
+	if l := lookupLocale(tag); l != nil {
+		return *l, true
+	}
+	return Locale{}, false
+}
+
+func lookupLocale(tag string) *Locale {
+	tag = normalizeTag(tag)
+	locales.RLock()
+	defer locales.RUnlock()
+	for {
+		if l, ok := locales.m[tag]; ok {
+			return l
+		}
+		i := strings.LastIndexByte(tag, '-')
+		if i < 0 {
+			return nil
+		}
+		tag = tag[:i]
+	}
+}
+
+// normalizeTag returns tag lower-cased, with "-" between subtags, such as
+// "pt-br" for "pt_BR".
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+}
+
+// WithLocale formats sizes with the unit names and decimal separator of the
+// language tag, such as "fr" for "1,5 Mo". Languages without a locale
+// fall back to English.
+func WithLocale(tag string) FormatOption {
+	l := lookupLocale(tag)
+	if l == nil {
+		l = &English
+	}
+	return func(c *formatConfig) {
+		c.locale = l
+	}
+}
+
+// FormatLocalized formats fs like String, or as set by opts, in the
+// language tag. See WithLocale.
+//
+//	label := size.FormatLocalized(r.Header.Get("Content-Language"))
+func (fs ByteSize) FormatLocalized(tag string, opts ...FormatOption) string {
+	c := newFormatConfig(append([]FormatOption{WithLocale(tag)}, opts...))
+	var buf [64]byte
+	return string(c.appendSize(buf[:0], fs))
+}
+
+// Humanize formats fs with the long unit names of the language tag, such as
+// "1.5 megabytes" or "1 byte" in English, "1,5 mégaoctet" in French and
+// "1.5 兆字节" in Chinese.
+func (fs ByteSize) Humanize(tag string, opts ...FormatOption) string {
+	c := newFormatConfig(append([]FormatOption{WithLocale(tag)}, opts...))
+	c.long = true
+	var buf [64]byte
+	return string(c.appendSize(buf[:0], fs))
+}
+
+// appendLocalized appends size to dst like appendSize, in c.locale.
+func (c formatConfig) appendLocalized(dst []byte, size ByteSize) []byte {
+	l := c.locale
+	switch size {
+	case Unlimited:
+		if l.Unlimited == "" {
+			return append(dst, unlimitedName...)
+		}
+		return append(dst, l.Unlimited...)
+	case Unknown:
+		return append(dst, unknownName...)
+	}
+
+	// Format in English, then swap the separator and the unit.
+	plain := c
+	plain.locale = nil
+	start := len(dst)
+	dst = plain.appendSize(dst, size)
+	end := len(dst)
+	for end > start && isLetter(dst[end-1]) {
+		end--
+	}
+	exp := 0
+	for exp < len(units)-1 && units[exp].unitName != string(dst[end:]) {
+		exp++
+	}
+	var num [32]byte
+	n := copy(num[:], dst[start:end])
+	dst = dst[:start]
+
+	decimal := l.Decimal
+	if decimal == "" {
+		decimal = "."
+	}
+	for _, b := range num[:n] {
+		if b == '.' {
+			dst = append(dst, decimal...)
+		} else {
+			dst = append(dst, b)
+		}
+	}
+
+	name, space := l.Units[exp], l.Space
+	if c.long {
+		name, space = l.Names[exp], l.NameSpace
+		if l.PluralNames[exp] != "" && l.plural(num[:n]) {
+			name = l.PluralNames[exp]
+		}
+	}
+	dst = append(dst, space...)
+	return append(dst, name...)
+}
+
+// plural reports whether the formatted number num takes the plural names.
+func (l *Locale) plural(num []byte) bool {
+	v, _ := strconv.ParseFloat(string(num), 64)
+	if v < 0 {
+		v = -v
+	}
+	if l.Plural == nil {
+		return v != 1
+	}
+	return l.Plural(v)
+}
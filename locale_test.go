@@ -0,0 +1,143 @@
+package bytesizer
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatLocalized(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+		size ByteSize
+		opts []FormatOption
+		want string
+	}{
+		{"English", "en", 1536 * KB, nil, "1.5MB"},
+		{"English region", "en-GB", 2 * GB, nil, "2GB"},
+		{"Unknown language", "xx", 1536 * KB, nil, "1.5MB"},
+		{"Empty tag", "", 512, nil, "512B"},
+		{"French", "fr", 1536 * KB, nil, "1,5\u00a0Mo"},
+		{"French region", "fr-CA", 512, nil, "512\u00a0o"},
+		{"Underscore", "fr_FR", KB, nil, "1\u00a0Ko"},
+		{"Case", "FR", KB, nil, "1\u00a0Ko"},
+		{"German", "de", 1536 * KB, nil, "1,5\u00a0MB"},
+		{"Spanish", "es", -1536 * KB, nil, "-1,5\u00a0MB"},
+		{"Chinese", "zh", 1536 * KB, nil, "1.5MB"},
+		{"Chinese script", "zh-Hans-CN", 2 * GB, nil, "2GB"},
+		{"Chinese unlimited", "zh", Unlimited, nil, "无限制"},
+		{"Japanese", "ja-JP", 1536 * KB, nil, "1.5MB"},
+		{"Japanese unlimited", "ja", Unlimited, nil, "無制限"},
+		{"Options", "fr", 1536 * KB, []FormatOption{WithUnit(KB)}, "1536\u00a0Ko"},
+		{"Precision", "fr", 1600 * KB, []FormatOption{WithPrecision(3)}, "1,563\u00a0Mo"},
+		{"Unlimited", "fr", Unlimited, nil, "illimité"},
+		{"Unknown", "fr", Unknown, nil, "–"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.size.FormatLocalized(tt.tag, tt.opts...))
+		})
+	}
+}
+
+func TestHumanize(t *testing.T) {
+	tests := []struct {
+		name string
+		tag  string
+		size ByteSize
+		want string
+	}{
+		{"Zero", "en", 0, "0 bytes"},
+		{"One byte", "en", 1, "1 byte"},
+		{"Bytes", "en", 512, "512 bytes"},
+		{"One unit", "en", KB, "1 kilobyte"},
+		{"Fraction", "en", 1536 * KB, "1.5 megabytes"},
+		{"Negative one", "en", -MB, "-1 megabyte"},
+		{"French singular", "fr", 1536 * KB, "1,5\u00a0mégaoctet"},
+		{"French plural", "fr", 2 * GB, "2\u00a0gigaoctets"},
+		{"French zero", "fr", 0, "0\u00a0octet"},
+		{"German", "de", 2 * GB, "2\u00a0Gigabyte"},
+		{"Spanish", "es", 2 * GB, "2\u00a0gigabytes"},
+		{"Chinese bytes", "zh", 512, "512 字节"},
+		{"Chinese kilobytes", "zh", 1536, "1.5 千字节"},
+		{"Chinese megabytes", "zh-CN", 1536 * KB, "1.5 兆字节"},
+		{"Chinese petabytes", "zh", 2 * PB, "2 拍字节"},
+		{"Japanese bytes", "ja", 1, "1バイト"},
+		{"Japanese megabytes", "ja", 1536 * KB, "1.5メガバイト"},
+		{"Japanese gigabytes", "ja-JP", -2 * GB, "-2ギガバイト"},
+		{"Unlimited", "en", Unlimited, "unlimited"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.size.Humanize(tt.tag))
+		})
+	}
+}
+
+func TestRegisterLocale(t *testing.T) {
+	_, ok := LookupLocale("pt-BR")
+	assert.False(t, ok)
+
+	RegisterLocale("pt_BR", Locale{
+		Units:       [6]string{"B", "KB", "MB", "GB", "TB", "PB"},
+		Names:       [6]string{"byte", "kilobyte", "megabyte", "gigabyte", "terabyte", "petabyte"},
+		PluralNames: [6]string{"bytes", "kilobytes", "megabytes", "gigabytes", "terabytes", "petabytes"},
+		Plural:      func(n float64) bool { return n >= 2 },
+		Decimal:     ",",
+		Space:       " ",
+		NameSpace:   " ",
+	})
+	t.Cleanup(func() {
+		locales.Lock()
+		delete(locales.m, "pt-br")
+		locales.Unlock()
+	})
+
+	l, ok := LookupLocale("pt-BR")
+	assert.True(t, ok)
+	assert.Equal(t, ",", l.Decimal)
+	_, ok = LookupLocale("pt")
+	assert.False(t, ok, "a region does not cover its language")
+
+	assert.Equal(t, "1,5 MB", (1536 * KB).FormatLocalized("pt-BR"))
+	assert.Equal(t, "1,5 megabyte", (1536 * KB).Humanize("pt-BR-x-test"))
+	assert.Equal(t, "unlimited", Unlimited.FormatLocalized("pt-BR"))
+}
+
+func TestWithLocale(t *testing.T) {
+	assert.Equal(t, []string{"1\u00a0Ko", "1,5\u00a0Ko"}, FormatAll([]ByteSize{KB, 1536}, WithLocale("fr")))
+	assert.Equal(t, "1,5\u00a0Ko", NewFormatter(WithLocale("fr")).Format(1536))
+
+	f := NewCachedFormatter(0)
+	assert.Equal(t, "1,5\u00a0Ko", f.Format(1536, WithLocale("fr")))
+	assert.Equal(t, "1.5KB", f.Format(1536))
+	assert.Equal(t, "1,5\u00a0Ko", f.Format(1536, WithLocale("fr-FR")))
+	assert.Equal(t, 2, f.Len(), "tags of a locale share its cache entries")
+}
+
+func TestLocaleConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				assert.Equal(t, "1,5\u00a0Mo", (1536 * KB).FormatLocalized("fr"))
+				_, ok := LookupLocale("de-AT")
+				assert.True(t, ok)
+			}
+		}()
+	}
+	RegisterLocale("it", Locale{Units: English.Units, Decimal: ","})
+	t.Cleanup(func() {
+		locales.Lock()
+		delete(locales.m, "it")
+		locales.Unlock()
+	})
+	wg.Wait()
+	assert.Equal(t, "1,5MB", (1536 * KB).FormatLocalized("it"))
+}
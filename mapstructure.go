@@ -0,0 +1,55 @@
+package bytesizer
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+var (
+	byteSizeType       = reflect.TypeOf(ByteSize(0))
+	byteSizeStringType = reflect.TypeOf(ByteSizeString(0))
+	byteSizeNumberType = reflect.TypeOf(ByteSizeNumber(0))
+)
+
+// DecodeHook returns a mapstructure decode hook that converts strings such
+// as "512MB" and plain numbers of bytes into ByteSize, ByteSizeString and
+// ByteSizeNumber values. The returned function has the signature of
+// mapstructure.DecodeHookFuncType, so it can be passed directly to
+// mapstructure.DecoderConfig.DecodeHook or viper.DecodeHook without this
+// package depending on mapstructure:
+//
+//	err := v.Unmarshal(&cfg, viper.DecodeHook(bytesizer.DecodeHook()))
+func DecodeHook() func(from, to reflect.Type, data interface{}) (interface{}, error) {
+	return func(from, to reflect.Type, data interface{}) (interface{}, error) {
+		if to != byteSizeType && to != byteSizeStringType && to != byteSizeNumberType {
+			return data, nil
+		}
+
+		size, err := toByteSize(data)
+		if err != nil {
+			return nil, err
+		}
+		return reflect.ValueOf(size).Convert(to).Interface(), nil
+	}
+}
+
+// toByteSize converts a decoded config value into a ByteSize.
+// Strings are parsed with Parse and numbers are taken as a count of bytes.
+func toByteSize(data interface{}) (ByteSize, error) {
+	v := reflect.ValueOf(data)
+	switch v.Kind() {
+	case reflect.String:
+		return Parse(v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return ByteSize(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("%w: %d", ErrOutOfRange, v.Uint())
+		}
+		return ByteSize(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return FromFloat(v.Float())
+	}
+	return 0, fmt.Errorf("cannot convert %T to ByteSize", data)
+}
@@ -0,0 +1,71 @@
+package bytesizer
+
+import (
+	"testing"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeHook(t *testing.T) {
+	type config struct {
+		CacheSize ByteSize       `mapstructure:"cache_size"`
+		Buffer    ByteSize       `mapstructure:"buffer"`
+		Limit     *ByteSize      `mapstructure:"limit"`
+		Human     ByteSizeString `mapstructure:"human"`
+		Number    ByteSizeNumber `mapstructure:"number"`
+		Name      string         `mapstructure:"name"`
+	}
+
+	input := map[string]interface{}{
+		"cache_size": "512MB",
+		"buffer":     4096,
+		"limit":      1.5e3,
+		"human":      "1KB",
+		"number":     uint8(8),
+		"name":       "10MB",
+	}
+
+	var c config
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook: DecodeHook(),
+		Result:     &c,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, dec.Decode(input))
+
+	limit := ByteSize(1500)
+	assert.Equal(t, config{
+		CacheSize: 512 * MB,
+		Buffer:    4 * KB,
+		Limit:     &limit,
+		Human:     ByteSizeString(KB),
+		Number:    ByteSizeNumber(8),
+		Name:      "10MB",
+	}, c)
+}
+
+func TestDecodeHookErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input interface{}
+	}{
+		{"Invalid string", "10XB"},
+		{"Bool", true},
+		{"Slice", []string{"1MB"}},
+		{"Overflow", uint64(1 << 63)},
+		{"Float overflow", 1e30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c struct{ Size ByteSize }
+			dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+				DecodeHook: DecodeHook(),
+				Result:     &c,
+			})
+			assert.NoError(t, err)
+			assert.Error(t, dec.Decode(map[string]interface{}{"size": tt.input}))
+		})
+	}
+}
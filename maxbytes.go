@@ -0,0 +1,73 @@
+package bytesizer
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// StatusCode returns 413 Request Entity Too Large, so that frameworks which
+// look for a StatusCode method report an *ErrSizeExceeded with that status.
+func (e *ErrSizeExceeded) StatusCode() int {
+	return http.StatusRequestEntityTooLarge
+}
+
+// MaxBytesReader is like http.MaxBytesReader, limiting a request body to
+// limit bytes, but reports a body that is too large as an *ErrSizeExceeded.
+// As with http.MaxBytesReader, the server closes the connection after the
+// response.
+func MaxBytesReader(w http.ResponseWriter, r io.ReadCloser, limit ByteSize) io.ReadCloser {
+	return &maxBytesBody{ReadCloser: http.MaxBytesReader(w, r, int64(limit)), limit: limit}
+}
+
+type maxBytesBody struct {
+	io.ReadCloser
+	limit ByteSize
+	read  ByteSize
+}
+
+func (b *maxBytesBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += ByteSize(n)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		err = &ErrSizeExceeded{Limit: b.limit, Read: b.read + 1}
+	}
+	return n, err
+}
+
+// CheckContentLength returns an *ErrSizeExceeded if the declared
+// Content-Length of r is larger than limit, so a request can be refused
+// before its body is read.
+func CheckContentLength(r *http.Request, limit ByteSize) error {
+	if r.ContentLength > int64(limit) {
+		return &ErrSizeExceeded{Limit: limit, Read: ByteSize(r.ContentLength)}
+	}
+	return nil
+}
+
+// MaxBytesHandler returns an http.Handler that limits request bodies to
+// limit bytes. Requests declaring a larger Content-Length are refused with
+// 413 Request Entity Too Large without calling next; for other requests, the
+// body fails with an *ErrSizeExceeded once it goes past the limit, which
+// next can report with WriteSizeExceeded.
+func MaxBytesHandler(next http.Handler, limit ByteSize) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := CheckContentLength(r, limit); err != nil {
+			WriteSizeExceeded(w, err.(*ErrSizeExceeded))
+			return
+		}
+		if r.Body != nil && r.Body != http.NoBody {
+			r2 := *r
+			r2.Body = MaxBytesReader(w, r.Body, limit)
+			r = &r2
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// WriteSizeExceeded replies to the request with 413 Request Entity Too Large
+// and a message naming the limit, e.g. "request body exceeded 25MB limit".
+func WriteSizeExceeded(w http.ResponseWriter, err *ErrSizeExceeded) {
+	http.Error(w, "request body exceeded "+err.Limit.String()+" limit", err.StatusCode())
+}
@@ -0,0 +1,69 @@
+package bytesizer
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// uploadHandler reads the body and reports a size error with WriteSizeExceeded.
+var uploadHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	b, err := io.ReadAll(r.Body)
+	var tooLarge *ErrSizeExceeded
+	if errors.As(err, &tooLarge) {
+		WriteSizeExceeded(w, tooLarge)
+		return
+	}
+	_, _ = io.WriteString(w, Calc(b).String())
+})
+
+func TestMaxBytesHandler(t *testing.T) {
+	tests := []struct {
+		name          string
+		size          int
+		contentLength int64
+		status        int
+		body          string
+	}{
+		{"Under limit", int(KB), int64(KB), http.StatusOK, "1KB"},
+		{"At limit", int(2 * KB), int64(2 * KB), http.StatusOK, "2KB"},
+		{"Declared too large", int(3 * KB), int64(3 * KB), http.StatusRequestEntityTooLarge, "request body exceeded 2KB limit\n"},
+		{"Chunked too large", int(3 * KB), -1, http.StatusRequestEntityTooLarge, "request body exceeded 2KB limit\n"},
+	}
+
+	h := MaxBytesHandler(uploadHandler, 2*KB)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("x", tt.size)))
+			req.ContentLength = tt.contentLength
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, tt.body, rec.Body.String())
+		})
+	}
+}
+
+func TestMaxBytesReader(t *testing.T) {
+	rec := httptest.NewRecorder()
+	body := MaxBytesReader(rec, io.NopCloser(strings.NewReader(strings.Repeat("x", 100))), 10)
+	b, err := io.ReadAll(body)
+	assert.Len(t, b, 10)
+
+	var tooLarge *ErrSizeExceeded
+	assert.True(t, errors.As(err, &tooLarge))
+	assert.Equal(t, ByteSize(10), tooLarge.Limit)
+	assert.Equal(t, ByteSize(11), tooLarge.Read)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, tooLarge.StatusCode())
+}
+
+func TestCheckContentLength(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("Hello"))
+	assert.NoError(t, CheckContentLength(req, 5))
+	assert.Equal(t, &ErrSizeExceeded{Limit: 4, Read: 5}, CheckContentLength(req, 4))
+}
@@ -0,0 +1,90 @@
+package bytesizer
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"text/tabwriter"
+)
+
+// MemReport holds the memory figures of runtime.MemStats as ByteSize.
+type MemReport struct {
+	Alloc        ByteSize // bytes of allocated heap objects
+	TotalAlloc   ByteSize // cumulative bytes allocated for heap objects
+	Sys          ByteSize // total bytes obtained from the OS
+	HeapAlloc    ByteSize // same as Alloc
+	HeapSys      ByteSize // bytes of heap memory obtained from the OS
+	HeapIdle     ByteSize // bytes in idle spans
+	HeapInuse    ByteSize // bytes in in-use spans
+	HeapReleased ByteSize // bytes of physical memory returned to the OS
+	StackInuse   ByteSize // bytes in stack spans
+	StackSys     ByteSize // stack memory obtained from the OS
+	GCSys        ByteSize // bytes of garbage collection metadata
+	OtherSys     ByteSize // miscellaneous off-heap runtime allocations
+	NextGC       ByteSize // target heap size of the next GC cycle
+	NumGC        uint32   // number of completed GC cycles
+}
+
+// MemStats reads the memory statistics of the runtime. Like
+// runtime.ReadMemStats, it stops the world briefly, so avoid calling it on
+// hot paths.
+func MemStats() MemReport {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return NewMemReport(&m)
+}
+
+// NewMemReport converts m into a MemReport.
+func NewMemReport(m *runtime.MemStats) MemReport {
+	return MemReport{
+		Alloc:        ByteSize(m.Alloc),
+		TotalAlloc:   ByteSize(m.TotalAlloc),
+		Sys:          ByteSize(m.Sys),
+		HeapAlloc:    ByteSize(m.HeapAlloc),
+		HeapSys:      ByteSize(m.HeapSys),
+		HeapIdle:     ByteSize(m.HeapIdle),
+		HeapInuse:    ByteSize(m.HeapInuse),
+		HeapReleased: ByteSize(m.HeapReleased),
+		StackInuse:   ByteSize(m.StackInuse),
+		StackSys:     ByteSize(m.StackSys),
+		GCSys:        ByteSize(m.GCSys),
+		OtherSys:     ByteSize(m.OtherSys),
+		NextGC:       ByteSize(m.NextGC),
+		NumGC:        m.NumGC,
+	}
+}
+
+// String summarizes the report on one line, e.g.
+// "heap 12MB/20MB, stack 512KB, sys 30MB, next GC 16MB, 5 GCs".
+func (r MemReport) String() string {
+	return fmt.Sprintf("heap %s/%s, stack %s, sys %s, next GC %s, %d GCs",
+		r.HeapAlloc, r.HeapSys, r.StackInuse, r.Sys, r.NextGC, r.NumGC)
+}
+
+// WriteTable writes the report to w as an aligned two-column table.
+func (r MemReport) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	rows := []struct {
+		name string
+		size ByteSize
+	}{
+		{"Alloc", r.Alloc},
+		{"TotalAlloc", r.TotalAlloc},
+		{"Sys", r.Sys},
+		{"HeapAlloc", r.HeapAlloc},
+		{"HeapSys", r.HeapSys},
+		{"HeapIdle", r.HeapIdle},
+		{"HeapInuse", r.HeapInuse},
+		{"HeapReleased", r.HeapReleased},
+		{"StackInuse", r.StackInuse},
+		{"StackSys", r.StackSys},
+		{"GCSys", r.GCSys},
+		{"OtherSys", r.OtherSys},
+		{"NextGC", r.NextGC},
+	}
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s\n", row.name, row.size)
+	}
+	fmt.Fprintf(tw, "NumGC\t%d\n", r.NumGC)
+	return tw.Flush()
+}
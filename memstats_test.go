@@ -0,0 +1,44 @@
+package bytesizer
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemStats(t *testing.T) {
+	r := MemStats()
+	assert.Greater(t, r.Sys, ByteSize(0))
+	assert.Greater(t, r.HeapAlloc, ByteSize(0))
+	assert.Equal(t, r.Alloc, r.HeapAlloc)
+	assert.GreaterOrEqual(t, r.HeapSys, r.HeapInuse)
+}
+
+func testMemReport() MemReport {
+	return NewMemReport(&runtime.MemStats{
+		Alloc:      uint64(12 * MB),
+		HeapAlloc:  uint64(12 * MB),
+		HeapSys:    uint64(20 * MB),
+		StackInuse: uint64(512 * KB),
+		Sys:        uint64(30 * MB),
+		NextGC:     uint64(16 * MB),
+		NumGC:      5,
+	})
+}
+
+func TestMemReportString(t *testing.T) {
+	assert.Equal(t, "heap 12MB/20MB, stack 512KB, sys 30MB, next GC 16MB, 5 GCs", testMemReport().String())
+}
+
+func TestMemReportWriteTable(t *testing.T) {
+	var b strings.Builder
+	assert.NoError(t, testMemReport().WriteTable(&b))
+
+	lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+	assert.Len(t, lines, 14)
+	assert.Equal(t, "Alloc         12MB", lines[0])
+	assert.Equal(t, "StackInuse    512KB", lines[8])
+	assert.Equal(t, "NumGC         5", lines[13])
+}
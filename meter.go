@@ -0,0 +1,123 @@
+package bytesizer
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// meterTick is how often a Meter folds the bytes counted into its averages.
+const meterTick = 100 * time.Millisecond
+
+// meterWindows are the time constants of the averages of a Meter.
+var meterWindows = [...]time.Duration{time.Second, 10 * time.Second, time.Minute}
+
+// Meter measures throughput as exponentially weighted moving averages over
+// 1 second, 10 seconds and 1 minute, like the meters of metrics libraries.
+// It is safe for concurrent use, and needs no background goroutine.
+//
+//	m := NewMeter()
+//	io.Copy(dst, io.TeeReader(src, m))
+//	log.Println(m) // 1s: 12MB/s, 10s: 10.5MB/s, 1m: 9.8MB/s
+type Meter struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	start   time.Time
+	last    time.Time // end of the last tick
+	pending int64     // bytes counted since the last tick
+	total   int64
+	rates   [len(meterWindows)]float64
+	primed  bool
+}
+
+// NewMeter returns a Meter starting now.
+func NewMeter() *Meter {
+	m := &Meter{now: time.Now}
+	m.start = m.now()
+	m.last = m.start
+	return m
+}
+
+// Add counts n bytes.
+func (m *Meter) Add(n ByteSize) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tick()
+	m.pending += int64(n)
+	m.total += int64(n)
+}
+
+// Write counts len(p) bytes, so a Meter can be used with io.TeeReader or
+// io.MultiWriter. It never fails.
+func (m *Meter) Write(p []byte) (int, error) {
+	m.Add(ByteSize(len(p)))
+	return len(p), nil
+}
+
+// Rate1s returns the average rate over about the last second.
+func (m *Meter) Rate1s() Rate {
+	return m.rate(0)
+}
+
+// Rate10s returns the average rate over about the last 10 seconds.
+func (m *Meter) Rate10s() Rate {
+	return m.rate(1)
+}
+
+// Rate1m returns the average rate over about the last minute.
+func (m *Meter) Rate1m() Rate {
+	return m.rate(2)
+}
+
+// Mean returns the average rate since the Meter was created.
+func (m *Meter) Mean() Rate {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return RateFor(ByteSize(m.total), m.now().Sub(m.start))
+}
+
+// Total returns the bytes counted.
+func (m *Meter) Total() ByteSize {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return ByteSize(m.total)
+}
+
+// String returns the three averages, like "1s: 12MB/s, 10s: 10.5MB/s,
+// 1m: 9.8MB/s".
+func (m *Meter) String() string {
+	return fmt.Sprintf("1s: %s, 10s: %s, 1m: %s", m.Rate1s(), m.Rate10s(), m.Rate1m())
+}
+
+func (m *Meter) rate(i int) Rate {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tick()
+	return Rate(m.rates[i])
+}
+
+// tick folds the pending bytes into the averages for each tick elapsed
+// since the last one. Call with m.mu held.
+func (m *Meter) tick() {
+	ticks := int64(m.now().Sub(m.last) / meterTick)
+	if ticks <= 0 {
+		return
+	}
+	m.last = m.last.Add(time.Duration(ticks) * meterTick)
+
+	// The pending bytes arrived during the first tick, the rest were idle.
+	instant := float64(m.pending) / meterTick.Seconds()
+	m.pending = 0
+	for i, w := range meterWindows {
+		alpha := 1 - math.Exp(-meterTick.Seconds()/w.Seconds())
+		if m.primed {
+			m.rates[i] += alpha * (instant - m.rates[i])
+		} else {
+			m.rates[i] = instant
+		}
+		m.rates[i] *= math.Pow(1-alpha, float64(ticks-1))
+	}
+	m.primed = true
+}
@@ -0,0 +1,45 @@
+package bytesizer
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	m := NewMeter()
+	m.now = func() time.Time { return now }
+	m.start, m.last = now, now
+
+	// A steady 1000KB/s for two minutes.
+	for i := 0; i < 1200; i++ {
+		m.Add(100 * KB)
+		now = now.Add(100 * time.Millisecond)
+	}
+	assert.InDelta(t, float64(1000*KBps), float64(m.Rate1s()), 1)
+	assert.InDelta(t, float64(1000*KBps), float64(m.Rate10s()), 1)
+	assert.InDelta(t, float64(1000*KBps), float64(m.Rate1m()), 1)
+	assert.Equal(t, ByteSize(1200*100*KB), m.Total())
+	assert.InDelta(t, float64(1000*KBps), float64(m.Mean()), 1)
+	assert.Equal(t, "1s: 1000KB/s, 10s: 1000KB/s, 1m: 1000KB/s", m.String())
+
+	// Idle: the short average drops first.
+	now = now.Add(5 * time.Second)
+	assert.Less(t, float64(m.Rate1s()), float64(10*KBps))
+	assert.Greater(t, float64(m.Rate10s()), float64(500*KBps))
+	assert.Less(t, float64(m.Rate10s()), float64(700*KBps))
+	assert.Greater(t, float64(m.Rate1m()), float64(900*KBps))
+}
+
+func TestMeterWriter(t *testing.T) {
+	m := NewMeter()
+	n, err := io.Copy(io.Discard, io.TeeReader(strings.NewReader("Hello"), m))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, ByteSize(5), m.Total())
+	assert.Equal(t, Rate(0), m.Rate1s()) // no tick yet
+}
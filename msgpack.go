@@ -0,0 +1,131 @@
+package bytesizer
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// MarshalMsgpack encodes the ByteSize as the smallest MessagePack integer
+// that holds the number of bytes. It satisfies the Marshaler interface of
+// github.com/vmihailenco/msgpack without depending on it.
+func (fs ByteSize) MarshalMsgpack() ([]byte, error) {
+	v := int64(fs)
+	switch {
+	case v >= 0 && v <= math.MaxInt8:
+		return []byte{byte(v)}, nil // positive fixint
+	case v < 0 && v >= -32:
+		return []byte{byte(v)}, nil // negative fixint
+	case v >= 0 && v <= math.MaxUint8:
+		return []byte{0xcc, byte(v)}, nil
+	case v >= 0 && v <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16([]byte{0xcd}, uint16(v)), nil
+	case v >= 0 && v <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32([]byte{0xce}, uint32(v)), nil
+	case v >= 0:
+		return binary.BigEndian.AppendUint64([]byte{0xcf}, uint64(v)), nil
+	case v >= math.MinInt8:
+		return []byte{0xd0, byte(v)}, nil
+	case v >= math.MinInt16:
+		return binary.BigEndian.AppendUint16([]byte{0xd1}, uint16(v)), nil
+	case v >= math.MinInt32:
+		return binary.BigEndian.AppendUint32([]byte{0xd2}, uint32(v)), nil
+	}
+	return binary.BigEndian.AppendUint64([]byte{0xd3}, uint64(v)), nil
+}
+
+// msgpackNumberSizes maps the MessagePack integer and float format codes to
+// the length of their payload.
+var msgpackNumberSizes = map[byte]int{
+	0xcc: 1, 0xcd: 2, 0xce: 4, 0xcf: 8, // uint 8/16/32/64
+	0xd0: 1, 0xd1: 2, 0xd2: 4, 0xd3: 8, // int 8/16/32/64
+	0xca: 4, 0xcb: 8, // float 32/64
+}
+
+// UnmarshalMsgpack decodes the ByteSize from a MessagePack integer or float
+// holding a number of bytes, or a string in any format accepted by Parse.
+// A MessagePack nil leaves the value unchanged.
+func (fs *ByteSize) UnmarshalMsgpack(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("msgpack: empty byte size")
+	}
+	code, body := data[0], data[1:]
+
+	switch {
+	case code <= 0x7f || code >= 0xe0: // positive and negative fixint
+		if len(body) != 0 {
+			return errInvalidMsgpack
+		}
+		*fs = ByteSize(int8(code))
+		return nil
+	case code >= 0xa0 && code <= 0xbf: // fixstr
+		return fs.unmarshalMsgpackString(body, int(code&0x1f))
+	case code == 0xc0: // nil
+		if len(body) != 0 {
+			return errInvalidMsgpack
+		}
+		return nil
+	case code == 0xd9 && len(body) >= 1: // str 8
+		return fs.unmarshalMsgpackString(body[1:], int(body[0]))
+	case code == 0xda && len(body) >= 2: // str 16
+		return fs.unmarshalMsgpackString(body[2:], int(binary.BigEndian.Uint16(body)))
+	case code == 0xdb && len(body) >= 4: // str 32
+		return fs.unmarshalMsgpackString(body[4:], int(binary.BigEndian.Uint32(body)))
+	}
+
+	size, ok := msgpackNumberSizes[code]
+	if !ok {
+		return fmt.Errorf("msgpack: cannot decode code 0x%02x into ByteSize", code)
+	}
+	if len(body) != size {
+		return errInvalidMsgpack
+	}
+
+	var v int64
+	switch code {
+	case 0xcc:
+		v = int64(body[0])
+	case 0xcd:
+		v = int64(binary.BigEndian.Uint16(body))
+	case 0xce:
+		v = int64(binary.BigEndian.Uint32(body))
+	case 0xcf:
+		u := binary.BigEndian.Uint64(body)
+		if u > math.MaxInt64 {
+			return fmt.Errorf("msgpack: %w: %d", ErrOutOfRange, u)
+		}
+		v = int64(u)
+	case 0xd0:
+		v = int64(int8(body[0]))
+	case 0xd1:
+		v = int64(int16(binary.BigEndian.Uint16(body)))
+	case 0xd2:
+		v = int64(int32(binary.BigEndian.Uint32(body)))
+	case 0xd3:
+		v = int64(binary.BigEndian.Uint64(body))
+	case 0xca, 0xcb:
+		var f float64
+		if code == 0xca {
+			f = float64(math.Float32frombits(binary.BigEndian.Uint32(body)))
+		} else {
+			f = math.Float64frombits(binary.BigEndian.Uint64(body))
+		}
+		size, err := FromFloat(f)
+		if err != nil {
+			return fmt.Errorf("msgpack: %w", err)
+		}
+		v = int64(size)
+	}
+	*fs = ByteSize(v)
+	return nil
+}
+
+var errInvalidMsgpack = errors.New("msgpack: invalid byte size encoding")
+
+func (fs *ByteSize) unmarshalMsgpackString(body []byte, n int) error {
+	if len(body) != n {
+		return errInvalidMsgpack
+	}
+	return fs.UnmarshalText(body)
+}
@@ -0,0 +1,105 @@
+package bytesizer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestByteSizeMarshalMsgpack(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     ByteSize
+		expected []byte
+	}{
+		{"Positive fixint", 127, []byte{0x7f}},
+		{"Negative fixint", -32, []byte{0xe0}},
+		{"uint8", 200, []byte{0xcc, 0xc8}},
+		{"uint16", KB, []byte{0xcd, 0x04, 0x00}},
+		{"uint32", MB, []byte{0xce, 0x00, 0x10, 0x00, 0x00}},
+		{"uint64", 4 * GB, []byte{0xcf, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00}},
+		{"int8", -100, []byte{0xd0, 0x9c}},
+		{"int16", -KB, []byte{0xd1, 0xfc, 0x00}},
+		{"int32", -MB, []byte{0xd2, 0xff, 0xf0, 0x00, 0x00}},
+		{"int64", -4 * GB, []byte{0xd3, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00, 0x00}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.size.MarshalMsgpack()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, b)
+
+			var got ByteSize
+			assert.NoError(t, got.UnmarshalMsgpack(b))
+			assert.Equal(t, tt.size, got)
+		})
+	}
+}
+
+func TestByteSizeUnmarshalMsgpack(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     interface{}
+		expectErr bool
+		expected  ByteSize
+	}{
+		{"int", 2048, false, 2 * KB},
+		{"uint64", uint64(MB), false, MB},
+		{"float32", float32(1536), false, 1536},
+		{"float64", 1.5e3, false, 1500},
+		{"string", "1.5KB", false, 1536},
+		{"long string", "0000000000000000000000000000000001MB", false, MB},
+		{"nil", nil, false, 7},
+		{"invalid string", "lots", true, 0},
+		{"uint64 overflow", uint64(math.MaxUint64), true, 0},
+		{"float overflow", 1e30, true, 0},
+		{"bool", true, true, 0},
+		{"array", []int{1}, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := msgpack.Marshal(tt.value)
+			assert.NoError(t, err)
+
+			var size ByteSize = 7
+			err = size.UnmarshalMsgpack(b)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, size)
+			}
+		})
+	}
+}
+
+func TestByteSizeUnmarshalMsgpackMalformed(t *testing.T) {
+	for _, data := range [][]byte{nil, {0x01, 0x02}, {0xcd, 0x01}, {0xa3, 'K', 'B'}, {0xd9}, {0xc0, 0x00}} {
+		var size ByteSize
+		assert.Error(t, size.UnmarshalMsgpack(data), "% x", data)
+	}
+}
+
+func TestByteSizeMsgpackStruct(t *testing.T) {
+	type entry struct {
+		Key  string
+		Size ByteSize
+	}
+
+	in := entry{Key: "blob", Size: 10 * MB}
+	b, err := msgpack.Marshal(in)
+	assert.NoError(t, err)
+
+	var out entry
+	assert.NoError(t, msgpack.Unmarshal(b, &out))
+	assert.Equal(t, in, out)
+
+	b, err = msgpack.Marshal(map[string]interface{}{"Key": "blob", "Size": "10MB"})
+	assert.NoError(t, err)
+	assert.NoError(t, msgpack.Unmarshal(b, &out))
+	assert.Equal(t, in, out)
+}
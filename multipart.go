@@ -0,0 +1,152 @@
+package bytesizer
+
+import (
+	"fmt"
+	"mime/multipart"
+)
+
+// MultipartLimits are the size limits enforced by a MultipartReader.
+// A zero limit means no limit.
+type MultipartLimits struct {
+	MaxPartSize  ByteSize // limit on the body of each part
+	MaxTotalSize ByteSize // limit on the bodies of all parts together
+}
+
+// MultipartSizeError is returned when a part of a multipart body goes past a
+// limit. It unwraps to an *ErrSizeExceeded, so it can be reported with
+// WriteSizeExceeded.
+type MultipartSizeError struct {
+	Part     int    // index of the part, from 0
+	FormName string // form name of the part
+	FileName string // file name of the part, if any
+	Total    bool   // whether MaxTotalSize was exceeded, rather than MaxPartSize
+	Limit    ByteSize
+	Read     ByteSize // bytes seen when the limit was detected
+}
+
+func (e *MultipartSizeError) Error() string {
+	name := fmt.Sprintf("part %d", e.Part)
+	if e.FormName != "" {
+		name = fmt.Sprintf("part %q", e.FormName)
+	}
+	if e.FileName != "" {
+		name += fmt.Sprintf(" (%s)", e.FileName)
+	}
+	if e.Total {
+		return fmt.Sprintf("%s exceeded %s total limit", name, e.Limit)
+	}
+	return fmt.Sprintf("%s exceeded %s part limit", name, e.Limit)
+}
+
+// Unwrap returns the limit and size as an *ErrSizeExceeded.
+func (e *MultipartSizeError) Unwrap() error {
+	return &ErrSizeExceeded{Limit: e.Limit, Read: e.Read}
+}
+
+// MultipartReader wraps a multipart.Reader to enforce MultipartLimits while
+// the parts are streamed, without buffering them:
+//
+//	mr, err := r.MultipartReader()
+//	if err != nil {
+//		return err
+//	}
+//	parts := NewMultipartReader(mr, MultipartLimits{MaxPartSize: 5 * MB, MaxTotalSize: 25 * MB})
+//	for {
+//		part, err := parts.NextPart()
+//		if err == io.EOF {
+//			break
+//		}
+//		if err != nil {
+//			return err
+//		}
+//		if _, err := io.Copy(dst, part); err != nil {
+//			return err // a *MultipartSizeError if a limit was exceeded
+//		}
+//	}
+type MultipartReader struct {
+	r      *multipart.Reader
+	limits MultipartLimits
+	parts  int
+	total  ByteSize
+}
+
+// NewMultipartReader returns a MultipartReader reading from r.
+func NewMultipartReader(r *multipart.Reader, limits MultipartLimits) *MultipartReader {
+	return &MultipartReader{r: r, limits: limits}
+}
+
+// NextPart returns the next part, or io.EOF when there are no more parts.
+func (m *MultipartReader) NextPart() (*LimitedPart, error) {
+	p, err := m.r.NextPart()
+	if err != nil {
+		return nil, err
+	}
+	part := &LimitedPart{Part: p, m: m, index: m.parts}
+	m.parts++
+	return part, nil
+}
+
+// LimitedPart is a part of a multipart body whose Read enforces the limits
+// of its MultipartReader.
+type LimitedPart struct {
+	*multipart.Part
+	m     *MultipartReader
+	index int
+	read  ByteSize
+}
+
+// Read reads the body of the part. It returns a *MultipartSizeError once the
+// part or the total goes past its limit.
+func (p *LimitedPart) Read(b []byte) (int, error) {
+	limits := p.m.limits
+	if err := p.exceeded(); err != nil {
+		return 0, err
+	}
+
+	// Read at most one byte past the tightest limit, to detect it.
+	max := ByteSize(-1)
+	if limits.MaxPartSize > 0 {
+		max = limits.MaxPartSize - p.read + 1
+	}
+	if limits.MaxTotalSize > 0 {
+		if remaining := limits.MaxTotalSize - p.m.total + 1; max < 0 || remaining < max {
+			max = remaining
+		}
+	}
+	if max >= 0 && ByteSize(len(b)) > max {
+		b = b[:max]
+	}
+
+	n, err := p.Part.Read(b)
+	p.read += ByteSize(n)
+	p.m.total += ByteSize(n)
+
+	if exceeded := p.exceeded(); exceeded != nil {
+		// Hide the extra byte read to detect the limit.
+		return n - 1, exceeded
+	}
+	return n, err
+}
+
+// exceeded returns a *MultipartSizeError if a limit has been exceeded.
+func (p *LimitedPart) exceeded() error {
+	limits := p.m.limits
+	var total bool
+	var limit, read ByteSize
+	switch {
+	case limits.MaxPartSize > 0 && p.read > limits.MaxPartSize:
+		limit, read = limits.MaxPartSize, p.read
+	case limits.MaxTotalSize > 0 && p.m.total > limits.MaxTotalSize:
+		total, limit, read = true, limits.MaxTotalSize, p.m.total
+	default:
+		return nil
+	}
+	return &MultipartSizeError{
+		Part:     p.index,
+		FormName: p.FormName(),
+		FileName: p.FileName(),
+		Total:    total,
+		Limit:    limit,
+		Read:     read,
+	}
+}
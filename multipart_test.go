@@ -0,0 +1,130 @@
+package bytesizer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// multipartBody builds a multipart body with one file part per size.
+func multipartBody(t *testing.T, sizes ...int) *multipart.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for i, size := range sizes {
+		fw, err := w.CreateFormFile("file", strings.Repeat("f", i+1)+".bin")
+		assert.NoError(t, err)
+		_, err = fw.Write(make([]byte, size))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+	return multipart.NewReader(&buf, w.Boundary())
+}
+
+// readParts reads every part and returns the sizes read and the first error.
+func readParts(m *MultipartReader) ([]int, error) {
+	var sizes []int
+	for {
+		part, err := m.NextPart()
+		if err == io.EOF {
+			return sizes, nil
+		}
+		if err != nil {
+			return sizes, err
+		}
+		n, err := io.Copy(io.Discard, part)
+		sizes = append(sizes, int(n))
+		if err != nil {
+			return sizes, err
+		}
+	}
+}
+
+func TestMultipartReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		sizes    []int
+		limits   MultipartLimits
+		read     []int
+		expected *MultipartSizeError
+	}{
+		{
+			name:   "Within limits",
+			sizes:  []int{1024, 2048},
+			limits: MultipartLimits{MaxPartSize: 2 * KB, MaxTotalSize: 3 * KB},
+			read:   []int{1024, 2048},
+		},
+		{
+			name:   "No limits",
+			sizes:  []int{4096, 4096},
+			limits: MultipartLimits{},
+			read:   []int{4096, 4096},
+		},
+		{
+			name:     "Part too large",
+			sizes:    []int{1024, 3000},
+			limits:   MultipartLimits{MaxPartSize: 2 * KB},
+			read:     []int{1024, 2048},
+			expected: &MultipartSizeError{Part: 1, FormName: "file", FileName: "ff.bin", Limit: 2 * KB, Read: 2*KB + 1},
+		},
+		{
+			name:     "Total too large",
+			sizes:    []int{2048, 2048},
+			limits:   MultipartLimits{MaxPartSize: 2 * KB, MaxTotalSize: 3 * KB},
+			read:     []int{2048, 1024},
+			expected: &MultipartSizeError{Part: 1, FormName: "file", FileName: "ff.bin", Total: true, Limit: 3 * KB, Read: 3*KB + 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			read, err := readParts(NewMultipartReader(multipartBody(t, tt.sizes...), tt.limits))
+			assert.Equal(t, tt.read, read)
+			if tt.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var sizeErr *MultipartSizeError
+			assert.True(t, errors.As(err, &sizeErr))
+			assert.Equal(t, tt.expected, sizeErr)
+
+			var exceeded *ErrSizeExceeded
+			assert.True(t, errors.As(err, &exceeded))
+			assert.Equal(t, tt.expected.Limit, exceeded.Limit)
+		})
+	}
+}
+
+func TestMultipartSizeError(t *testing.T) {
+	tests := []struct {
+		err      *MultipartSizeError
+		expected string
+	}{
+		{&MultipartSizeError{Part: 1, FormName: "avatar", FileName: "me.png", Limit: 5 * MB}, `part "avatar" (me.png) exceeded 5MB part limit`},
+		{&MultipartSizeError{Part: 2, FormName: "doc", Total: true, Limit: 25 * MB}, `part "doc" exceeded 25MB total limit`},
+		{&MultipartSizeError{Part: 3, Limit: KB}, `part 3 exceeded 1KB part limit`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.EqualError(t, tt.err, tt.expected)
+		})
+	}
+}
+
+func TestLimitedPartAfterExceeded(t *testing.T) {
+	m := NewMultipartReader(multipartBody(t, 100), MultipartLimits{MaxPartSize: 10})
+	part, err := m.NextPart()
+	assert.NoError(t, err)
+	_, err = io.ReadAll(part)
+	assert.Error(t, err)
+
+	n, err := part.Read(make([]byte, 10))
+	assert.Equal(t, 0, n)
+	assert.Error(t, err)
+}
@@ -0,0 +1,127 @@
+package bytesizer
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InterfaceCounters holds the traffic counters of a network interface.
+type InterfaceCounters struct {
+	Name    string
+	RxBytes ByteSize // bytes received
+	TxBytes ByteSize // bytes transmitted
+}
+
+// InterfaceRate holds the traffic rates of a network interface.
+type InterfaceRate struct {
+	Name string
+	Rx   Rate
+	Tx   Rate
+}
+
+// NetCounters returns the traffic counters of the network interfaces, from
+// /proc/net/dev on Linux and the interface list of sysctl on macOS. On
+// other systems it returns an error.
+//
+// On macOS the counters are 32 bits wide and wrap around every 4GB;
+// NetRate handles a single wrap between samples there.
+func NetCounters() ([]InterfaceCounters, error) {
+	return netCounters()
+}
+
+// ParseNetDev parses data in the format of /proc/net/dev.
+func ParseNetDev(r io.Reader) ([]InterfaceCounters, error) {
+	var counters []InterfaceCounters
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		name, data, ok := strings.Cut(sc.Text(), ":")
+		if !ok {
+			continue // header lines
+		}
+		fields := strings.Fields(data)
+		if len(fields) < 9 {
+			return nil, fmt.Errorf("invalid /proc/net/dev line %q", sc.Text())
+		}
+		rx, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid /proc/net/dev line %q", sc.Text())
+		}
+		tx, err := strconv.ParseUint(fields[8], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid /proc/net/dev line %q", sc.Text())
+		}
+		counters = append(counters, InterfaceCounters{
+			Name:    strings.TrimSpace(name),
+			RxBytes: ByteSize(rx),
+			TxBytes: ByteSize(tx),
+		})
+	}
+	return counters, sc.Err()
+}
+
+// NetRate turns two snapshots of counters taken elapsed apart into rates,
+// for the interfaces present in both. A counter that went backwards counts
+// as zero traffic, unless it is a wrapped 32-bit counter on macOS.
+func NetRate(before, after []InterfaceCounters, elapsed time.Duration) []InterfaceRate {
+	prev := make(map[string]InterfaceCounters, len(before))
+	for _, c := range before {
+		prev[c.Name] = c
+	}
+
+	var rates []InterfaceRate
+	for _, c := range after {
+		p, ok := prev[c.Name]
+		if !ok {
+			continue
+		}
+		rates = append(rates, InterfaceRate{
+			Name: c.Name,
+			Rx:   RateFor(counterDelta(p.RxBytes, c.RxBytes), elapsed),
+			Tx:   RateFor(counterDelta(p.TxBytes, c.TxBytes), elapsed),
+		})
+	}
+	return rates
+}
+
+// counterDelta returns the growth of a counter from a to b. On platforms
+// with 32-bit counters, a counter that wrapped around is accounted for;
+// other decreases are resets.
+func counterDelta(a, b ByteSize) ByteSize {
+	switch {
+	case b >= a:
+		return b - a
+	case netCounters32 && a < 1<<32:
+		return b + 1<<32 - a
+	default:
+		return 0
+	}
+}
+
+// SampleNetRate reads the counters twice, interval apart, and returns the
+// rates of the interfaces. It returns early with ctx.Err() if ctx is done.
+func SampleNetRate(ctx context.Context, interval time.Duration) ([]InterfaceRate, error) {
+	before, err := NetCounters()
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+
+	t := time.NewTimer(interval)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-t.C:
+	}
+
+	after, err := NetCounters()
+	if err != nil {
+		return nil, err
+	}
+	return NetRate(before, after, time.Since(start)), nil
+}
@@ -0,0 +1,39 @@
+package bytesizer
+
+import (
+	"net"
+	"os"
+	"syscall"
+)
+
+// netCounters32 reports whether interface counters are 32 bits wide.
+const netCounters32 = true
+
+func netCounters() ([]InterfaceCounters, error) {
+	rib, err := syscall.RouteRIB(syscall.NET_RT_IFLIST, 0)
+	if err != nil {
+		return nil, os.NewSyscallError("sysctl", err)
+	}
+	msgs, err := syscall.ParseRoutingMessage(rib)
+	if err != nil {
+		return nil, os.NewSyscallError("sysctl", err)
+	}
+
+	var counters []InterfaceCounters
+	for _, m := range msgs {
+		ifm, ok := m.(*syscall.InterfaceMessage)
+		if !ok {
+			continue
+		}
+		ifi, err := net.InterfaceByIndex(int(ifm.Header.Index))
+		if err != nil {
+			continue
+		}
+		counters = append(counters, InterfaceCounters{
+			Name:    ifi.Name,
+			RxBytes: ByteSize(ifm.Header.Data.Ibytes),
+			TxBytes: ByteSize(ifm.Header.Data.Obytes),
+		})
+	}
+	return counters, nil
+}
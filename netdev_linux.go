@@ -0,0 +1,15 @@
+package bytesizer
+
+import "os"
+
+// netCounters32 reports whether interface counters are 32 bits wide.
+const netCounters32 = false
+
+func netCounters() ([]InterfaceCounters, error) {
+	f, err := os.Open("/proc/net/dev")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseNetDev(f)
+}
@@ -0,0 +1,12 @@
+//go:build !linux && !darwin
+
+package bytesizer
+
+import "errors"
+
+// netCounters32 reports whether interface counters are 32 bits wide.
+const netCounters32 = false
+
+func netCounters() ([]InterfaceCounters, error) {
+	return nil, errors.New("network interface counters are not supported on this platform")
+}
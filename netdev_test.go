@@ -0,0 +1,76 @@
+package bytesizer
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testNetDev = `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo: 1048576     100    0    0    0     0          0         0  1048576     100    0    0    0     0       0          0
+  eth0: 5368709120 4000000    0    0    0     0          0         0 1073741824 2000000    0    0    0     0       0          0
+`
+
+func TestParseNetDev(t *testing.T) {
+	counters, err := ParseNetDev(strings.NewReader(testNetDev))
+	assert.NoError(t, err)
+	assert.Equal(t, []InterfaceCounters{
+		{Name: "lo", RxBytes: MB, TxBytes: MB},
+		{Name: "eth0", RxBytes: 5 * GB, TxBytes: GB},
+	}, counters)
+
+	_, err = ParseNetDev(strings.NewReader("eth0: 1 2 3\n"))
+	assert.Error(t, err)
+}
+
+func TestNetRate(t *testing.T) {
+	before := []InterfaceCounters{
+		{Name: "lo", RxBytes: MB, TxBytes: MB},
+		{Name: "eth0", RxBytes: 5 * GB, TxBytes: GB},
+		{Name: "gone0", RxBytes: KB},
+	}
+	after := []InterfaceCounters{
+		{Name: "lo", RxBytes: MB, TxBytes: MB},
+		{Name: "eth0", RxBytes: 5*GB + 20*MB, TxBytes: GB + 2*MB},
+		{Name: "new0", RxBytes: KB},
+	}
+
+	assert.Equal(t, []InterfaceRate{
+		{Name: "lo"},
+		{Name: "eth0", Rx: 10 * MBps, Tx: MBps},
+	}, NetRate(before, after, 2*time.Second))
+}
+
+func TestCounterDelta(t *testing.T) {
+	assert.Equal(t, KB, counterDelta(KB, 2*KB))
+	assert.Equal(t, ByteSize(0), counterDelta(5*GB, KB))
+	if netCounters32 {
+		assert.Equal(t, 2*KB, counterDelta(4*GB-KB, KB))
+	} else {
+		assert.Equal(t, ByteSize(0), counterDelta(4*GB-KB, KB))
+	}
+}
+
+func TestSampleNetRate(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("network counters are not supported")
+	}
+
+	counters, err := NetCounters()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, counters)
+
+	rates, err := SampleNetRate(context.Background(), 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Len(t, rates, len(counters))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = SampleNetRate(ctx, time.Hour)
+	assert.Equal(t, context.Canceled, err)
+}
@@ -0,0 +1,142 @@
+package bytesizer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// ByteSizeObject is a ByteSize which is encoded to JSON as an object with
+// separate value and unit fields, e.g. {"value":1.5,"unit":"GB"}, for API
+// consumers that render the number and the unit on their own.
+//
+// The unit is the one chosen by String. When the rounded value is not exact
+// the size is encoded in bytes instead, e.g. {"value":1049600,"unit":"B"},
+// so round trips never lose precision.
+//
+// Unlimited and Unknown have no value, and are encoded with their own units
+// as {"value":null,"unit":"unlimited"} and {"value":null,"unit":"unknown"}.
+//
+// On input the object must contain exactly the value and unit fields, and
+// the unit must be one of B, KB, MB, GB, TB or PB, or a sentinel unit with
+// a null value.
+type ByteSizeObject ByteSize
+
+type byteSizeObject struct {
+	Value json.RawMessage `json:"value"`
+	Unit  *string         `json:"unit"`
+}
+
+// sentinelUnits are the units of the sentinels in a ByteSizeObject.
+var sentinelUnits = map[string]ByteSize{
+	"unlimited": Unlimited,
+	"unknown":   Unknown,
+}
+
+// MarshalJSON encodes the value as a {"value", "unit"} JSON object.
+func (o ByteSizeObject) MarshalJSON() ([]byte, error) {
+	switch ByteSize(o) {
+	case Unlimited:
+		return []byte(`{"value":null,"unit":"unlimited"}`), nil
+	case Unknown:
+		return []byte(`{"value":null,"unit":"unknown"}`), nil
+	}
+	text, err := ByteSize(o).AppendText(nil)
+	if err != nil {
+		return nil, err
+	}
+	i := bytes.IndexAny(text, "BKMGTP")
+	b := append([]byte(`{"value":`), text[:i]...)
+	b = append(b, `,"unit":"`...)
+	b = append(b, text[i:]...)
+	return append(b, `"}`...), nil
+}
+
+// UnmarshalJSON decodes the value from a {"value", "unit"} JSON object.
+// A JSON null leaves the value unchanged.
+func (o *ByteSizeObject) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+
+	var obj byteSizeObject
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&obj); err != nil {
+		return fmt.Errorf("invalid byte size object: %w", err)
+	}
+	if len(obj.Value) == 0 {
+		return fmt.Errorf("invalid byte size object: missing value")
+	}
+	if obj.Unit == nil {
+		return fmt.Errorf("invalid byte size object: missing unit")
+	}
+
+	if v, ok := sentinelUnits[*obj.Unit]; ok {
+		if string(obj.Value) != "null" {
+			return fmt.Errorf("invalid byte size object: %s has no value", *obj.Unit)
+		}
+		*o = ByteSizeObject(v)
+		return nil
+	}
+	unit, ok := lookupUnit(*obj.Unit)
+	if !ok {
+		return fmt.Errorf("invalid byte size object: unknown unit %q", *obj.Unit)
+	}
+
+	if i, err := strconv.ParseInt(string(obj.Value), 10, 64); err == nil {
+		v := ByteSize(i) * unit
+		if v/unit != ByteSize(i) || v == Unknown {
+			return fmt.Errorf("%w: %s%s", ErrOutOfRange, obj.Value, *obj.Unit)
+		}
+		*o = ByteSizeObject(v)
+		return nil
+	}
+
+	var f float64
+	if c := obj.Value[0]; c != '-' && (c < '0' || c > '9') || json.Unmarshal(obj.Value, &f) != nil {
+		return fmt.Errorf("invalid byte size object: invalid value %s", obj.Value)
+	}
+	v, err := FromFloat(f * float64(unit))
+	if err != nil {
+		return fmt.Errorf("invalid byte size object: %w", err)
+	}
+	*o = ByteSizeObject(v)
+	return nil
+}
+
+// JSONSchema returns the JSON Schema of a ByteSizeObject.
+func (ByteSizeObject) JSONSchema() map[string]interface{} {
+	names := make([]interface{}, len(units), len(units)+2)
+	for i, u := range units {
+		names[i] = u.unitName
+	}
+	names = append(names, "unlimited", "unknown")
+	return map[string]interface{}{
+		"type":                 "object",
+		"required":             []interface{}{"value", "unit"},
+		"additionalProperties": false,
+		"properties": map[string]interface{}{
+			"value": map[string]interface{}{"type": []interface{}{"number", "null"}},
+			"unit":  map[string]interface{}{"type": "string", "enum": names},
+		},
+		"examples": []interface{}{map[string]interface{}{"value": 1.5, "unit": "GB"}},
+	}
+}
+
+// JSONSchemaBytes returns JSONSchema encoded as JSON.
+// It implements the RawExposer interface of github.com/swaggest/jsonschema-go.
+func (o ByteSizeObject) JSONSchemaBytes() ([]byte, error) {
+	return json.Marshal(o.JSONSchema())
+}
+
+// lookupUnit returns the unit with the exact name, such as "KB".
+func lookupUnit(name string) (ByteSize, bool) {
+	for _, u := range units {
+		if u.unitName == name {
+			return u.size, true
+		}
+	}
+	return 0, false
+}
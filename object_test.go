@@ -0,0 +1,100 @@
+package bytesizer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByteSizeObjectMarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     ByteSize
+		expected string
+	}{
+		{"Zero", 0, `{"value":0,"unit":"B"}`},
+		{"Bytes", 532, `{"value":532,"unit":"B"}`},
+		{"Gigabytes", 1536 * MB, `{"value":1.5,"unit":"GB"}`},
+		{"Inexact", 1025 * KB, `{"value":1049600,"unit":"B"}`},
+		{"Unlimited", Unlimited, `{"value":null,"unit":"unlimited"}`},
+		{"Unknown", Unknown, `{"value":null,"unit":"unknown"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(ByteSizeObject(tt.size))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(b))
+
+			var o ByteSizeObject
+			assert.NoError(t, json.Unmarshal(b, &o))
+			assert.Equal(t, tt.size, ByteSize(o))
+		})
+	}
+}
+
+func TestByteSizeObjectUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expectErr bool
+		expected  ByteSize
+	}{
+		{"Integer", `{"value":10,"unit":"MB"}`, false, 10 * MB},
+		{"Float", `{"unit":"GB","value":1.5}`, false, 1536 * MB},
+		{"Bytes", `{"value":1049600,"unit":"B"}`, false, 1025 * KB},
+		{"Null", `null`, false, 7},
+		{"Lowercase unit", `{"value":1,"unit":"gb"}`, true, 0},
+		{"Unknown unit", `{"value":1,"unit":"XB"}`, true, 0},
+		{"Unit with value", `{"value":1,"unit":"1GB"}`, true, 0},
+		{"Missing unit", `{"value":1}`, true, 0},
+		{"Missing value", `{"unit":"GB"}`, true, 0},
+		{"Unknown field", `{"value":1,"unit":"GB","scale":2}`, true, 0},
+		{"String value", `{"value":"1","unit":"GB"}`, true, 0},
+		{"Null value", `{"value":null,"unit":"GB"}`, true, 0},
+		{"Unlimited", `{"unit":"unlimited","value":null}`, false, Unlimited},
+		{"Unknown", `{"value":null,"unit":"unknown"}`, false, Unknown},
+		{"Unlimited with value", `{"value":1,"unit":"unlimited"}`, true, 0},
+		{"Unknown without value", `{"unit":"unknown"}`, true, 0},
+		{"Uppercase sentinel", `{"value":null,"unit":"Unlimited"}`, true, 0},
+		{"Number", `1024`, true, 0},
+		{"String", `"1GB"`, true, 0},
+		{"Out of range", `{"value":9000000,"unit":"PB"}`, true, 0},
+		{"Float out of range", `{"value":8192.5,"unit":"PB"}`, true, 0},
+		{"Negative float out of range", `{"value":-8192.5,"unit":"PB"}`, true, 0},
+		{"Unknown value", `{"value":-8192,"unit":"PB"}`, true, 0},
+		{"Unknown bytes", `{"value":-9223372036854775808,"unit":"B"}`, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var o ByteSizeObject = 7
+			err := json.Unmarshal([]byte(tt.input), &o)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, ByteSize(o))
+			}
+		})
+	}
+}
+
+func TestByteSizeObjectOutOfRange(t *testing.T) {
+	for _, input := range []string{`{"value":9000000,"unit":"PB"}`, `{"value":8192.5,"unit":"PB"}`, `{"value":1e300,"unit":"B"}`} {
+		var o ByteSizeObject
+		assert.ErrorIs(t, json.Unmarshal([]byte(input), &o), ErrOutOfRange, input)
+	}
+}
+
+func TestByteSizeObjectStruct(t *testing.T) {
+	type quota struct {
+		Used  ByteSizeObject `json:"used"`
+		Limit ByteSizeObject `json:"limit"`
+	}
+
+	b, err := json.Marshal(quota{Used: ByteSizeObject(512 * KB), Limit: ByteSizeObject(2 * GB)})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"used":{"value":512,"unit":"KB"},"limit":{"value":2,"unit":"GB"}}`, string(b))
+}
@@ -0,0 +1,45 @@
+// Package otelattr records bytesizer values on OpenTelemetry spans and metrics.
+//
+// Sizes are recorded as int64 numbers of bytes, matching the OpenTelemetry
+// semantic conventions (e.g. http.request.body.size) and the UCUM unit "By".
+// A humanized string variant is available for span attributes meant for people.
+package otelattr
+
+import (
+	"context"
+
+	"github.com/iamlongalong/bytesizer"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Unit is the UCUM unit for bytes, for use with metric.WithUnit.
+const Unit = "By"
+
+// Size returns an attribute holding v as an int64 number of bytes.
+func Size(key string, v bytesizer.ByteSize) attribute.KeyValue {
+	return attribute.Int64(key, int64(v))
+}
+
+// SizeString returns an attribute holding v as a humanized string, e.g. "1.5MB".
+func SizeString(key string, v bytesizer.ByteSize) attribute.KeyValue {
+	return attribute.String(key, v.String())
+}
+
+// SetSize sets an attribute holding v in bytes on span.
+func SetSize(span trace.Span, key string, v bytesizer.ByteSize) {
+	span.SetAttributes(Size(key, v))
+}
+
+// RecordSize records v in bytes on h. The histogram should be created with
+// metric.WithUnit(Unit).
+func RecordSize(ctx context.Context, h metric.Int64Histogram, v bytesizer.ByteSize, opts ...metric.RecordOption) {
+	h.Record(ctx, int64(v), opts...)
+}
+
+// AddSize adds v in bytes to c. The counter should be created with
+// metric.WithUnit(Unit).
+func AddSize(ctx context.Context, c metric.Int64Counter, v bytesizer.ByteSize, opts ...metric.AddOption) {
+	c.Add(ctx, int64(v), opts...)
+}
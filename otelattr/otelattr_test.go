@@ -0,0 +1,62 @@
+package otelattr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/iamlongalong/bytesizer"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestAttributes(t *testing.T) {
+	size := 1536 * bytesizer.KB
+	assert.Equal(t, attribute.Int64("http.request.body.size", 1572864), Size("http.request.body.size", size))
+	assert.Equal(t, attribute.String("cache.size", "1.5MB"), SizeString("cache.size", size))
+}
+
+func TestSetSize(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	_, span := tp.Tracer("test").Start(context.Background(), "upload")
+	SetSize(span, "upload.size", 10*bytesizer.MB)
+	span.End()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, []attribute.KeyValue{attribute.Int64("upload.size", 10485760)}, spans[0].Attributes())
+}
+
+func TestRecordSizeAndAddSize(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+
+	h, err := meter.Int64Histogram("http.request.body.size", metric.WithUnit(Unit))
+	assert.NoError(t, err)
+	c, err := meter.Int64Counter("transfer.bytes", metric.WithUnit(Unit))
+	assert.NoError(t, err)
+
+	RecordSize(ctx, h, 2*bytesizer.KB)
+	RecordSize(ctx, h, 6*bytesizer.KB)
+	AddSize(ctx, c, bytesizer.MB, metric.WithAttributes(attribute.String("direction", "rx")))
+
+	var rm metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(ctx, &rm))
+	metrics := rm.ScopeMetrics[0].Metrics
+	assert.Len(t, metrics, 2)
+
+	hist := metrics[0].Data.(metricdata.Histogram[int64])
+	assert.Equal(t, "By", metrics[0].Unit)
+	assert.Equal(t, uint64(2), hist.DataPoints[0].Count)
+	assert.Equal(t, int64(8*bytesizer.KB), hist.DataPoints[0].Sum)
+
+	sum := metrics[1].Data.(metricdata.Sum[int64])
+	assert.Equal(t, int64(bytesizer.MB), sum.DataPoints[0].Value)
+}
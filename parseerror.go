@@ -0,0 +1,225 @@
+package bytesizer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrSyntax is returned for a size that is not a number followed by a
+	// unit, such as "1 MB" or "1,5MB".
+	ErrSyntax = errors.New("invalid syntax")
+	// ErrUnknownUnit is returned for a size with a unit Parse does not know,
+	// such as "10XB".
+	ErrUnknownUnit = errors.New("unknown unit")
+
+	errMissingNumber = fmt.Errorf("%w: missing number", ErrSyntax)
+	errMissingUnit   = fmt.Errorf("%w: missing unit", ErrSyntax)
+	errTrailingSpace = fmt.Errorf("%w: trailing space", ErrSyntax)
+)
+
+// ParseError is the error Parse and Parser return for an invalid size. It
+// locates the failure in the input, so that a CLI or a validator can point
+// at it, and suggests a fix when one is likely.
+//
+//	var perr *ParseError
+//	if errors.As(err, &perr) && perr.Suggestion != "" {
+//	    fmt.Printf("did you mean %q?\n", perr.Suggestion)
+//	}
+type ParseError struct {
+	// Input is the text parsed, after the normalization of options such as
+	// AllowSpaces.
+	Input string
+	// Offset is the byte offset in Input of the failure.
+	Offset int
+	// Token is the text at Offset that could not be parsed, if any.
+	Token string
+	// Suggestion is a similar input that fixes the failure, such as
+	// "1.5MiB" for "1.5MIB", or "" if none is likely.
+	Suggestion string
+	// Err is the cause, which errors.Is matches with ErrSyntax,
+	// ErrUnknownUnit, ErrNotFinite or ErrOutOfRange.
+	Err error
+}
+
+// Error returns a message such as
+// `invalid size "10MIB": unknown unit "MIB" at offset 2, did you mean "10MiB"`.
+func (e *ParseError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid size %q: %v", e.Input, e.Err)
+	if e.Token != "" {
+		fmt.Fprintf(&b, " %q", e.Token)
+	}
+	if !errors.Is(e.Err, ErrOutOfRange) {
+		fmt.Fprintf(&b, " at offset %d", e.Offset)
+	}
+	if e.Suggestion != "" {
+		fmt.Fprintf(&b, ", did you mean %q", e.Suggestion)
+	}
+	return b.String()
+}
+
+// Unwrap returns e.Err.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// newParseError returns the ParseError for s, which parse rejected with
+// err. Parsing stays fast by only reporting a cause; the failure is located
+// here, when it is needed.
+func newParseError(s string, err error) *ParseError {
+	e := &ParseError{Input: s, Err: err}
+	switch err {
+	case ErrOutOfRange:
+		return e
+	case ErrNotFinite:
+		if v, _, err := splitSize(s); err == nil {
+			e.Token = v
+		}
+		return e
+	}
+
+	e.Err = ErrSyntax
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	n := numberEnd(s[i:])
+	rest := s[i+n:]
+	u := 0
+	for u < len(rest) && isLetter(rest[u]) {
+		u++
+	}
+	switch {
+	case s == "":
+	case n == 0:
+		e.Offset, e.Token = i, s[i:]
+	case !isDecimal(s[:i+n]):
+		e.Offset, e.Token = i, s[i:i+n]
+	case u > 0 && u == len(rest):
+		e.Err = ErrUnknownUnit
+		e.Offset, e.Token = i+n, rest
+	case u > 0 && isParseUnit(rest[:u]):
+		// Text after a unit, such as "10KB 20".
+		e.Offset, e.Token = i+n+u, rest[u:]
+	default:
+		e.Offset, e.Token = i+n, rest
+	}
+	if sug := suggest(s); sug != s {
+		e.Suggestion = sug
+	}
+	return e
+}
+
+// suggest returns an input similar to s that parses, or "".
+func suggest(s string) string {
+	// Digits of other scripts and full-width forms, as in "１２８ＭＢ".
+	if t := normalizeDigits(s); t != s {
+		if _, err := parse(t); err == nil {
+			return t
+		}
+		s = t
+	}
+
+	// Spaces, as in "1 MB" or "1M B".
+	if t := strings.Join(strings.FieldsFunc(s, isSpace), ""); t != s {
+		if _, err := parse(t); err == nil {
+			return t
+		}
+		s = t
+	}
+
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	n := numberEnd(s[i:])
+	if n == 0 {
+		return ""
+	}
+	if unit := suggestUnit(s[i+n:]); unit != "" {
+		if _, err := parse(s[:i+n] + unit); err == nil {
+			return s[:i+n] + unit
+		}
+	}
+	return ""
+}
+
+// unitSuggestions maps other spellings of units, upper-cased, to the units
+// Parse accepts.
+var unitSuggestions = func() map[string]string {
+	m := map[string]string{"B": "B", "BYTE": "B", "BYTES": "B"}
+	for i, p := range []string{"K", "M", "G", "T", "P"} {
+		si := []string{"KILO", "MEGA", "GIGA", "TERA", "PETA"}[i]
+		iec := []string{"KIBI", "MEBI", "GIBI", "TEBI", "PEBI"}[i]
+		for _, name := range []string{p, p + "B", p + "BS", p + "BYTE", p + "BYTES", si, si + "BYTE", si + "BYTES"} {
+			m[name] = p + "B"
+		}
+		for _, name := range []string{p + "I", p + "IB", iec, iec + "BYTE", iec + "BYTES"} {
+			m[name] = p + "iB"
+		}
+	}
+	return m
+}()
+
+// suggestUnit returns the unit Parse accepts that name likely means, or "".
+// Besides other spellings, it corrects a single typo when only one unit is
+// that close, such as "GiV" for "GiB".
+func suggestUnit(name string) string {
+	if name == "" {
+		return ""
+	}
+	upper := strings.ToUpper(name)
+	if unit, ok := unitSuggestions[upper]; ok {
+		return unit
+	}
+
+	var best string
+	for _, unit := range []string{"B", "KB", "MB", "GB", "TB", "PB", "KiB", "MiB", "GiB", "TiB", "PiB"} {
+		if editDistance(upper, strings.ToUpper(unit)) == 1 {
+			if best != "" {
+				return "" // ambiguous, as "XB" is
+			}
+			best = unit
+		}
+	}
+	return best
+}
+
+// isParseUnit reports whether Parse accepts name as a unit.
+func isParseUnit(name string) bool {
+	_, ok := parseUnits[name]
+	if !ok {
+		_, ok = parseUnits[strings.ToUpper(name)]
+	}
+	return ok
+}
+
+// editDistance returns the Levenshtein distance between a and b, in bytes.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, minInt(cur[j-1]+1, prev[j-1]+cost))
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,114 @@
+package bytesizer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		input      string
+		err        error
+		offset     int
+		token      string
+		suggestion string
+	}{
+		{"", ErrSyntax, 0, "", ""},
+		{"10XB", ErrUnknownUnit, 2, "XB", ""},
+		{"10MIB", ErrUnknownUnit, 2, "MIB", "10MiB"},
+		{"1mib", ErrUnknownUnit, 1, "mib", "1MiB"},
+		{"-5k", ErrUnknownUnit, 2, "k", "-5KB"},
+		{"10megabytes", ErrUnknownUnit, 2, "megabytes", "10MB"},
+		{"2 gibibytes", ErrSyntax, 1, " gibibytes", "2GiB"},
+		{"10GiV", ErrUnknownUnit, 2, "GiV", "10GiB"},
+		{"1 MB", ErrSyntax, 1, " MB", "1MB"},
+		{"1M B", ErrSyntax, 1, "M B", "1MB"},
+		{"1,5MB", ErrSyntax, 1, ",5MB", ""},
+		{"10KB 20", ErrSyntax, 4, " 20", ""},
+		{"1.5.5KB", ErrSyntax, 0, "1.5.5", ""},
+		{"+OneKB", ErrSyntax, 1, "OneKB", ""},
+		{"unlimted", ErrSyntax, 0, "unlimted", ""},
+		{"NaNKB", ErrNotFinite, 0, "NaN", ""},
+		{"8192PB", ErrOutOfRange, 0, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			var perr *ParseError
+			if !assert.True(t, errors.As(err, &perr), "%v", err) {
+				return
+			}
+			assert.Equal(t, tt.input, perr.Input)
+			assert.True(t, errors.Is(err, tt.err), "%v", err)
+			assert.Equal(t, tt.offset, perr.Offset)
+			assert.Equal(t, tt.token, perr.Token)
+			assert.Equal(t, tt.suggestion, perr.Suggestion)
+			if tt.suggestion != "" {
+				_, err := Parse(tt.suggestion)
+				assert.NoError(t, err, "the suggestion parses")
+			}
+		})
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"10XB", `invalid size "10XB": unknown unit "XB" at offset 2`},
+		{"10MIB", `invalid size "10MIB": unknown unit "MIB" at offset 2, did you mean "10MiB"`},
+		{"1 MB", `invalid size "1 MB": invalid syntax " MB" at offset 1, did you mean "1MB"`},
+		{"8192PB", `invalid size "8192PB": size out of range`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := Parse(tt.input)
+			assert.EqualError(t, err, tt.want)
+		})
+	}
+}
+
+func TestParseErrorCompound(t *testing.T) {
+	p := NewParser(AllowCompound(), AllowSpaces())
+	tests := []struct {
+		input      string
+		err        error
+		offset     int
+		token      string
+		suggestion string
+	}{
+		{"1GB 512XB", ErrUnknownUnit, 7, "XB", ""},
+		{"1GB 5 MIB", ErrUnknownUnit, 6, "MIB", "1GB 5MiB"},
+		{"1GB 5", ErrSyntax, 5, "", ""},
+		{"GB", ErrSyntax, 0, "", ""},
+		{"8000PB 8000PB", ErrOutOfRange, 0, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := p.Parse(tt.input)
+			var perr *ParseError
+			if !assert.True(t, errors.As(err, &perr), "%v", err) {
+				return
+			}
+			assert.Equal(t, tt.input, perr.Input)
+			assert.True(t, errors.Is(err, tt.err), "%v", err)
+			assert.Equal(t, tt.offset, perr.Offset)
+			assert.Equal(t, tt.token, perr.Token)
+			assert.Equal(t, tt.suggestion, perr.Suggestion)
+		})
+	}
+}
+
+func TestParseRateError(t *testing.T) {
+	_, err := ParseRate("10XB/s")
+	var perr *ParseError
+	assert.True(t, errors.As(err, &perr))
+	assert.True(t, errors.Is(err, ErrUnknownUnit))
+	assert.Equal(t, 2, perr.Offset)
+}
@@ -0,0 +1,323 @@
+package bytesizer
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParseOption configures a Parser.
+type ParseOption func(*parseConfig)
+
+type parseConfig struct {
+	compound  bool
+	spaces    bool
+	sentinels bool
+	digits    bool
+}
+
+// AllowCompound accepts sizes made of several parts, such as "1GB512MB" or
+// "1GB 512MB", which add up. Like time.ParseDuration, a leading sign applies
+// to the whole size, and every part but a single one needs a unit.
+func AllowCompound() ParseOption {
+	return func(c *parseConfig) {
+		c.compound = true
+	}
+}
+
+// AllowSpaces accepts spaces around a size and between its number and unit,
+// such as " 1.5 GB". Any Unicode space counts, for text copied from web
+// pages: no-break spaces (U+00A0), narrow and thin spaces (U+202F, U+2009)
+// and zero-width spaces (U+200B) among others. Directional formatting
+// characters count too, so sizes formatted WithBidi parse back. Spaces
+// inside the number, such as thousands separators, are still an error.
+func AllowSpaces() ParseOption {
+	return func(c *parseConfig) {
+		c.spaces = true
+	}
+}
+
+// AllowSentinels accepts the sentinels: "unlimited" in any case and the
+// "-1" of configuration files are Unlimited, and "–" as String formats it,
+// an empty string or "-" are Unknown. Parse rejects them all.
+func AllowSentinels() ParseOption {
+	return func(c *parseConfig) {
+		c.sentinels = true
+	}
+}
+
+// AllowUnicodeDigits accepts the digits of other scripts and the
+// full-width forms of CJK input methods, for text pasted from localized
+// systems: "١٢٨MB" with Arabic-Indic digits and "１２８ＭＢ" are 128MB. The
+// Arabic decimal separator (U+066B) and the minus sign (U+2212) are
+// accepted too.
+func AllowUnicodeDigits() ParseOption {
+	return func(c *parseConfig) {
+		c.digits = true
+	}
+}
+
+// Parser parses sizes like Parse, with options for other inputs. Create one
+// with NewParser.
+//
+//	p := NewParser(AllowCompound())
+//	size, err := p.Parse("1GB 512MB")
+type Parser struct {
+	c parseConfig
+}
+
+// NewParser returns a Parser accepting what Parse accepts, and what opts
+// allow.
+func NewParser(opts ...ParseOption) Parser {
+	var p Parser
+	for _, opt := range opts {
+		opt(&p.c)
+	}
+	return p
+}
+
+// Parse parses s.
+func (p Parser) Parse(s string) (ByteSize, error) {
+	if p.c.digits {
+		s = normalizeDigits(s)
+	}
+	if p.c.spaces {
+		s = normalizeSpaces(s)
+	}
+	if p.c.sentinels {
+		switch {
+		case s == "-1" || len(s) == len(unlimitedName) && strings.EqualFold(s, unlimitedName):
+			return Unlimited, nil
+		case s == "" || s == "-" || s == unknownName:
+			return Unknown, nil
+		}
+	}
+	size, err := p.parse(s)
+	var perr *ParseError
+	if p.c.sentinels && errors.As(err, &perr) && perr.Suggestion == "" &&
+		len(s) < len(unlimitedName)+2 && editDistance(strings.ToLower(s), unlimitedName) <= 2 {
+		perr.Suggestion = unlimitedName
+	}
+	return size, err
+}
+
+// parse parses s, normalized, with the options other than AllowSentinels.
+func (p Parser) parse(s string) (ByteSize, error) {
+	if p.c.compound {
+		return parseCompound(s, p.c.spaces)
+	}
+	if i := strings.IndexByte(s, ' '); p.c.spaces && i >= 0 && isUnit(s[i+1:]) {
+		s = s[:i] + s[i+1:]
+	}
+	return Parse(s)
+}
+
+// isSpace reports whether r is a space for AllowSpaces.
+func isSpace(r rune) bool {
+	switch r {
+	case '\u200b', '\u2060', '\ufeff': // zero-width spaces
+		return true
+	case '\u200e', '\u200f', '\u061c', // directional marks
+		'\u202a', '\u202b', '\u202c', '\u202d', '\u202e', // embeddings and overrides
+		'\u2066', '\u2067', '\u2068', '\u2069': // isolates
+		return true
+	}
+	return unicode.IsSpace(r)
+}
+
+// normalizeSpaces trims the spaces around s, and turns every run of spaces
+// in it into one ASCII space.
+func normalizeSpaces(s string) string {
+	return strings.Join(strings.FieldsFunc(s, isSpace), " ")
+}
+
+// normalizeDigits returns s with the digits of every script turned into
+// ASCII digits, and full-width forms into ASCII, for AllowUnicodeDigits.
+func normalizeDigits(s string) string {
+	ascii := true
+	for i := 0; i < len(s) && ascii; i++ {
+		ascii = s[i] < utf8.RuneSelf
+	}
+	if ascii {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r < utf8.RuneSelf:
+			return r
+		case r >= '\uff01' && r <= '\uff5e': // full-width forms of ASCII
+			return r - 0xfee0
+		case r == '\u066b': // Arabic decimal separator
+			return '.'
+		case r == '\u2212': // minus sign
+			return '-'
+		case unicode.IsDigit(r):
+			return '0' + digitValue(r)
+		}
+		return r
+	}, s)
+}
+
+// digitValue returns the value of the decimal digit r. Unicode encodes
+// decimal digits in runs from 0 to 9, which may follow each other, as the
+// mathematical digits do.
+func digitValue(r rune) rune {
+	start := r
+	for unicode.IsDigit(start - 1) {
+		start--
+	}
+	return (r - start) % 10
+}
+
+// isUnit reports whether s is made of letters, as a unit is.
+func isUnit(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !isLetter(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseCompound parses a sequence of sizes, with an optional leading sign.
+// If spaces is set, a space may separate a number from its unit.
+func parseCompound(s string, spaces bool) (ByteSize, error) {
+	rest := s
+	neg := false
+	if rest != "" && (rest[0] == '-' || rest[0] == '+') {
+		neg = rest[0] == '-'
+		rest = rest[1:]
+	}
+
+	var total ByteSize
+	for first := true; first || rest != ""; first = false {
+		start := len(s) - len(rest)
+		n := numberEnd(rest)
+		num := rest[:n]
+		if spaces && n+1 < len(rest) && rest[n] == ' ' && isLetter(rest[n+1]) {
+			rest = rest[n+1:]
+		} else {
+			rest = rest[n:]
+		}
+		u := 0
+		for u < len(rest) && isLetter(rest[u]) {
+			u++
+		}
+		switch {
+		case n == 0:
+			return 0, &ParseError{Input: s, Offset: start, Err: errMissingNumber}
+		case u == 0 && !(first && rest == ""):
+			return 0, &ParseError{Input: s, Offset: start + n, Err: errMissingUnit}
+		}
+		part, err := parse(num + rest[:u])
+		if err != nil {
+			return 0, partError(s, start, len(s)-len(rest)+u, num+rest[:u], err)
+		}
+		if total > Unlimited-part {
+			return 0, &ParseError{Input: s, Err: ErrOutOfRange}
+		}
+		total += part
+
+		// Parts may be separated by spaces.
+		rest = rest[u:]
+		if sp := len(rest) - len(strings.TrimLeft(rest, " ")); sp > 0 {
+			if sp == len(rest) {
+				return 0, &ParseError{Input: s, Offset: len(s) - sp, Err: errTrailingSpace}
+			}
+			rest = rest[sp:]
+		}
+	}
+	if neg {
+		total = -total
+	}
+	return total, nil
+}
+
+// partError returns the ParseError for the part of s between start and end,
+// parsed as part and rejected with err, with offsets in s.
+func partError(s string, start, end int, part string, err error) *ParseError {
+	e := newParseError(part, err)
+	e.Input = s
+	if e.Offset > 0 && s[start:end] != part {
+		e.Offset++ // the space AllowSpaces dropped before the unit
+	}
+	e.Offset += start
+	if e.Suggestion != "" {
+		e.Suggestion = s[:start] + e.Suggestion + s[end:]
+	}
+	return e
+}
+
+// SplitSize splits s into its number and unit as Parse reads them, such as
+// "-1.5e3" and "kB" for "-1.5e3kB", for programs with units of their own,
+// such as powers of 1000. The unit is "" after a bare number and is not
+// checked. It fails with a *ParseError wrapping ErrSyntax if s does not
+// start with a decimal number.
+func SplitSize(s string) (number, unit string, err error) {
+	i := 0
+	if i < len(s) && (s[i] == '+' || s[i] == '-') {
+		i++
+	}
+	n := i + numberEnd(s[i:])
+	if !isDecimal(s[:n]) {
+		return "", "", newParseError(s, ErrSyntax)
+	}
+	return s[:n], s[n:], nil
+}
+
+// numberEnd returns the length of the unsigned decimal number at the start
+// of s.
+func numberEnd(s string) int {
+	i := 0
+	for i < len(s) && (isDigit(s[i]) || s[i] == '.') {
+		i++
+	}
+	if i > 0 && i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if j < len(s) && isDigit(s[j]) {
+			for j < len(s) && isDigit(s[j]) {
+				j++
+			}
+			i = j
+		}
+	}
+	return i
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// isDecimal reports whether s is a decimal number with an optional sign,
+// fraction and exponent, such as "-1.5e3". It rejects the other forms
+// strconv.ParseFloat accepts, such as hexadecimal and underscores.
+func isDecimal(s string) bool {
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		s = s[1:]
+	}
+	n := numberEnd(s)
+	if n != len(s) {
+		return false
+	}
+	// One point at most, and a digit at least before any exponent.
+	digits, points := 0, 0
+	for i := 0; i < n && s[i] != 'e' && s[i] != 'E'; i++ {
+		if s[i] == '.' {
+			points++
+		} else {
+			digits++
+		}
+	}
+	return digits > 0 && points <= 1
+}
@@ -0,0 +1,266 @@
+package bytesizer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestParseStrict checks that Parse only accepts a whole, single size.
+func TestParseStrict(t *testing.T) {
+	for _, s := range []string{
+		"10KBfoo", "10KB foo", "10 KB", " 10KB", "10KB ", "10KB\n", "1KBKB",
+		"1GB512MB", "1GB 512MB", "0x1p10KB", "0x10", "1_000KB", "1.5.5KB",
+		"--1KB", "+-1KB", "1e", "1e+KB", "1eKB", ".KB", ".", "..5", "1..5KB",
+		"KB", "-", "+", "e3", "1KB\x00", "\x001KB", "1 e3", "NaN", "Inf",
+		"1 KB", "１KB",
+	} {
+		t.Run(s, func(t *testing.T) {
+			_, err := Parse(s)
+			assert.Error(t, err)
+		})
+	}
+
+	for s, want := range map[string]ByteSize{
+		"1e3":    1000,
+		"1E3B":   1000,
+		"1e+3KB": 1000 * KB,
+		".5KB":   512,
+		"5.KB":   5 * KB,
+		"-1.5KB": -1536,
+		"+2MB":   2 * MB,
+	} {
+		t.Run(s, func(t *testing.T) {
+			got, err := Parse(s)
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestParserCompound(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ByteSize
+		err  bool
+	}{
+		{"1GB512MB", GB + 512*MB, false},
+		{"1GB 512MB", GB + 512*MB, false},
+		{"1.5GB 256KB", 3*GB/2 + 256*KB, false},
+		{"-1GB512MB", -(GB + 512*MB), false},
+		{"+1KB1B", KB + 1, false},
+		{"1GB 1GB", 2 * GB, false},
+		{"1KiB 1B", KB + 1, false},
+		{"10MB", 10 * MB, false},
+		{"1024", KB, false},
+		{"1GB512", 0, true},
+		{"1GB -512MB", 0, true},
+		{"1GB x", 0, true},
+		{"1GB ", 0, true},
+		{"GB", 0, true},
+		{"", 0, true},
+		{"-", 0, true},
+		{"1GB  512MB", GB + 512*MB, false},
+		{"1XB 2MB", 0, true},
+		{"0x1p3KB", 0, true},
+		{"8191PB 1PB", 0, true},
+	}
+
+	p := NewParser(AllowCompound())
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := p.Parse(tt.in)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := p.Parse("8191PB 1PB")
+	assert.True(t, errors.Is(err, ErrOutOfRange))
+
+	// Without the option, a Parser is Parse.
+	_, err = NewParser().Parse("1GB512MB")
+	assert.Error(t, err)
+}
+
+func TestParserAllowSpaces(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ByteSize
+		err  bool
+	}{
+		{"1.5 GB", 3 * GB / 2, false},
+		{"1.5\u00a0GB", 3 * GB / 2, false},
+		{"1.5\u202fGB", 3 * GB / 2, false},
+		{"1.5\u2009GiB", 3 * GB / 2, false},
+		{"1.5\u200bGB", 3 * GB / 2, false},
+		{"\u00a0 10KB\t\n", 10 * KB, false},
+		{"\ufeff10 KB", 10 * KB, false},
+		{"10 \u00a0 KB", 10 * KB, false},
+		{"-2 MB", -2 * MB, false},
+		{"1024", KB, false},
+		{" 1024 ", KB, false},
+		{"1\u00a0024 KB", 0, true}, // thousands separator
+		{"1 e3", 0, true},
+		{"1 0KB", 0, true},
+		{"10 KB foo", 0, true},
+		{"10 KB 5", 0, true},
+		{"1GB 512MB", 0, true},
+		{"- 2MB", 0, true},
+		{"\u00a0", 0, true},
+		{"", 0, true},
+	}
+
+	p := NewParser(AllowSpaces())
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := p.Parse(tt.in)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			_, err = Parse(tt.in)
+			if tt.in != "1024" {
+				assert.Error(t, err, "Parse is strict")
+			}
+		})
+	}
+
+	compound := NewParser(AllowSpaces(), AllowCompound())
+	got, err := compound.Parse("1 GB 512 MB ")
+	assert.NoError(t, err)
+	assert.Equal(t, GB+512*MB, got)
+	_, err = compound.Parse("1 2MB")
+	assert.Error(t, err)
+}
+
+func TestParserAllowSentinels(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ByteSize
+	}{
+		{"-1", Unlimited},
+		{"", Unknown},
+		{"-", Unknown},
+		{"unlimited", Unlimited},
+		{"UNLIMITED", Unlimited},
+		{"Unlimited", Unlimited},
+		{"\u2013", Unknown},
+		{"-2", -2},
+		{"1GB", GB},
+	}
+
+	p := NewParser(AllowSentinels())
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := p.Parse(tt.in)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	got, err := NewParser(AllowSentinels(), AllowSpaces()).Parse(" \u00a0")
+	assert.NoError(t, err)
+	assert.Equal(t, Unknown, got)
+
+	_, err = p.Parse("unlimted")
+	var perr *ParseError
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, "unlimited", perr.Suggestion)
+	_, err = Parse("unlimted")
+	assert.True(t, errors.As(err, &perr))
+	assert.Empty(t, perr.Suggestion, "Parse does not accept the suggestion")
+}
+
+func TestParserAllowUnicodeDigits(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ByteSize
+		err  bool
+	}{
+		{"１２８ＭＢ", 128 * MB, false},
+		{"１．５ＧｉＢ", 3 * GB / 2, false},
+		{"－２ＫＢ", -2 * KB, false},
+		{"١٢٨MB", 128 * MB, false},                 // Arabic-Indic
+		{"١٫٥GB", 3 * GB / 2, false},               // Arabic decimal separator
+		{"۱۲۸KB", 128 * KB, false},                 // Extended Arabic-Indic
+		{"१०२४", KB, false},                        // Devanagari
+		{"১০KB", 10 * KB, false},                   // Bengali
+		{"−1KB", -KB, false},                       // minus sign
+		{"\U0001d7d9\U0001d7ceKB", 10 * KB, false}, // mathematical double-struck
+		{"128MB", 128 * MB, false},
+		{"１２８ ＭＢ", 0, true},
+		{"一二八MB", 0, true}, // numerals, not digits
+		{"１２８ＸＢ", 0, true},
+		{"１２８ｍｂ", 0, true}, // as "128mb"
+	}
+
+	p := NewParser(AllowUnicodeDigits())
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := p.Parse(tt.in)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	got, err := NewParser(AllowUnicodeDigits(), AllowSpaces()).Parse("１２８　ＭＢ")
+	assert.NoError(t, err)
+	assert.Equal(t, 128*MB, got)
+
+	_, err = Parse("１２８ＭＢ")
+	var perr *ParseError
+	assert.True(t, errors.As(err, &perr), "Parse is strict")
+	assert.Equal(t, "128MB", perr.Suggestion)
+}
+
+func TestSplitSize(t *testing.T) {
+	tests := []struct {
+		in        string
+		num, unit string
+		err       bool
+	}{
+		{"10KB", "10", "KB", false},
+		{"1.5e3kB", "1.5e3", "kB", false},
+		{"-2Mb", "-2", "Mb", false},
+		{"+.5GiB", "+.5", "GiB", false},
+		{"4096", "4096", "", false},
+		{"1e3", "1e3", "", false},
+		{"10XB", "10", "XB", false},
+		{"1eKB", "1", "eKB", false},
+		{"KB", "", "", true},
+		{"1.2.3KB", "", "", true},
+		{"unlimited", "", "", true},
+		{"", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			num, unit, err := SplitSize(tt.in)
+			if tt.err {
+				assert.ErrorIs(t, err, ErrSyntax)
+				var perr *ParseError
+				if assert.True(t, errors.As(err, &perr)) {
+					assert.False(t, perr.Suggestion != "" && strings.EqualFold(perr.Suggestion, tt.in), "suggests the input")
+				}
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.num, num)
+			assert.Equal(t, tt.unit, unit)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package bytesizer
+
+import "fmt"
+
+// Part size limits of Amazon S3 multipart uploads, also used by most
+// S3-compatible object stores.
+const (
+	S3MinPartSize ByteSize = 5 * MB
+	S3MaxPartSize ByteSize = 5 * GB
+	S3MaxParts             = 10000
+)
+
+// PartPlan splits an upload of total bytes into parts for a multipart
+// upload. All parts but the last have the same size, the smallest size of
+// at least minPart that needs no more than maxParts parts; the last part
+// holds the rest and may be smaller than minPart. An empty upload is a
+// single empty part.
+//
+// PartPlan returns an error if total does not fit in maxParts parts of
+// maxPart bytes:
+//
+//	parts, err := PartPlan(size, S3MinPartSize, S3MaxPartSize, S3MaxParts)
+func PartPlan(total ByteSize, minPart, maxPart ByteSize, maxParts int) ([]ByteSize, error) {
+	switch {
+	case total < 0:
+		return nil, fmt.Errorf("invalid total size %s", total)
+	case minPart < 1 || maxPart < minPart:
+		return nil, fmt.Errorf("invalid part size range %s to %s", minPart, maxPart)
+	case maxParts < 1:
+		return nil, fmt.Errorf("invalid maximum number of parts %d", maxParts)
+	}
+	if total == 0 {
+		return []ByteSize{0}, nil
+	}
+
+	part := (total-1)/ByteSize(maxParts) + 1
+	if part < minPart {
+		part = minPart
+	}
+	if part > maxPart {
+		return nil, fmt.Errorf("%s does not fit in %d parts of %s", total, maxParts, maxPart)
+	}
+
+	n := (total-1)/part + 1
+	parts := make([]ByteSize, n)
+	for i := range parts {
+		parts[i] = part
+	}
+	parts[n-1] = total - (n-1)*part
+	return parts, nil
+}
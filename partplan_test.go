@@ -0,0 +1,63 @@
+package bytesizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartPlan(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     ByteSize
+		minPart   ByteSize
+		maxPart   ByteSize
+		maxParts  int
+		expectErr bool
+		expected  []ByteSize
+	}{
+		{"Empty", 0, 5, 10, 3, false, []ByteSize{0}},
+		{"Single small part", 3, 5, 10, 3, false, []ByteSize{3}},
+		{"Exact parts", 10, 5, 10, 3, false, []ByteSize{5, 5}},
+		{"Short last part", 12, 5, 10, 3, false, []ByteSize{5, 5, 2}},
+		{"Grows past minimum", 20, 5, 10, 3, false, []ByteSize{7, 7, 6}},
+		{"Maximum", 30, 5, 10, 3, false, []ByteSize{10, 10, 10}},
+		{"Too large", 31, 5, 10, 3, true, nil},
+		{"Negative total", -1, 5, 10, 3, true, nil},
+		{"Invalid range", 10, 10, 5, 3, true, nil},
+		{"Zero minimum", 10, 0, 5, 3, true, nil},
+		{"No parts", 10, 5, 10, 0, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := PartPlan(tt.total, tt.minPart, tt.maxPart, tt.maxParts)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, parts)
+			}
+		})
+	}
+}
+
+func TestPartPlanS3(t *testing.T) {
+	parts, err := PartPlan(100*MB, S3MinPartSize, S3MaxPartSize, S3MaxParts)
+	assert.NoError(t, err)
+	assert.Len(t, parts, 20)
+
+	parts, err = PartPlan(TB, S3MinPartSize, S3MaxPartSize, S3MaxParts)
+	assert.NoError(t, err)
+	assert.Len(t, parts, S3MaxParts)
+	var sum ByteSize
+	for _, p := range parts {
+		sum += p
+	}
+	assert.Equal(t, TB, sum)
+
+	_, err = PartPlan(50000*GB, S3MinPartSize, S3MaxPartSize, S3MaxParts)
+	assert.NoError(t, err)
+	_, err = PartPlan(50000*GB+1, S3MinPartSize, S3MaxPartSize, S3MaxParts)
+	assert.Error(t, err)
+}
@@ -0,0 +1,156 @@
+package bytesizer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// MemInfo holds the system memory figures of /proc/meminfo on Linux.
+type MemInfo struct {
+	MemTotal     ByteSize
+	MemFree      ByteSize
+	MemAvailable ByteSize
+	Buffers      ByteSize
+	Cached       ByteSize
+	SwapCached   ByteSize
+	Active       ByteSize
+	Inactive     ByteSize
+	SwapTotal    ByteSize
+	SwapFree     ByteSize
+	Dirty        ByteSize
+	Shmem        ByteSize
+	Slab         ByteSize
+
+	// Fields holds every size in the file by name, including those
+	// without a struct field above.
+	Fields map[string]ByteSize
+}
+
+// ReadMemInfo reads /proc/meminfo. It only works on Linux.
+func ReadMemInfo() (MemInfo, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return MemInfo{}, err
+	}
+	defer f.Close()
+	return ParseMemInfo(f)
+}
+
+// ParseMemInfo parses data in the format of /proc/meminfo.
+func ParseMemInfo(r io.Reader) (MemInfo, error) {
+	var m MemInfo
+	fields, err := parseProcSizes(r, map[string]*ByteSize{
+		"MemTotal":     &m.MemTotal,
+		"MemFree":      &m.MemFree,
+		"MemAvailable": &m.MemAvailable,
+		"Buffers":      &m.Buffers,
+		"Cached":       &m.Cached,
+		"SwapCached":   &m.SwapCached,
+		"Active":       &m.Active,
+		"Inactive":     &m.Inactive,
+		"SwapTotal":    &m.SwapTotal,
+		"SwapFree":     &m.SwapFree,
+		"Dirty":        &m.Dirty,
+		"Shmem":        &m.Shmem,
+		"Slab":         &m.Slab,
+	})
+	m.Fields = fields
+	return m, err
+}
+
+// ProcStatus holds the memory figures of /proc/<pid>/status on Linux.
+type ProcStatus struct {
+	VmPeak   ByteSize // peak virtual memory size
+	VmSize   ByteSize // virtual memory size
+	VmHWM    ByteSize // peak resident set size
+	VmRSS    ByteSize // resident set size
+	VmData   ByteSize // size of data segments
+	VmStk    ByteSize // size of the main stack
+	VmExe    ByteSize // size of text segments
+	VmLib    ByteSize // size of shared library code
+	VmSwap   ByteSize // swapped-out anonymous memory
+	RssAnon  ByteSize // resident anonymous memory
+	RssFile  ByteSize // resident file mappings
+	RssShmem ByteSize // resident shared memory
+
+	// Fields holds every size in the file by name, including those
+	// without a struct field above.
+	Fields map[string]ByteSize
+}
+
+// ReadProcStatus reads /proc/<pid>/status, or /proc/self/status if pid is
+// zero. It only works on Linux.
+func ReadProcStatus(pid int) (ProcStatus, error) {
+	name := "/proc/self/status"
+	if pid != 0 {
+		name = "/proc/" + strconv.Itoa(pid) + "/status"
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return ProcStatus{}, err
+	}
+	defer f.Close()
+	return ParseProcStatus(f)
+}
+
+// ParseProcStatus parses data in the format of /proc/<pid>/status.
+// Lines that are not sizes, such as Name or Pid, are ignored.
+func ParseProcStatus(r io.Reader) (ProcStatus, error) {
+	var s ProcStatus
+	fields, err := parseProcSizes(r, map[string]*ByteSize{
+		"VmPeak":   &s.VmPeak,
+		"VmSize":   &s.VmSize,
+		"VmHWM":    &s.VmHWM,
+		"VmRSS":    &s.VmRSS,
+		"VmData":   &s.VmData,
+		"VmStk":    &s.VmStk,
+		"VmExe":    &s.VmExe,
+		"VmLib":    &s.VmLib,
+		"VmSwap":   &s.VmSwap,
+		"RssAnon":  &s.RssAnon,
+		"RssFile":  &s.RssFile,
+		"RssShmem": &s.RssShmem,
+	})
+	s.Fields = fields
+	return s, err
+}
+
+// parseProcSizes parses "Name:   1234 kB" lines, storing known names in
+// dst and returning all sizes by name. In /proc, kB means 1024 bytes.
+// Lines without a kB suffix are not sizes and are skipped.
+func parseProcSizes(r io.Reader, dst map[string]*ByteSize) (map[string]ByteSize, error) {
+	fields := make(map[string]ByteSize)
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		name, value, ok := strings.Cut(sc.Text(), ":")
+		if !ok {
+			continue
+		}
+		num, ok := trimSuffix(strings.TrimSpace(value), " kB")
+		if !ok {
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(num), 10, 64)
+		if err != nil {
+			return fields, fmt.Errorf("invalid size for %s: %w", name, err)
+		}
+		size := ByteSize(n) * KB
+		fields[name] = size
+		if p, ok := dst[name]; ok {
+			*p = size
+		}
+	}
+	return fields, sc.Err()
+}
+
+// trimSuffix returns s without suffix, and whether s ended with it.
+func trimSuffix(s, suffix string) (string, bool) {
+	if !strings.HasSuffix(s, suffix) {
+		return s, false
+	}
+	return s[:len(s)-len(suffix)], true
+}
@@ -0,0 +1,86 @@
+package bytesizer
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testMemInfo = `MemTotal:       16314032 kB
+MemFree:         1173868 kB
+MemAvailable:    9204504 kB
+Buffers:          503128 kB
+Cached:          7704940 kB
+SwapCached:         1024 kB
+Active:          8206412 kB
+Inactive:        5325296 kB
+SwapTotal:       2097148 kB
+SwapFree:        2096124 kB
+Dirty:               356 kB
+Shmem:            612320 kB
+Slab:             911536 kB
+HugePages_Total:       0
+Hugepagesize:       2048 kB
+`
+
+func TestParseMemInfo(t *testing.T) {
+	m, err := ParseMemInfo(strings.NewReader(testMemInfo))
+	assert.NoError(t, err)
+	assert.Equal(t, 16314032*KB, m.MemTotal)
+	assert.Equal(t, "15.56GB", m.MemTotal.String())
+	assert.Equal(t, 9204504*KB, m.MemAvailable)
+	assert.Equal(t, 356*KB, m.Dirty)
+	assert.Equal(t, 911536*KB, m.Slab)
+	assert.Equal(t, 2*MB, m.Fields["Hugepagesize"])
+	assert.NotContains(t, m.Fields, "HugePages_Total")
+	assert.Len(t, m.Fields, 14)
+}
+
+func TestParseMemInfoInvalid(t *testing.T) {
+	_, err := ParseMemInfo(strings.NewReader("MemTotal:       lots kB\n"))
+	assert.Error(t, err)
+}
+
+func TestParseProcStatus(t *testing.T) {
+	status := `Name:	bytesize
+State:	S (sleeping)
+Pid:	4242
+VmPeak:	  724060 kB
+VmSize:	  722012 kB
+VmHWM:	   22036 kB
+VmRSS:	   21908 kB
+RssAnon:	   10240 kB
+RssFile:	   11668 kB
+RssShmem:	       0 kB
+VmSwap:	       0 kB
+Threads:	9
+`
+	s, err := ParseProcStatus(strings.NewReader(status))
+	assert.NoError(t, err)
+	assert.Equal(t, 21908*KB, s.VmRSS)
+	assert.Equal(t, 22036*KB, s.VmHWM)
+	assert.Equal(t, 10*MB, s.RssAnon)
+	assert.Equal(t, ByteSize(0), s.VmSwap)
+	assert.NotContains(t, s.Fields, "Threads")
+}
+
+func TestReadProcMem(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("/proc is Linux only")
+	}
+
+	m, err := ReadMemInfo()
+	assert.NoError(t, err)
+	assert.Greater(t, m.MemTotal, ByteSize(0))
+
+	s, err := ReadProcStatus(0)
+	assert.NoError(t, err)
+	assert.Greater(t, s.VmRSS, ByteSize(0))
+
+	byPid, err := ReadProcStatus(os.Getpid())
+	assert.NoError(t, err)
+	assert.Greater(t, byPid.VmRSS, ByteSize(0))
+}
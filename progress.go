@@ -0,0 +1,94 @@
+package bytesizer
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Progress is a snapshot of a transfer reported by a ProgressReader.
+type Progress struct {
+	Done    ByteSize      // bytes read so far
+	Total   ByteSize      // expected total, zero if unknown
+	Percent float64       // Done as a percentage of Total, zero if Total is unknown
+	Rate    Rate          // rate since the previous report
+	Elapsed time.Duration // time since the first Read
+	ETA     time.Duration // estimated time left at Rate, zero if unknown
+}
+
+// String formats p for humans, e.g. "1.5MB / 10MB (15%) at 2MB/s, ETA 4s".
+func (p Progress) String() string {
+	if p.Total <= 0 {
+		return fmt.Sprintf("%s at %s", p.Done, p.Rate)
+	}
+	s := fmt.Sprintf("%s / %s (%.0f%%) at %s", p.Done, p.Total, p.Percent, p.Rate)
+	if p.ETA > 0 {
+		s += ", ETA " + p.ETA.Round(time.Second).String()
+	}
+	return s
+}
+
+// ProgressReader is an io.Reader that reports the progress of reading from an
+// underlying reader of known total size to a callback.
+type ProgressReader struct {
+	r        io.Reader
+	total    ByteSize
+	interval time.Duration
+	fn       func(Progress)
+
+	done       ByteSize
+	start      time.Time
+	lastReport time.Time
+	lastDone   ByteSize
+	eof        bool
+}
+
+// NewProgressReader returns a ProgressReader reading from r, which is
+// expected to hold total bytes (zero if unknown). fn is called from Read at
+// most once per interval, and once more when r reaches EOF.
+func NewProgressReader(r io.Reader, total ByteSize, interval time.Duration, fn func(Progress)) *ProgressReader {
+	return &ProgressReader{r: r, total: total, interval: interval, fn: fn}
+}
+
+// Read reads from the underlying reader and reports progress when due.
+func (p *ProgressReader) Read(b []byte) (int, error) {
+	if p.start.IsZero() {
+		p.start = time.Now()
+		p.lastReport = p.start
+	}
+
+	n, err := p.r.Read(b)
+	if n == 0 && err == io.EOF && p.eof {
+		return n, err
+	}
+	p.done += ByteSize(n)
+	p.eof = err == io.EOF
+
+	if now := time.Now(); p.eof || now.Sub(p.lastReport) >= p.interval {
+		p.report(now)
+	}
+	return n, err
+}
+
+// Done returns the number of bytes read so far.
+func (p *ProgressReader) Done() ByteSize {
+	return p.done
+}
+
+func (p *ProgressReader) report(now time.Time) {
+	progress := Progress{
+		Done:    p.done,
+		Total:   p.total,
+		Elapsed: now.Sub(p.start),
+	}
+	progress.Rate = RateFor(p.done-p.lastDone, now.Sub(p.lastReport))
+	if p.total > 0 {
+		progress.Percent = float64(p.done) / float64(p.total) * 100
+		if progress.Rate > 0 {
+			progress.ETA = (p.total - p.done).TransferTime(progress.Rate)
+		}
+	}
+
+	p.lastReport, p.lastDone = now, p.done
+	p.fn(progress)
+}
@@ -0,0 +1,88 @@
+package bytesizer
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressReader(t *testing.T) {
+	var reports []Progress
+	r := NewProgressReader(strings.NewReader(strings.Repeat("x", int(10*KB))), 10*KB, 0, func(p Progress) {
+		reports = append(reports, p)
+	})
+
+	buf := make([]byte, KB)
+	for {
+		if _, err := r.Read(buf); err != nil {
+			assert.Equal(t, io.EOF, err)
+			break
+		}
+	}
+
+	assert.Equal(t, 10*KB, r.Done())
+	assert.NotEmpty(t, reports)
+	assert.Equal(t, KB, reports[0].Done)
+	assert.Equal(t, 10.0, reports[0].Percent)
+
+	last := reports[len(reports)-1]
+	assert.Equal(t, 10*KB, last.Done)
+	assert.Equal(t, 10*KB, last.Total)
+	assert.Equal(t, 100.0, last.Percent)
+	assert.Equal(t, time.Duration(0), last.ETA)
+}
+
+func TestProgressReaderInterval(t *testing.T) {
+	var reports int
+	r := NewProgressReader(strings.NewReader(strings.Repeat("x", int(100*KB))), 100*KB, time.Hour, func(p Progress) {
+		reports++
+	})
+	_, err := io.Copy(io.Discard, r)
+	assert.NoError(t, err)
+	// Only the final report at EOF.
+	assert.Equal(t, 1, reports)
+
+	_, err = r.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 1, reports)
+}
+
+func TestProgressReaderETA(t *testing.T) {
+	var reports []Progress
+	src := ThrottleReader(strings.NewReader(strings.Repeat("x", int(40*KB))), 100*KBps)
+	r := NewProgressReader(src, 40*KB, 50*time.Millisecond, func(p Progress) {
+		reports = append(reports, p)
+	})
+	_, err := io.Copy(io.Discard, r)
+	assert.NoError(t, err)
+
+	var sawETA bool
+	for _, p := range reports[:len(reports)-1] {
+		if p.ETA > 0 {
+			sawETA = true
+			assert.Greater(t, float64(p.Rate), 0.0)
+		}
+	}
+	assert.True(t, sawETA)
+}
+
+func TestProgressString(t *testing.T) {
+	tests := []struct {
+		name     string
+		progress Progress
+		expected string
+	}{
+		{"Known total", Progress{Done: 1536 * KB, Total: 10 * MB, Percent: 15, Rate: 2 * MBps, ETA: 4250 * time.Millisecond}, "1.5MB / 10MB (15%) at 2MB/s, ETA 4s"},
+		{"Finished", Progress{Done: 10 * MB, Total: 10 * MB, Percent: 100, Rate: 2 * MBps}, "10MB / 10MB (100%) at 2MB/s"},
+		{"Unknown total", Progress{Done: 1536 * KB, Rate: 2 * MBps}, "1.5MB at 2MB/s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.progress.String())
+		})
+	}
+}
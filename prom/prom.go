@@ -0,0 +1,68 @@
+// Package prom exports bytesizer values to Prometheus.
+//
+// Prometheus convention is to report sizes in base units, so every helper
+// here converts ByteSize values to a plain number of bytes; metric names
+// should carry the "_bytes" suffix.
+package prom
+
+import (
+	"math"
+
+	"github.com/iamlongalong/bytesizer"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// NewGaugeFunc creates a GaugeFunc that reports the size returned by f in bytes.
+//
+//	prometheus.MustRegister(prom.NewGaugeFunc(prometheus.GaugeOpts{
+//		Name: "cache_size_bytes",
+//		Help: "Current size of the cache.",
+//	}, cache.Size))
+func NewGaugeFunc(opts prometheus.GaugeOpts, f func() bytesizer.ByteSize) prometheus.GaugeFunc {
+	return prometheus.NewGaugeFunc(opts, func() float64 {
+		return f().Byte()
+	})
+}
+
+// SetGauge sets g to size in bytes.
+func SetGauge(g prometheus.Gauge, size bytesizer.ByteSize) {
+	g.Set(size.Byte())
+}
+
+// Observe records size in bytes on o, typically a Histogram or Summary.
+func Observe(o prometheus.Observer, size bytesizer.ByteSize) {
+	o.Observe(size.Byte())
+}
+
+// SizeBuckets returns histogram buckets in bytes, starting at min and
+// multiplying by factor until max is reached. max is always the last bucket.
+// Bucket boundaries are rounded to whole bytes.
+//
+//	prom.SizeBuckets(bytesizer.KB, bytesizer.GB, 4) // 1KB, 4KB, 16KB, ..., 256MB, 1GB
+//
+// Like prometheus.ExponentialBuckets, it panics if min is not positive,
+// max is less than min, or factor is not greater than 1.
+func SizeBuckets(min, max bytesizer.ByteSize, factor float64) []float64 {
+	if min <= 0 {
+		panic("prom.SizeBuckets needs a positive min")
+	}
+	if max < min {
+		panic("prom.SizeBuckets needs max >= min")
+	}
+	if factor <= 1 {
+		panic("prom.SizeBuckets needs a factor greater than 1")
+	}
+
+	var buckets []float64
+	for b := min.Byte(); b < max.Byte(); b *= factor {
+		rounded := math.Round(b)
+		if rounded >= max.Byte() {
+			break
+		}
+		if n := len(buckets); n > 0 && buckets[n-1] == rounded {
+			continue
+		}
+		buckets = append(buckets, rounded)
+	}
+	return append(buckets, max.Byte())
+}
@@ -0,0 +1,80 @@
+package prom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/iamlongalong/bytesizer"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGaugeFunc(t *testing.T) {
+	size := 1536 * bytesizer.KB
+	g := NewGaugeFunc(prometheus.GaugeOpts{Name: "cache_size_bytes", Help: "Cache size."}, func() bytesizer.ByteSize {
+		return size
+	})
+
+	assert.Equal(t, float64(1572864), testutil.ToFloat64(g))
+
+	expected := `
+# HELP cache_size_bytes Cache size.
+# TYPE cache_size_bytes gauge
+cache_size_bytes 1.572864e+06
+`
+	assert.NoError(t, testutil.CollectAndCompare(g, strings.NewReader(expected)))
+}
+
+func TestSetGaugeAndObserve(t *testing.T) {
+	g := prometheus.NewGauge(prometheus.GaugeOpts{Name: "queue_bytes"})
+	SetGauge(g, 2*bytesizer.MB)
+	assert.Equal(t, float64(2*bytesizer.MB), testutil.ToFloat64(g))
+
+	h := prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "request_size_bytes",
+		Help:    "Request size.",
+		Buckets: SizeBuckets(bytesizer.KB, bytesizer.MB, 32),
+	})
+	Observe(h, 10*bytesizer.KB)
+	Observe(h, 2*bytesizer.MB)
+
+	expected := `
+# HELP request_size_bytes Request size.
+# TYPE request_size_bytes histogram
+request_size_bytes_bucket{le="1024"} 0
+request_size_bytes_bucket{le="32768"} 1
+request_size_bytes_bucket{le="1.048576e+06"} 1
+request_size_bytes_bucket{le="+Inf"} 2
+request_size_bytes_sum 2.107392e+06
+request_size_bytes_count 2
+`
+	assert.NoError(t, testutil.CollectAndCompare(h, strings.NewReader(expected)))
+}
+
+func TestSizeBuckets(t *testing.T) {
+	tests := []struct {
+		name     string
+		min, max bytesizer.ByteSize
+		factor   float64
+		expected []float64
+	}{
+		{"Exact", bytesizer.KB, bytesizer.MB, 4, []float64{1024, 4096, 16384, 65536, 262144, 1048576}},
+		{"Max appended", bytesizer.KB, 10 * bytesizer.KB, 4, []float64{1024, 4096, 10240}},
+		{"Rounded", 10, 20, 1.5, []float64{10, 15, 20}},
+		{"Single", bytesizer.KB, bytesizer.KB, 2, []float64{1024}},
+		{"Small factor", 1, 3, 1.1, []float64{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SizeBuckets(tt.min, tt.max, tt.factor))
+		})
+	}
+}
+
+func TestSizeBucketsPanics(t *testing.T) {
+	assert.Panics(t, func() { SizeBuckets(0, bytesizer.MB, 2) })
+	assert.Panics(t, func() { SizeBuckets(bytesizer.MB, bytesizer.KB, 2) })
+	assert.Panics(t, func() { SizeBuckets(bytesizer.KB, bytesizer.MB, 1) })
+}
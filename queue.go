@@ -0,0 +1,203 @@
+package bytesizer
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrQueueFull is returned by Queue.Push when an item does not fit.
+	ErrQueueFull = errors.New("queue full")
+	// ErrQueueClosed is returned by Queue.Push after Close, and by Queue.Pop
+	// once a closed queue is empty.
+	ErrQueueClosed = errors.New("queue closed")
+)
+
+// OverflowPolicy sets what Queue.Push does when an item does not fit.
+type OverflowPolicy int
+
+const (
+	// BlockOnFull waits for items to be popped. This is the default.
+	BlockOnFull OverflowPolicy = iota
+	// RejectOnFull fails with ErrQueueFull.
+	RejectOnFull
+	// DropOldest drops the oldest items until the new one fits.
+	DropOldest
+)
+
+type queueItem[T any] struct {
+	v    T
+	size ByteSize
+}
+
+// Queue is a FIFO queue whose capacity is a number of bytes rather than of
+// items, for buffering messages where memory is the constraint. The size of
+// each item is given by a cost function. It is safe for concurrent use.
+//
+//	q := NewQueue(64*MB, func(m []byte) ByteSize { return ByteSize(len(m)) }, BlockOnFull)
+//	go func() {
+//		for {
+//			msg, err := q.Pop(ctx)
+//			if err != nil {
+//				return
+//			}
+//			send(msg)
+//		}
+//	}()
+//	err := q.Push(ctx, msg)
+type Queue[T any] struct {
+	capacity ByteSize
+	cost     func(T) ByteSize
+	policy   OverflowPolicy
+
+	mu      sync.Mutex
+	items   []queueItem[T]
+	size    ByteSize
+	dropped int
+	closed  bool
+	changed chan struct{} // closed when items are pushed or popped
+}
+
+// NewQueue returns a Queue holding up to capacity bytes, as measured by
+// cost, with policy for items that do not fit.
+func NewQueue[T any](capacity ByteSize, cost func(T) ByteSize, policy OverflowPolicy) *Queue[T] {
+	return &Queue[T]{capacity: capacity, cost: cost, policy: policy}
+}
+
+// Push adds v to the back of the queue. An item larger than the capacity
+// never fits and fails with ErrQueueFull; otherwise the queue's policy
+// applies. With BlockOnFull, Push returns ctx.Err() if ctx is done first.
+func (q *Queue[T]) Push(ctx context.Context, v T) error {
+	size := q.cost(v)
+	if size > q.capacity {
+		return ErrQueueFull
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for {
+		if q.closed {
+			return ErrQueueClosed
+		}
+		if q.size+size <= q.capacity {
+			break
+		}
+		switch q.policy {
+		case RejectOnFull:
+			return ErrQueueFull
+		case DropOldest:
+			for q.size+size > q.capacity {
+				q.pop()
+				q.dropped++
+			}
+			continue
+		}
+		if err := q.wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	q.items = append(q.items, queueItem[T]{v: v, size: size})
+	q.size += size
+	q.notify()
+	return nil
+}
+
+// Pop removes and returns the item at the front of the queue, waiting for
+// one if it is empty. It fails with ErrQueueClosed once the queue is closed
+// and empty, or with ctx.Err() if ctx is done first.
+func (q *Queue[T]) Pop(ctx context.Context) (T, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) == 0 {
+		if q.closed {
+			var zero T
+			return zero, ErrQueueClosed
+		}
+		if err := q.wait(ctx); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+	v := q.pop()
+	q.notify()
+	return v, nil
+}
+
+// TryPop removes and returns the item at the front of the queue, if any.
+func (q *Queue[T]) TryPop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	v := q.pop()
+	q.notify()
+	return v, true
+}
+
+// Len returns the number of items in the queue.
+func (q *Queue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items)
+}
+
+// Size returns the total size of the items in the queue.
+func (q *Queue[T]) Size() ByteSize {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.size
+}
+
+// Dropped returns the number of items dropped by the DropOldest policy.
+func (q *Queue[T]) Dropped() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dropped
+}
+
+// Close stops the queue from accepting items. Items already queued can
+// still be popped, and waiting calls return.
+func (q *Queue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.notify()
+}
+
+// pop removes the front item. Call with q.mu held and the queue not empty.
+func (q *Queue[T]) pop() T {
+	it := q.items[0]
+	q.items[0] = queueItem[T]{} // let it be collected
+	q.items = q.items[1:]
+	q.size -= it.size
+	return it.v
+}
+
+// wait releases q.mu until the queue changes or ctx is done.
+func (q *Queue[T]) wait(ctx context.Context) error {
+	if q.changed == nil {
+		q.changed = make(chan struct{})
+	}
+	changed := q.changed
+	q.mu.Unlock()
+	defer q.mu.Lock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-changed:
+		return nil
+	}
+}
+
+// notify wakes up waiting calls. Call with q.mu held.
+func (q *Queue[T]) notify() {
+	if q.changed != nil {
+		close(q.changed)
+		q.changed = nil
+	}
+}
@@ -0,0 +1,128 @@
+package bytesizer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func stringCost(s string) ByteSize {
+	return ByteSize(len(s))
+}
+
+func TestQueue(t *testing.T) {
+	q := NewQueue(10, stringCost, RejectOnFull)
+	ctx := context.Background()
+
+	assert.NoError(t, q.Push(ctx, "hello"))
+	assert.NoError(t, q.Push(ctx, "abc"))
+	assert.ErrorIs(t, q.Push(ctx, "xyz"), ErrQueueFull)
+	assert.ErrorIs(t, q.Push(ctx, "eleven char"), ErrQueueFull)
+	assert.Equal(t, 2, q.Len())
+	assert.Equal(t, ByteSize(8), q.Size())
+
+	v, err := q.Pop(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", v)
+	v, ok := q.TryPop()
+	assert.True(t, ok)
+	assert.Equal(t, "abc", v)
+	_, ok = q.TryPop()
+	assert.False(t, ok)
+	assert.Equal(t, ByteSize(0), q.Size())
+
+	assert.NoError(t, q.Push(ctx, "x"))
+	q.Close()
+	assert.ErrorIs(t, q.Push(ctx, "y"), ErrQueueClosed)
+	v, err = q.Pop(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "x", v)
+	_, err = q.Pop(ctx)
+	assert.ErrorIs(t, err, ErrQueueClosed)
+}
+
+func TestQueueDropOldest(t *testing.T) {
+	q := NewQueue(10, stringCost, DropOldest)
+	ctx := context.Background()
+	for _, s := range []string{"aaa", "bbb", "ccc", "dddddd"} {
+		assert.NoError(t, q.Push(ctx, s))
+	}
+	assert.Equal(t, 2, q.Dropped())
+
+	var got []string
+	for {
+		v, ok := q.TryPop()
+		if !ok {
+			break
+		}
+		got = append(got, v)
+	}
+	assert.Equal(t, []string{"ccc", "dddddd"}, got)
+}
+
+func TestQueueBlockOnFull(t *testing.T) {
+	q := NewQueue(10, stringCost, BlockOnFull)
+	ctx := context.Background()
+	assert.NoError(t, q.Push(ctx, "0123456789"))
+
+	done := make(chan error)
+	go func() {
+		done <- q.Push(ctx, "abc")
+	}()
+	select {
+	case <-done:
+		t.Fatal("Push did not block")
+	case <-time.After(20 * time.Millisecond):
+	}
+	_, err := q.Pop(ctx)
+	assert.NoError(t, err)
+	assert.NoError(t, <-done)
+
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, q.Push(timeout, "0123456789"), context.DeadlineExceeded)
+
+	// Pop waits for an item, and Close wakes it up.
+	_, _ = q.Pop(ctx)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.Close()
+	}()
+	_, err = q.Pop(ctx)
+	assert.ErrorIs(t, err, ErrQueueClosed)
+}
+
+func TestQueueConcurrent(t *testing.T) {
+	q := NewQueue(KB, func(b []byte) ByteSize { return ByteSize(len(b)) }, BlockOnFull)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 250; j++ {
+				assert.NoError(t, q.Push(ctx, make([]byte, 100)))
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		q.Close()
+	}()
+
+	var total ByteSize
+	for {
+		b, err := q.Pop(ctx)
+		if err != nil {
+			assert.ErrorIs(t, err, ErrQueueClosed)
+			break
+		}
+		assert.LessOrEqual(t, q.Size(), KB)
+		total += ByteSize(len(b))
+	}
+	assert.Equal(t, ByteSize(4*250*100), total)
+}
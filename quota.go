@@ -0,0 +1,252 @@
+package bytesizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrQuotaExceeded is returned, wrapped, when a Quota cannot fit a request.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// QuotaSnapshot is the state of a Quota at one point in time.
+type QuotaSnapshot struct {
+	Limit    ByteSize
+	Soft     ByteSize // 0 without a soft limit
+	Used     ByteSize
+	Reserved ByteSize
+}
+
+// Total returns the used and reserved bytes.
+func (s QuotaSnapshot) Total() ByteSize {
+	return s.Used + s.Reserved
+}
+
+// Available returns the bytes that can still be reserved or used.
+func (s QuotaSnapshot) Available() ByteSize {
+	if s.Total() >= s.Limit {
+		return 0
+	}
+	return s.Limit - s.Total()
+}
+
+// QuotaOption configures a Quota.
+type QuotaOption func(*Quota)
+
+// WithSoftLimit sets a soft limit below the quota's limit. fn is called
+// when the used and reserved bytes cross it, with above reporting the
+// direction. Requests are not refused at the soft limit.
+func WithSoftLimit(soft ByteSize, fn func(s QuotaSnapshot, above bool)) QuotaOption {
+	return func(q *Quota) {
+		q.soft = soft
+		q.onSoft = fn
+	}
+}
+
+// WithExceededHandler sets a function called when a request of requested
+// bytes is refused, e.g. to log or alert.
+func WithExceededHandler(fn func(s QuotaSnapshot, requested ByteSize)) QuotaOption {
+	return func(q *Quota) {
+		q.onExceeded = fn
+	}
+}
+
+// Quota accounts bytes against a limit, such as the storage of a tenant.
+// Bytes can be reserved ahead of a write of known size, then used, and
+// released once freed. It is safe for concurrent use; callbacks are called
+// without holding the quota's lock, so they may use it.
+//
+//	q := NewQuota(10*GB, WithSoftLimit(8*GB, func(s QuotaSnapshot, above bool) {
+//		log.Printf("tenant at %s of %s", s.Total(), s.Limit)
+//	}))
+//	if err := q.Reserve(upload.Size); err != nil {
+//		return err
+//	}
+//	n, err := store(upload)
+//	q.Use(n)
+//	q.Unreserve(upload.Size - n)
+type Quota struct {
+	mu         sync.Mutex
+	limit      ByteSize
+	soft       ByteSize
+	used       ByteSize
+	reserved   ByteSize
+	above      bool
+	onSoft     func(QuotaSnapshot, bool)
+	onExceeded func(QuotaSnapshot, ByteSize)
+	freed      chan struct{} // closed when bytes are freed, for WaitReserve
+}
+
+// NewQuota returns a Quota with the given limit.
+func NewQuota(limit ByteSize, opts ...QuotaOption) *Quota {
+	q := &Quota{limit: limit}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return q
+}
+
+// Reserve sets aside n bytes for later use. It fails with an error wrapping
+// ErrQuotaExceeded if they do not fit.
+func (q *Quota) Reserve(n ByteSize) error {
+	return q.update(func() error {
+		if err := q.check(n); err != nil {
+			return err
+		}
+		q.reserved += n
+		return nil
+	})
+}
+
+// WaitReserve is like Reserve, but waits for bytes to be freed while they
+// do not fit, until ctx is done. It fails at once if n exceeds the limit.
+func (q *Quota) WaitReserve(ctx context.Context, n ByteSize) error {
+	for {
+		var freed chan struct{}
+		err := q.update(func() error {
+			if n < 0 || n > q.limit {
+				return q.check(n)
+			}
+			if q.check(n) != nil {
+				if q.freed == nil {
+					q.freed = make(chan struct{})
+				}
+				freed = q.freed
+				return nil
+			}
+			q.reserved += n
+			return nil
+		})
+		if err != nil || freed == nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-freed:
+		}
+	}
+}
+
+// Unreserve gives back n reserved bytes which were not used.
+func (q *Quota) Unreserve(n ByteSize) {
+	_ = q.update(func() error {
+		q.reserved -= clampSize(n, q.reserved)
+		q.notifyFreed()
+		return nil
+	})
+}
+
+// Use records n bytes as used. Reserved bytes are used first; the rest must
+// fit in the quota, or Use fails with an error wrapping ErrQuotaExceeded
+// and nothing is recorded.
+func (q *Quota) Use(n ByteSize) error {
+	return q.update(func() error {
+		fromReserved := clampSize(n, q.reserved)
+		if err := q.check(n - fromReserved); err != nil {
+			return err
+		}
+		q.reserved -= fromReserved
+		q.used += n
+		return nil
+	})
+}
+
+// Release gives back n used bytes, e.g. after a file is deleted.
+func (q *Quota) Release(n ByteSize) {
+	_ = q.update(func() error {
+		q.used -= clampSize(n, q.used)
+		q.notifyFreed()
+		return nil
+	})
+}
+
+// SetLimit changes the limit. Bytes already used or reserved are kept,
+// even if they exceed the new limit.
+func (q *Quota) SetLimit(limit ByteSize) {
+	_ = q.update(func() error {
+		q.limit = limit
+		q.notifyFreed()
+		return nil
+	})
+}
+
+// Snapshot returns the current state of the quota.
+func (q *Quota) Snapshot() QuotaSnapshot {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.snapshot()
+}
+
+func (q *Quota) snapshot() QuotaSnapshot {
+	return QuotaSnapshot{Limit: q.limit, Soft: q.soft, Used: q.used, Reserved: q.reserved}
+}
+
+// notifyFreed wakes up WaitReserve calls. Call with q.mu held.
+func (q *Quota) notifyFreed() {
+	if q.freed != nil {
+		close(q.freed)
+		q.freed = nil
+	}
+}
+
+// check returns an error if n more bytes do not fit. Call with q.mu held.
+func (q *Quota) check(n ByteSize) error {
+	if n < 0 {
+		return fmt.Errorf("invalid size %s", n)
+	}
+	if s := q.snapshot(); n > s.Available() {
+		return &quotaError{snapshot: s, requested: n}
+	}
+	return nil
+}
+
+// update runs fn with the lock held, then calls the callbacks.
+func (q *Quota) update(fn func() error) error {
+	q.mu.Lock()
+	err := fn()
+	s := q.snapshot()
+	crossed := false
+	if q.soft > 0 && (s.Total() >= q.soft) != q.above {
+		q.above = !q.above
+		crossed = true
+	}
+	above := q.above
+	q.mu.Unlock()
+
+	if crossed && q.onSoft != nil {
+		q.onSoft(s, above)
+	}
+	var qe *quotaError
+	if errors.As(err, &qe) && q.onExceeded != nil {
+		q.onExceeded(qe.snapshot, qe.requested)
+	}
+	return err
+}
+
+// quotaError is the error returned when a request does not fit a Quota.
+type quotaError struct {
+	snapshot  QuotaSnapshot
+	requested ByteSize
+}
+
+func (e *quotaError) Error() string {
+	return fmt.Sprintf("%v: %s requested, %s available of %s", ErrQuotaExceeded, e.requested, e.snapshot.Available(), e.snapshot.Limit)
+}
+
+func (e *quotaError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// clampSize returns n limited to the range 0 to max.
+func clampSize(n, max ByteSize) ByteSize {
+	if n < 0 {
+		return 0
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
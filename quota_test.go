@@ -0,0 +1,117 @@
+package bytesizer
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuota(t *testing.T) {
+	q := NewQuota(10 * MB)
+
+	assert.NoError(t, q.Reserve(4*MB))
+	assert.NoError(t, q.Use(3*MB))
+	assert.Equal(t, QuotaSnapshot{Limit: 10 * MB, Used: 3 * MB, Reserved: MB}, q.Snapshot())
+
+	// Use beyond the reservation takes from the free space.
+	assert.NoError(t, q.Use(2*MB))
+	assert.Equal(t, QuotaSnapshot{Limit: 10 * MB, Used: 5 * MB}, q.Snapshot())
+
+	err := q.Reserve(6 * MB)
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+	assert.EqualError(t, err, "quota exceeded: 6MB requested, 5MB available of 10MB")
+	assert.ErrorIs(t, q.Use(6*MB), ErrQuotaExceeded)
+	assert.Equal(t, 5*MB, q.Snapshot().Available())
+
+	assert.NoError(t, q.Reserve(5*MB))
+	assert.Equal(t, ByteSize(0), q.Snapshot().Available())
+	q.Unreserve(10 * MB)
+	q.Release(2 * MB)
+	assert.Equal(t, QuotaSnapshot{Limit: 10 * MB, Used: 3 * MB}, q.Snapshot())
+	q.Release(10 * MB)
+	assert.Equal(t, ByteSize(0), q.Snapshot().Used)
+
+	assert.Error(t, q.Reserve(-1))
+
+	q.SetLimit(MB)
+	assert.ErrorIs(t, q.Reserve(2*MB), ErrQuotaExceeded)
+}
+
+func TestQuotaCallbacks(t *testing.T) {
+	type softEvent struct {
+		total ByteSize
+		above bool
+	}
+	var soft []softEvent
+	var exceeded []ByteSize
+
+	var q *Quota
+	q = NewQuota(10*MB,
+		WithSoftLimit(8*MB, func(s QuotaSnapshot, above bool) {
+			soft = append(soft, softEvent{s.Total(), above})
+			_ = q.Snapshot() // callbacks may use the quota
+		}),
+		WithExceededHandler(func(s QuotaSnapshot, requested ByteSize) {
+			exceeded = append(exceeded, requested)
+		}),
+	)
+
+	assert.NoError(t, q.Use(5*MB))
+	assert.NoError(t, q.Reserve(3*MB))
+	assert.NoError(t, q.Use(MB))
+	assert.Error(t, q.Reserve(3*MB))
+	q.Unreserve(2 * MB)
+	assert.NoError(t, q.Use(2*MB))
+
+	assert.Equal(t, []softEvent{{8 * MB, true}, {6 * MB, false}, {8 * MB, true}}, soft)
+	assert.Equal(t, []ByteSize{3 * MB}, exceeded)
+}
+
+func TestQuotaConcurrent(t *testing.T) {
+	q := NewQuota(100 * KB)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	granted := 0
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if q.Reserve(KB) == nil {
+				mu.Lock()
+				granted++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 100, granted)
+	assert.Equal(t, 100*KB, q.Snapshot().Reserved)
+}
+
+func TestQuotaWaitReserve(t *testing.T) {
+	q := NewQuota(10 * KB)
+	assert.NoError(t, q.WaitReserve(context.Background(), 8*KB))
+
+	done := make(chan error)
+	go func() {
+		done <- q.WaitReserve(context.Background(), 4*KB)
+	}()
+	select {
+	case <-done:
+		t.Fatal("WaitReserve returned before bytes were freed")
+	case <-time.After(20 * time.Millisecond):
+	}
+	q.Unreserve(2 * KB)
+	assert.NoError(t, <-done)
+	assert.Equal(t, 10*KB, q.Snapshot().Reserved)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, q.WaitReserve(ctx, KB), context.DeadlineExceeded)
+
+	assert.ErrorIs(t, q.WaitReserve(context.Background(), 11*KB), ErrQuotaExceeded)
+	assert.Error(t, q.WaitReserve(context.Background(), -1))
+}
@@ -0,0 +1,126 @@
+package bytesizer
+
+import (
+	"fmt"
+	"math"
+)
+
+// StorageCapacity is the usable part of raw storage under a StorageLayout.
+type StorageCapacity struct {
+	Raw    ByteSize
+	Usable ByteSize
+	// Overhead is the share of Raw not usable for data, from 0 to 100.
+	Overhead float64
+}
+
+// StorageLayout is a way of spreading data over disks, such as a RAID level
+// or a replication scheme.
+type StorageLayout struct {
+	name     string
+	minDisks int  // 0 for invalid layouts
+	even     bool // whether the number of disks must be even
+	// usable returns the usable bytes of n disks of at least min bytes and
+	// sum bytes in total.
+	usable func(n int, min, sum ByteSize) ByteSize
+}
+
+// RAID levels. Disks of different sizes are used up to the size of the
+// smallest, as by most controllers.
+var (
+	// RAID0 stripes data over all disks, without redundancy.
+	RAID0 = StorageLayout{name: "RAID0", minDisks: 1, usable: func(n int, min, _ ByteSize) ByteSize {
+		return ByteSize(n) * min
+	}}
+	// RAID1 mirrors the data on every disk.
+	RAID1 = StorageLayout{name: "RAID1", minDisks: 2, usable: func(_ int, min, _ ByteSize) ByteSize {
+		return min
+	}}
+	// RAID5 stripes data with one disk's worth of parity.
+	RAID5 = StorageLayout{name: "RAID5", minDisks: 3, usable: func(n int, min, _ ByteSize) ByteSize {
+		return ByteSize(n-1) * min
+	}}
+	// RAID6 stripes data with two disks' worth of parity.
+	RAID6 = StorageLayout{name: "RAID6", minDisks: 4, usable: func(n int, min, _ ByteSize) ByteSize {
+		return ByteSize(n-2) * min
+	}}
+	// RAID10 stripes data over mirrored pairs of disks.
+	RAID10 = StorageLayout{name: "RAID10", minDisks: 4, even: true, usable: func(n int, min, _ ByteSize) ByteSize {
+		return ByteSize(n/2) * min
+	}}
+)
+
+// Replication returns the layout of distributed storage keeping copies of
+// all data on different disks, such as Ceph or HDFS replication. Disks are
+// used in full.
+func Replication(copies int) StorageLayout {
+	l := StorageLayout{name: fmt.Sprintf("%dx replication", copies)}
+	if copies >= 1 {
+		l.minDisks = copies
+		l.usable = func(_ int, _, sum ByteSize) ByteSize {
+			return sum / ByteSize(copies)
+		}
+	}
+	return l
+}
+
+// ErasureCoding returns the layout of distributed storage splitting data
+// into data chunks plus parity chunks on different disks, such as 8+3 in
+// Ceph or MinIO. Disks are used in full.
+func ErasureCoding(data, parity int) StorageLayout {
+	l := StorageLayout{name: fmt.Sprintf("%d+%d erasure coding", data, parity)}
+	if data >= 1 && parity >= 0 {
+		l.minDisks = data + parity
+		l.usable = func(_ int, _, sum ByteSize) ByteSize {
+			return ByteSize(float64(sum) * float64(data) / float64(data+parity))
+		}
+	}
+	return l
+}
+
+// String returns the name of the layout, e.g. "RAID6" or "3x replication".
+func (l StorageLayout) String() string {
+	return l.name
+}
+
+// Capacity returns the raw and usable capacity of disks in the layout.
+//
+//	c, err := RAID6.Capacity(8, 12*DecimalTB)
+//	fmt.Println(c.Usable, c.Overhead) // 65.48TB 25
+func (l StorageLayout) Capacity(disks int, size ByteSize) (StorageCapacity, error) {
+	sizes := make([]ByteSize, disks)
+	for i := range sizes {
+		sizes[i] = size
+	}
+	return l.CapacityOf(sizes...)
+}
+
+// CapacityOf is like Capacity, for disks of different sizes.
+func (l StorageLayout) CapacityOf(disks ...ByteSize) (StorageCapacity, error) {
+	if l.minDisks < 1 {
+		return StorageCapacity{}, fmt.Errorf("invalid storage layout %q", l)
+	}
+	n := len(disks)
+	if n < l.minDisks {
+		return StorageCapacity{}, fmt.Errorf("%s needs at least %d disks, got %d", l, l.minDisks, n)
+	}
+	if l.even && n%2 != 0 {
+		return StorageCapacity{}, fmt.Errorf("%s needs an even number of disks, got %d", l, n)
+	}
+
+	min, sum := ByteSize(math.MaxInt), ByteSize(0)
+	for _, d := range disks {
+		if d < 0 {
+			return StorageCapacity{}, fmt.Errorf("invalid disk size %s", d)
+		}
+		if d < min {
+			min = d
+		}
+		sum += d
+	}
+
+	c := StorageCapacity{Raw: sum, Usable: l.usable(n, min, sum)}
+	if sum > 0 {
+		c.Overhead = (1 - float64(c.Usable)/float64(sum)) * 100
+	}
+	return c, nil
+}
@@ -0,0 +1,84 @@
+package bytesizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorageLayoutCapacity(t *testing.T) {
+	tests := []struct {
+		layout       StorageLayout
+		disks        int
+		wantUsable   ByteSize
+		wantOverhead float64
+	}{
+		{RAID0, 4, 16 * TB, 0},
+		{RAID1, 2, 4 * TB, 50},
+		{RAID5, 4, 12 * TB, 25},
+		{RAID6, 8, 24 * TB, 25},
+		{RAID10, 6, 12 * TB, 50},
+		{Replication(3), 9, 12 * TB, 66.67},
+		{ErasureCoding(8, 3), 11, 32 * TB, 27.27},
+		{ErasureCoding(4, 2), 12, 32 * TB, 33.33},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout.String(), func(t *testing.T) {
+			c, err := tt.layout.Capacity(tt.disks, 4*TB)
+			assert.NoError(t, err)
+			assert.Equal(t, ByteSize(tt.disks)*4*TB, c.Raw)
+			assert.Equal(t, tt.wantUsable, c.Usable)
+			assert.InDelta(t, tt.wantOverhead, c.Overhead, 0.01)
+		})
+	}
+}
+
+func TestStorageLayoutCapacityOf(t *testing.T) {
+	disks := []ByteSize{4 * TB, 4 * TB, 2 * TB, 6 * TB}
+	tests := []struct {
+		layout     StorageLayout
+		wantUsable ByteSize
+	}{
+		{RAID0, 8 * TB},
+		{RAID1, 2 * TB},
+		{RAID5, 6 * TB},
+		{RAID6, 4 * TB},
+		{RAID10, 4 * TB},
+		{Replication(2), 8 * TB},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.layout.String(), func(t *testing.T) {
+			c, err := tt.layout.CapacityOf(disks...)
+			assert.NoError(t, err)
+			assert.Equal(t, 16*TB, c.Raw)
+			assert.Equal(t, tt.wantUsable, c.Usable)
+		})
+	}
+}
+
+func TestStorageLayoutErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		layout  StorageLayout
+		disks   []ByteSize
+		wantErr string
+	}{
+		{"Too few disks", RAID6, []ByteSize{TB, TB, TB}, "RAID6 needs at least 4 disks, got 3"},
+		{"Odd disks", RAID10, []ByteSize{TB, TB, TB, TB, TB}, "RAID10 needs an even number of disks, got 5"},
+		{"Too few for replicas", Replication(3), []ByteSize{TB, TB}, "3x replication needs at least 3 disks"},
+		{"Too few for chunks", ErasureCoding(8, 3), []ByteSize{TB}, "8+3 erasure coding needs at least 11 disks"},
+		{"Negative size", RAID1, []ByteSize{TB, -TB}, "invalid disk size"},
+		{"No copies", Replication(0), []ByteSize{TB}, `invalid storage layout "0x replication"`},
+		{"Negative parity", ErasureCoding(4, -1), []ByteSize{TB, TB, TB}, "invalid storage layout"},
+		{"Zero value", StorageLayout{}, []ByteSize{TB}, "invalid storage layout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.layout.CapacityOf(tt.disks...)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package bytesizer
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rate is a transfer rate in bytes per second, e.g. 10 * MBps.
+type Rate float64
+
+// Common rates.
+const (
+	Bps  Rate = 1
+	KBps      = Bps * Rate(KB)
+	MBps      = Bps * Rate(MB)
+	GBps      = Bps * Rate(GB)
+	TBps      = Bps * Rate(TB)
+	PBps      = Bps * Rate(PB)
+)
+
+// rateIntervals are the interval suffixes accepted by ParseRate.
+var rateIntervals = map[string]time.Duration{
+	"ms":  time.Millisecond,
+	"s":   time.Second,
+	"sec": time.Second,
+	"m":   time.Minute,
+	"min": time.Minute,
+	"h":   time.Hour,
+}
+
+// ParseRate parses a rate such as "10MB/s", "1.5GB/min" or "512KB/100ms".
+// The size part accepts any format accepted by Parse. The interval is one of
+// ms, s (sec), m (min) and h, or any duration accepted by time.ParseDuration.
+func ParseRate(s string) (Rate, error) {
+	i := strings.LastIndexByte(s, '/')
+	if i < 0 {
+		return 0, fmt.Errorf("invalid rate %q: missing interval", s)
+	}
+
+	size, err := parseSize(s[:i])
+	if err != nil {
+		return 0, fmt.Errorf("invalid rate %q: %w", s, err)
+	}
+
+	per, ok := rateIntervals[s[i+1:]]
+	if !ok {
+		if per, err = time.ParseDuration(s[i+1:]); err != nil {
+			return 0, fmt.Errorf("invalid rate %q: invalid interval %q", s, s[i+1:])
+		}
+	}
+	if per <= 0 {
+		return 0, fmt.Errorf("invalid rate %q: interval must be positive", s)
+	}
+
+	r := size / per.Seconds()
+	if math.IsInf(r, 0) {
+		return 0, fmt.Errorf("invalid rate %q: %w", s, ErrOutOfRange)
+	}
+	return Rate(r), nil
+}
+
+// RateFromBits converts a rate in bits per second, as used for network
+// links, into a Rate.
+func RateFromBits(bps float64) Rate {
+	return Rate(bps / 8)
+}
+
+// BitsPerSecond returns the rate in bits per second.
+func (r Rate) BitsPerSecond() float64 {
+	return float64(r) * 8
+}
+
+// BytesPerSecond returns the rate in bytes per second.
+func (r Rate) BytesPerSecond() float64 {
+	return float64(r)
+}
+
+// Over returns the number of bytes transferred at rate r during d, capped
+// at Unlimited. It returns 0 for a NaN rate.
+func (r Rate) Over(d time.Duration) ByteSize {
+	return clampFloat(float64(r) * d.Seconds())
+}
+
+// RateFor returns the rate at which size bytes are transferred in d.
+// It returns zero if d is not positive.
+func RateFor(size ByteSize, d time.Duration) Rate {
+	if d <= 0 {
+		return 0
+	}
+	return Rate(float64(size) / d.Seconds())
+}
+
+// TransferTime returns how long it takes to transfer fs at rate, e.g.
+// (3 * TB).TransferTime(120 * MBps) is about 7h17m. The result is capped at
+// the largest time.Duration, which is also returned for a non-positive rate.
+func (fs ByteSize) TransferTime(rate Rate) time.Duration {
+	if fs <= 0 {
+		return 0
+	}
+	if rate <= 0 {
+		return math.MaxInt64
+	}
+	d := float64(fs) / float64(rate) * float64(time.Second)
+	if d >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(d)
+}
+
+// String formats the rate per second with an appropriate unit, e.g. "10MB/s".
+func (r Rate) String() string {
+	return r.FormatRate(time.Second)
+}
+
+// FormatRate formats the rate per interval with an appropriate unit, e.g.
+// MBps.FormatRate(time.Minute) returns "60MB/min".
+func (r Rate) FormatRate(per time.Duration) string {
+	v := float64(r) * per.Seconds()
+
+	var suffix string
+	switch per {
+	case time.Millisecond:
+		suffix = "/ms"
+	case time.Second:
+		suffix = "/s"
+	case time.Minute:
+		suffix = "/min"
+	case time.Hour:
+		suffix = "/h"
+	default:
+		suffix = "/" + per.String()
+	}
+
+	for i := len(units) - 1; i > 0; i-- {
+		if math.Abs(v) >= float64(units[i].size) {
+			return formatString(v/float64(units[i].size), units[i].unitName+suffix, 2)
+		}
+	}
+	return formatString(v, "B"+suffix, 2)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// It returns String when that form parses back to the same rate, and the
+// exact number of bytes per second (e.g. "1049600B/s") otherwise. NaN and
+// infinite rates are an error wrapping ErrNotFinite.
+func (r Rate) MarshalText() ([]byte, error) {
+	if math.IsNaN(float64(r)) || math.IsInf(float64(r), 0) {
+		return nil, fmt.Errorf("cannot marshal rate: %w: %v", ErrNotFinite, float64(r))
+	}
+	s := r.String()
+	if v, err := ParseRate(s); err == nil && v == r {
+		return []byte(s), nil
+	}
+	return append(strconv.AppendFloat(nil, float64(r), 'f', -1, 64), "B/s"...), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// It accepts any format accepted by ParseRate.
+func (r *Rate) UnmarshalText(text []byte) error {
+	v, err := ParseRate(string(text))
+	if err != nil {
+		return err
+	}
+	*r = v
+	return nil
+}
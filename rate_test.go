@@ -0,0 +1,145 @@
+package bytesizer
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expectErr bool
+		expected  Rate
+	}{
+		{"Per second", "10MB/s", false, 10 * MBps},
+		{"Per sec", "10MB/sec", false, 10 * MBps},
+		{"Per minute", "60MB/min", false, MBps},
+		{"Per m", "1.5GB/m", false, 1.5 * GBps / 60},
+		{"Per hour", "3600KB/h", false, KBps},
+		{"Per millisecond", "1KB/ms", false, 1000 * KBps},
+		{"Duration interval", "512KB/100ms", false, 5 * MBps},
+		{"Fractional bytes", "1B/2s", false, 0.5},
+		{"Bare number", "1024/s", false, KBps},
+		{"Missing interval", "10MB", true, 0},
+		{"Invalid size", "10XB/s", true, 0},
+		{"Invalid interval", "10MB/day", true, 0},
+		{"Zero interval", "10MB/0s", true, 0},
+		{"Negative interval", "10MB/-1s", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseRate(tt.input)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.InDelta(t, float64(tt.expected), float64(r), 1e-9)
+			}
+		})
+	}
+}
+
+func TestRateString(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate     Rate
+		per      time.Duration
+		expected string
+	}{
+		{"Bytes", 512, time.Second, "512B/s"},
+		{"Megabytes", 10 * MBps, time.Second, "10MB/s"},
+		{"Decimal", 1.5 * GBps, time.Second, "1.5GB/s"},
+		{"Fraction of a byte", 0.5, time.Second, "0.5B/s"},
+		{"Per minute", MBps, time.Minute, "60MB/min"},
+		{"Per hour", KBps, time.Hour, "3.52MB/h"},
+		{"Per millisecond", MBps, time.Millisecond, "1.02KB/ms"},
+		{"Other interval", MBps, 5 * time.Second, "5MB/5s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.rate.FormatRate(tt.per))
+		})
+	}
+
+	assert.Equal(t, "10MB/s", (10 * MBps).String())
+}
+
+func TestRateConversions(t *testing.T) {
+	assert.Equal(t, 800.0, Rate(100).BitsPerSecond())
+	assert.Equal(t, 125*1000*1000.0, RateFromBits(1e9).BytesPerSecond()) // 1Gbps
+	assert.Equal(t, 600*MB, (10 * MBps).Over(time.Minute))
+	assert.Equal(t, 512*KB, MBps.Over(500*time.Millisecond))
+}
+
+func TestRateMarshalText(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate     Rate
+		expected string
+	}{
+		{"Humanized", 10 * MBps, "10MB/s"},
+		{"Exact bytes", Rate(1025 * KB), "1049600B/s"},
+		{"Fraction", 1.25, "1.25B/s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.rate.MarshalText()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(b))
+
+			var got Rate
+			assert.NoError(t, got.UnmarshalText(b))
+			assert.Equal(t, tt.rate, got)
+		})
+	}
+}
+
+func TestRateGob(t *testing.T) {
+	var buf bytes.Buffer
+	var in interface{} = 10 * MBps
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(&in))
+
+	var out interface{}
+	assert.NoError(t, gob.NewDecoder(&buf).Decode(&out))
+	assert.Equal(t, 10*MBps, out)
+}
+
+func TestTransferTime(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     ByteSize
+		rate     Rate
+		expected time.Duration
+	}{
+		{"Seconds", 600 * MB, 10 * MBps, time.Minute},
+		{"Terabytes", 3 * TB, 120 * MBps, 26214400 * time.Millisecond},
+		{"Fraction", KB, 2 * KBps, 500 * time.Millisecond},
+		{"Zero size", 0, MBps, 0},
+		{"Zero rate", KB, 0, math.MaxInt64},
+		{"Overflow", PB, 1e-6, math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.size.TransferTime(tt.rate))
+		})
+	}
+}
+
+func TestRateFor(t *testing.T) {
+	assert.Equal(t, 10*MBps, RateFor(600*MB, time.Minute))
+	assert.Equal(t, Rate(0), RateFor(MB, 0))
+
+	r := RateFor(3*TB, 8*time.Hour)
+	assert.Equal(t, "109.23MB/s", r.String())
+	assert.InDelta(t, float64(8*time.Hour), float64((3 * TB).TransferTime(r)), float64(time.Millisecond))
+}
@@ -0,0 +1,38 @@
+package bytesizer
+
+import "fmt"
+
+// Rlimit is a resource limit of the current process, in bytes. Either value
+// is Unlimited if the resource has no limit.
+type Rlimit struct {
+	// Soft is the limit enforced by the kernel.
+	Soft ByteSize
+	// Hard is the ceiling the soft limit can be raised to.
+	Hard ByteSize
+}
+
+// Allows reports whether n bytes fit within the soft limit.
+func (l Rlimit) Allows(n ByteSize) bool {
+	return n <= l.Soft
+}
+
+// String returns the limits like "soft 1GB, hard unlimited".
+func (l Rlimit) String() string {
+	return fmt.Sprintf("soft %s, hard %s", formatRlimit(l.Soft), formatRlimit(l.Hard))
+}
+
+func formatRlimit(v ByteSize) string {
+	if v == Unlimited {
+		return "unlimited"
+	}
+	return v.String()
+}
+
+// rlimitSize converts a raw rlimit value to a ByteSize. RLIM_INFINITY, and
+// anything too large for a ByteSize, is Unlimited.
+func rlimitSize(v uint64) ByteSize {
+	if v >= uint64(Unlimited) {
+		return Unlimited
+	}
+	return ByteSize(v)
+}
@@ -0,0 +1,19 @@
+//go:build !(linux || darwin || freebsd || netbsd || dragonfly || solaris)
+
+package bytesizer
+
+import "errors"
+
+var errRlimitUnsupported = errors.New("resource limits are not supported on this platform")
+
+// FileSizeLimit returns RLIMIT_FSIZE, the largest file the process may
+// write. It is not supported on this platform.
+func FileSizeLimit() (Rlimit, error) {
+	return Rlimit{}, errRlimitUnsupported
+}
+
+// AddressSpaceLimit returns RLIMIT_AS, the largest virtual memory the
+// process may map. It is not supported on this platform.
+func AddressSpaceLimit() (Rlimit, error) {
+	return Rlimit{}, errRlimitUnsupported
+}
@@ -0,0 +1,33 @@
+package bytesizer
+
+import (
+	"math"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRlimit(t *testing.T) {
+	l := Rlimit{Soft: GB, Hard: Unlimited}
+	assert.True(t, l.Allows(GB))
+	assert.False(t, l.Allows(GB+1))
+	assert.Equal(t, "soft 1GB, hard unlimited", l.String())
+
+	assert.Equal(t, MB, rlimitSize(uint64(MB)))
+	assert.Equal(t, Unlimited, rlimitSize(math.MaxUint64))
+	assert.Equal(t, Unlimited, rlimitSize(math.MaxInt64))
+}
+
+func TestRlimitCurrent(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("resource limits are not supported on windows")
+	}
+
+	for _, fn := range []func() (Rlimit, error){FileSizeLimit, AddressSpaceLimit} {
+		l, err := fn()
+		assert.NoError(t, err)
+		assert.Greater(t, l.Soft, ByteSize(0))
+		assert.LessOrEqual(t, l.Soft, l.Hard)
+	}
+}
@@ -0,0 +1,31 @@
+//go:build linux || darwin || freebsd || netbsd || dragonfly || solaris
+
+package bytesizer
+
+import "syscall"
+
+// FileSizeLimit returns RLIMIT_FSIZE, the largest file the process may
+// write, as set by "ulimit -f".
+//
+// A server can warn at startup when its files cannot grow as configured:
+//
+//	if lim, err := FileSizeLimit(); err == nil && !lim.Allows(maxSegment) {
+//		log.Printf("file size limit %s is below the segment size %s", lim.Soft, maxSegment)
+//	}
+func FileSizeLimit() (Rlimit, error) {
+	return getRlimit(syscall.RLIMIT_FSIZE)
+}
+
+// AddressSpaceLimit returns RLIMIT_AS, the largest virtual memory the
+// process may map, as set by "ulimit -v".
+func AddressSpaceLimit() (Rlimit, error) {
+	return getRlimit(syscall.RLIMIT_AS)
+}
+
+func getRlimit(resource int) (Rlimit, error) {
+	var lim syscall.Rlimit
+	if err := syscall.Getrlimit(resource, &lim); err != nil {
+		return Rlimit{}, err
+	}
+	return Rlimit{Soft: rlimitSize(uint64(lim.Cur)), Hard: rlimitSize(uint64(lim.Max))}, nil
+}
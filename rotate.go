@@ -0,0 +1,205 @@
+package bytesizer
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RotateOption configures a RotateWriter.
+type RotateOption func(*RotateWriter)
+
+// WithMaxBackups keeps at most n rotated files. The default, or n ≤ 0,
+// keeps them all.
+func WithMaxBackups(n int) RotateOption {
+	return func(w *RotateWriter) {
+		w.maxBackups = n
+	}
+}
+
+// WithMaxTotalSize removes the oldest rotated files until they add up to at
+// most size. The default, or size ≤ 0, is no limit.
+func WithMaxTotalSize(size ByteSize) RotateOption {
+	return func(w *RotateWriter) {
+		w.maxTotal = size
+	}
+}
+
+// RotateWriter is an io.WriteCloser writing to a file that is rotated when
+// it reaches a size: the file is renamed to "<name>.1", older files shift
+// to "<name>.2" and so on, and a new file is opened. It is safe for
+// concurrent use.
+//
+// A write is never split across files, so a write larger than the maximum
+// size goes to a file of its own.
+type RotateWriter struct {
+	filename   string
+	maxSize    ByteSize
+	maxBackups int
+	maxTotal   ByteSize
+
+	mu   sync.Mutex
+	f    *os.File
+	size ByteSize
+}
+
+// NewRotateWriter opens filename for appending, creating it if needed, and
+// returns a RotateWriter rotating it once it reaches maxSize:
+//
+//	w, err := NewRotateWriter("/var/log/app.log", 100*MB, WithMaxBackups(5))
+//	if err != nil {
+//		return err
+//	}
+//	log.SetOutput(w)
+func NewRotateWriter(filename string, maxSize ByteSize, opts ...RotateOption) (*RotateWriter, error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("invalid rotation size %s", maxSize)
+	}
+	w := &RotateWriter{filename: filename, maxSize: maxSize}
+	for _, opt := range opts {
+		opt(w)
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write writes p to the current file, rotating it first if p would take it
+// past the maximum size.
+func (w *RotateWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return 0, os.ErrClosed
+	}
+	if w.size > 0 && w.size+ByteSize(len(p)) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.f.Write(p)
+	w.size += ByteSize(n)
+	return n, err
+}
+
+// Size returns the size of the current file.
+func (w *RotateWriter) Size() ByteSize {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.size
+}
+
+// Rotate rotates the current file regardless of its size.
+func (w *RotateWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return os.ErrClosed
+	}
+	return w.rotate()
+}
+
+// Close closes the current file.
+func (w *RotateWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.f == nil {
+		return os.ErrClosed
+	}
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
+
+func (w *RotateWriter) open() error {
+	f, err := os.OpenFile(w.filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.f = f
+	w.size = ByteSize(info.Size())
+	return nil
+}
+
+// rotate closes the current file, moves it to the first backup and opens a
+// new one. If the backups cannot be moved, it reopens the current file, so
+// that writes go on and the next rotation tries again.
+func (w *RotateWriter) rotate() error {
+	err := w.f.Close()
+	w.f = nil
+	if err == nil {
+		err = w.shift()
+	}
+	if oerr := w.open(); err == nil {
+		err = oerr
+	}
+	return err
+}
+
+// shift moves the current file and the backups up by one, oldest first.
+func (w *RotateWriter) shift() error {
+	n := 0
+	for exists(w.backup(n + 1)) {
+		n++
+	}
+	for i := n; i >= 1; i-- {
+		var err error
+		if w.maxBackups > 0 && i >= w.maxBackups {
+			err = os.Remove(w.backup(i))
+		} else {
+			err = os.Rename(w.backup(i), w.backup(i+1))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(w.filename, w.backup(1)); err != nil {
+		return err
+	}
+	if w.maxTotal > 0 {
+		return w.trim()
+	}
+	return nil
+}
+
+// trim removes the oldest backups until they fit in the total size.
+func (w *RotateWriter) trim() error {
+	var sizes []ByteSize
+	var total ByteSize
+	for i := 1; ; i++ {
+		info, err := os.Stat(w.backup(i))
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		sizes = append(sizes, ByteSize(info.Size()))
+		total += ByteSize(info.Size())
+	}
+	for i := len(sizes); i >= 1 && total > w.maxTotal; i-- {
+		if err := os.Remove(w.backup(i)); err != nil {
+			return err
+		}
+		total -= sizes[i-1]
+	}
+	return nil
+}
+
+func (w *RotateWriter) backup(i int) string {
+	return fmt.Sprintf("%s.%d", w.filename, i)
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
@@ -0,0 +1,117 @@
+package bytesizer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fileContents returns the contents of the files, "" for missing ones.
+func fileContents(t *testing.T, paths ...string) []string {
+	t.Helper()
+	var out []string
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if !os.IsNotExist(err) {
+			assert.NoError(t, err)
+		}
+		out = append(out, string(data))
+	}
+	return out
+}
+
+func TestRotateWriter(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewRotateWriter(name, 10)
+	assert.NoError(t, err)
+
+	for _, s := range []string{"aaaa", "bbbb", "cc", "dddd", "eeeeeeeeeeee", "f"} {
+		n, err := w.Write([]byte(s))
+		assert.NoError(t, err)
+		assert.Equal(t, len(s), n)
+	}
+	assert.Equal(t, ByteSize(1), w.Size())
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"f", "eeeeeeeeeeee", "dddd", "aaaabbbbcc", ""},
+		fileContents(t, name, name+".1", name+".2", name+".3", name+".4"))
+
+	_, err = w.Write([]byte("x"))
+	assert.ErrorIs(t, err, os.ErrClosed)
+	assert.ErrorIs(t, w.Close(), os.ErrClosed)
+}
+
+func TestRotateWriterAppends(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	assert.NoError(t, os.WriteFile(name, []byte("12345678"), 0o644))
+
+	w, err := NewRotateWriter(name, 10)
+	assert.NoError(t, err)
+	defer w.Close()
+	assert.Equal(t, ByteSize(8), w.Size())
+
+	_, err = w.Write([]byte("abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"abc", "12345678"}, fileContents(t, name, name+".1"))
+}
+
+func TestRotateWriterMaxBackups(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewRotateWriter(name, MB, WithMaxBackups(2))
+	assert.NoError(t, err)
+	defer w.Close()
+
+	for _, s := range []string{"1", "2", "3", "4"} {
+		_, err := w.Write([]byte(s))
+		assert.NoError(t, err)
+		assert.NoError(t, w.Rotate())
+	}
+	assert.Equal(t, []string{"", "4", "3", ""}, fileContents(t, name, name+".1", name+".2", name+".3"))
+}
+
+func TestRotateWriterRotateError(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewRotateWriter(name, 4, WithMaxBackups(1))
+	assert.NoError(t, err)
+	defer w.Close()
+
+	// A backup that cannot be removed fails the rotation, but the current
+	// file stays open.
+	assert.NoError(t, os.MkdirAll(filepath.Join(name+".1", "dir"), 0o755))
+	_, err = w.Write([]byte("abc"))
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("de"))
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, os.ErrClosed)
+
+	assert.NoError(t, os.RemoveAll(name+".1"))
+	_, err = w.Write([]byte("de"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"de", "abc"}, fileContents(t, name, name+".1"))
+}
+
+func TestRotateWriterMaxTotalSize(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	w, err := NewRotateWriter(name, 10, WithMaxTotalSize(25))
+	assert.NoError(t, err)
+	defer w.Close()
+
+	for _, s := range []string{"a", "b", "c", "d"} {
+		_, err := w.Write([]byte(strings.Repeat(s, 10)))
+		assert.NoError(t, err)
+	}
+	// Each write rotates the previous file out; only two backups fit in 25 bytes.
+	assert.Equal(t, []string{strings.Repeat("d", 10), strings.Repeat("c", 10), strings.Repeat("b", 10), ""},
+		fileContents(t, name, name+".1", name+".2", name+".3"))
+}
+
+func TestNewRotateWriterErrors(t *testing.T) {
+	_, err := NewRotateWriter(filepath.Join(t.TempDir(), "app.log"), 0)
+	assert.Error(t, err)
+
+	_, err = NewRotateWriter(filepath.Join(t.TempDir(), "missing", "app.log"), MB)
+	assert.Error(t, err)
+}
@@ -0,0 +1,61 @@
+package bytesizer
+
+// RoundingMode selects how In rounds a size that is not a whole number of
+// units.
+type RoundingMode int
+
+const (
+	// RoundTowardZero drops the fraction, like the *Int methods.
+	RoundTowardZero RoundingMode = iota
+	// RoundFloor rounds toward negative infinity.
+	RoundFloor
+	// RoundCeil rounds toward positive infinity, such as to bill for every
+	// started unit.
+	RoundCeil
+	// RoundHalfUp rounds to the nearest unit, and halves away from zero.
+	RoundHalfUp
+	// RoundHalfEven rounds to the nearest unit, and halves to the even
+	// unit.
+	RoundHalfEven
+)
+
+// In returns fs as a whole number of unit, rounded with mode. unit may be
+// any positive size, such as GB or DecimalGB; In panics if it is not
+// positive. The result is exact: it does not go through a float64.
+//
+//	billedGB := size.In(GB, RoundCeil)
+func (fs ByteSize) In(unit ByteSize, mode RoundingMode) int64 {
+	if unit <= 0 {
+		panic("bytesizer: In with a non-positive unit")
+	}
+	q, r := int64(fs/unit), int64(fs%unit)
+	if r == 0 {
+		return q
+	}
+
+	// r has the sign of fs; away is the next unit away from zero.
+	away := int64(1)
+	abs := r
+	if r < 0 {
+		away, abs = -1, -r
+	}
+	switch mode {
+	case RoundFloor:
+		if r < 0 {
+			return q - 1
+		}
+	case RoundCeil:
+		if r > 0 {
+			return q + 1
+		}
+	case RoundHalfUp:
+		if abs >= int64(unit)-abs {
+			return q + away
+		}
+	case RoundHalfEven:
+		if abs > int64(unit)-abs || abs == int64(unit)-abs && q%2 != 0 {
+			return q + away
+		}
+	}
+	return q
+}
@@ -0,0 +1,55 @@
+package bytesizer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIn(t *testing.T) {
+	tests := []struct {
+		name string
+		size ByteSize
+		unit ByteSize
+		// toward zero, floor, ceil, half up, half even
+		want [5]int64
+	}{
+		{"Whole", 3 * GB, GB, [5]int64{3, 3, 3, 3, 3}},
+		{"Below half", GB + 100*MB, GB, [5]int64{1, 1, 2, 1, 1}},
+		{"Half, odd", GB + GB/2, GB, [5]int64{1, 1, 2, 2, 2}},
+		{"Half, even", 2*GB + GB/2, GB, [5]int64{2, 2, 3, 3, 2}},
+		{"Above half", GB + 900*MB, GB, [5]int64{1, 1, 2, 2, 2}},
+		{"Negative below half", -(GB + 100*MB), GB, [5]int64{-1, -2, -1, -1, -1}},
+		{"Negative half, odd", -(GB + GB/2), GB, [5]int64{-1, -2, -1, -2, -2}},
+		{"Negative half, even", -(2*GB + GB/2), GB, [5]int64{-2, -3, -2, -3, -2}},
+		{"Negative above half", -(GB + 900*MB), GB, [5]int64{-1, -2, -1, -2, -2}},
+		{"Less than a unit", 1, PB, [5]int64{0, 0, 1, 0, 0}},
+		{"Decimal unit", 1500 * DecimalMB, DecimalGB, [5]int64{1, 1, 2, 2, 2}},
+		{"Bytes", 12345, Byte, [5]int64{12345, 12345, 12345, 12345, 12345}},
+		{"Largest", Unlimited, PB, [5]int64{8191, 8191, 8192, 8192, 8192}},
+		{"Smallest", math.MinInt64, PB, [5]int64{-8192, -8192, -8192, -8192, -8192}},
+		{"Largest unit", Unlimited - 1, Unlimited, [5]int64{0, 0, 1, 1, 1}},
+	}
+
+	modes := []RoundingMode{RoundTowardZero, RoundFloor, RoundCeil, RoundHalfUp, RoundHalfEven}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, mode := range modes {
+				assert.Equal(t, tt.want[i], tt.size.In(tt.unit, mode), "mode %d", mode)
+			}
+		})
+	}
+
+	assert.Panics(t, func() { GB.In(0, RoundCeil) })
+}
+
+func TestInMatchesIntMethods(t *testing.T) {
+	for _, size := range []ByteSize{0, 1023, 3*PB + 5*TB, -(2*GB + 1), Unlimited} {
+		assert.Equal(t, int64(size.KBInt()), size.In(KB, RoundTowardZero))
+		assert.Equal(t, int64(size.MBInt()), size.In(MB, RoundTowardZero))
+		assert.Equal(t, int64(size.GBInt()), size.In(GB, RoundTowardZero))
+		assert.Equal(t, int64(size.TBInt()), size.In(TB, RoundTowardZero))
+		assert.Equal(t, int64(size.PBInt()), size.In(PB, RoundTowardZero))
+	}
+}
@@ -0,0 +1,48 @@
+package bytesizer
+
+import (
+	"fmt"
+	"math"
+)
+
+// SavingsReport compares a size before and after compression or
+// deduplication.
+type SavingsReport struct {
+	Original   ByteSize
+	Compressed ByteSize
+	// Ratio is Original divided by Compressed, e.g. 3.6 for 3.6:1, or 0 if
+	// Compressed is 0.
+	Ratio float64
+	// Percent is the share of Original saved, from 0 to 100, or negative if
+	// Compressed is larger. It is 0 if Original is 0.
+	Percent float64
+}
+
+// Savings compares original and compressed sizes, for the summary of a
+// backup or archive tool.
+//
+//	fmt.Println(Savings(7*GB/2, 890*MB)) // 3.5GB → 890MB, 75.17% smaller
+func Savings(original, compressed ByteSize) SavingsReport {
+	r := SavingsReport{Original: original, Compressed: compressed}
+	if compressed != 0 {
+		r.Ratio = float64(original) / float64(compressed)
+	}
+	if original != 0 {
+		r.Percent = (1 - float64(compressed)/float64(original)) * 100
+	}
+	return r
+}
+
+// String returns a summary such as "3.5GB → 890MB, 75.17% smaller", or
+// "0B → 5MB, larger" without a percentage of an empty original.
+func (r SavingsReport) String() string {
+	switch {
+	case r.Original == 0 && r.Compressed > 0:
+		return fmt.Sprintf("%s → %s, larger", r.Original, r.Compressed)
+	case r.Percent > 0:
+		return fmt.Sprintf("%s → %s, %s smaller", r.Original, r.Compressed, formatString(r.Percent, "%", 2))
+	case r.Percent < 0:
+		return fmt.Sprintf("%s → %s, %s larger", r.Original, r.Compressed, formatString(math.Abs(r.Percent), "%", 2))
+	}
+	return fmt.Sprintf("%s → %s, same size", r.Original, r.Compressed)
+}
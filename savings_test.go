@@ -0,0 +1,37 @@
+package bytesizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSavings(t *testing.T) {
+	tests := []struct {
+		name        string
+		original    ByteSize
+		compressed  ByteSize
+		wantRatio   float64
+		wantPercent float64
+		wantString  string
+	}{
+		{"Smaller", 4 * GB, GB, 4, 75, "4GB → 1GB, 75% smaller"},
+		{"Fraction", 7 * GB / 2, 890 * MB, 4.027, 75.17, "3.5GB → 890MB, 75.17% smaller"},
+		{"Larger", 100 * MB, 110 * MB, 0.909, -10, "100MB → 110MB, 10% larger"},
+		{"Same", GB, GB, 1, 0, "1GB → 1GB, same size"},
+		{"Empty result", GB, 0, 0, 100, "1GB → 0B, 100% smaller"},
+		{"Empty input", 0, 0, 0, 0, "0B → 0B, same size"},
+		{"From empty input", 0, 5 * MB, 0, 0, "0B → 5MB, larger"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Savings(tt.original, tt.compressed)
+			assert.Equal(t, tt.original, s.Original)
+			assert.Equal(t, tt.compressed, s.Compressed)
+			assert.InDelta(t, tt.wantRatio, s.Ratio, 1e-3)
+			assert.InDelta(t, tt.wantPercent, s.Percent, 1e-2)
+			assert.Equal(t, tt.wantString, s.String())
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package bytesizer
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// Scanner returns a fmt.Scanner that parses a size into p, so sizes can be
+// read with fmt.Sscan, fmt.Sscanf and friends using the %v, %s or %d verbs:
+//
+//	var limit bytesizer.ByteSize
+//	fmt.Sscanf("limit=10MB", "limit=%v", bytesizer.Scanner(&limit))
+//
+// *ByteSize cannot implement fmt.Scanner itself because its Scan method
+// implements sql.Scanner, hence the adapter.
+//
+// The token consists of digits, letters, '.', '+' and '-', and is parsed with Parse.
+func Scanner(p *ByteSize) fmt.Scanner {
+	return (*fmtScanner)(p)
+}
+
+// fmtScanner implements fmt.Scanner for a *ByteSize.
+type fmtScanner ByteSize
+
+func (s *fmtScanner) Scan(state fmt.ScanState, verb rune) error {
+	switch verb {
+	case 'v', 's', 'd':
+	default:
+		return fmt.Errorf("bytesizer: invalid verb %%%c for ByteSize", verb)
+	}
+
+	token, err := state.Token(true, func(r rune) bool {
+		return unicode.IsDigit(r) || unicode.IsLetter(r) || r == '.' || r == '+' || r == '-'
+	})
+	if err != nil {
+		return err
+	}
+	return (*ByteSize)(s).UnmarshalText(token)
+}
@@ -0,0 +1,41 @@
+package bytesizer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanner(t *testing.T) {
+	var limit, used ByteSize
+	n, err := fmt.Sscanf("limit=10MB used=1.5KB", "limit=%v used=%v", Scanner(&limit), Scanner(&used))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, 10*MB, limit)
+	assert.Equal(t, ByteSize(1536), used)
+
+	var a, b ByteSize
+	n, err = fmt.Sscan("  2048\n 4KB", Scanner(&a), Scanner(&b))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, 2*KB, a)
+	assert.Equal(t, 4*KB, b)
+
+	var c ByteSize
+	_, err = fmt.Sscanf("size:64MB,", "size:%s,", Scanner(&c))
+	assert.NoError(t, err)
+	assert.Equal(t, 64*MB, c)
+}
+
+func TestScannerErrors(t *testing.T) {
+	var size ByteSize
+	_, err := fmt.Sscanf("limit=lots", "limit=%v", Scanner(&size))
+	assert.Error(t, err)
+
+	_, err = fmt.Sscanf("10MB", "%x", Scanner(&size))
+	assert.Error(t, err)
+
+	_, err = fmt.Sscan("", Scanner(&size))
+	assert.Error(t, err)
+}
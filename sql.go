@@ -0,0 +1,64 @@
+package bytesizer
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Value implements driver.Valuer, storing the ByteSize as an int64 number of bytes.
+func (fs ByteSize) Value() (driver.Value, error) {
+	return int64(fs), nil
+}
+
+// Scan implements sql.Scanner. It accepts int64 and float64 values as a
+// number of bytes, and string or []byte values in any format accepted by Parse.
+func (fs *ByteSize) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case int64:
+		*fs = ByteSize(v)
+	case float64:
+		size, err := FromFloat(v)
+		if err != nil {
+			return fmt.Errorf("cannot scan into ByteSize: %w", err)
+		}
+		*fs = size
+	case string:
+		return fs.UnmarshalText([]byte(v))
+	case []byte:
+		return fs.UnmarshalText(v)
+	case nil:
+		return fmt.Errorf("cannot scan NULL into ByteSize, use NullByteSize")
+	default:
+		return fmt.Errorf("cannot scan %T into ByteSize", src)
+	}
+	return nil
+}
+
+// NullByteSize represents a ByteSize that may be null.
+// It implements sql.Scanner and driver.Valuer, like sql.NullInt64.
+type NullByteSize struct {
+	ByteSize ByteSize
+	Valid    bool // Valid is true if ByteSize is not NULL
+}
+
+// Scan implements sql.Scanner.
+func (n *NullByteSize) Scan(src interface{}) error {
+	if src == nil {
+		n.ByteSize, n.Valid = 0, false
+		return nil
+	}
+	if err := n.ByteSize.Scan(src); err != nil {
+		n.Valid = false
+		return err
+	}
+	n.Valid = true
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (n NullByteSize) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return int64(n.ByteSize), nil
+}
@@ -0,0 +1,76 @@
+package bytesizer
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ driver.Valuer = ByteSize(0)
+	_ sql.Scanner   = (*ByteSize)(nil)
+	_ driver.Valuer = NullByteSize{}
+	_ sql.Scanner   = (*NullByteSize)(nil)
+)
+
+func TestByteSizeValue(t *testing.T) {
+	v, err := (10 * MB).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10*MB), v)
+}
+
+func TestByteSizeScan(t *testing.T) {
+	tests := []struct {
+		name      string
+		src       interface{}
+		expectErr bool
+		expected  ByteSize
+	}{
+		{"int64", int64(2048), false, 2 * KB},
+		{"float64", float64(1536), false, 1536},
+		{"string", "1.5KB", false, 1536},
+		{"bytes", []byte("10MB"), false, 10 * MB},
+		{"bytes without unit", []byte("4096"), false, 4 * KB},
+		{"invalid string", "ten", true, 0},
+		{"NaN", math.NaN(), true, 0},
+		{"float64 out of range", float64(1e30), true, 0},
+		{"nil", nil, true, 0},
+		{"bool", true, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var size ByteSize
+			err := size.Scan(tt.src)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, size)
+			}
+		})
+	}
+}
+
+func TestNullByteSize(t *testing.T) {
+	var n NullByteSize
+	assert.NoError(t, n.Scan(int64(1024)))
+	assert.Equal(t, NullByteSize{ByteSize: KB, Valid: true}, n)
+
+	v, err := n.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1024), v)
+
+	assert.NoError(t, n.Scan(nil))
+	assert.Equal(t, NullByteSize{}, n)
+
+	v, err = n.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+
+	assert.Error(t, n.Scan("ten"))
+	assert.False(t, n.Valid)
+}
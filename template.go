@@ -0,0 +1,89 @@
+package bytesizer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// TemplateFuncs returns functions for text/template and html/template, to
+// format sizes in reports and generated configuration:
+//
+//	bytesize    formats a size with an appropriate unit: {{bytesize .Size}}
+//	formatSize  formats a size in a unit: {{formatSize .Size "MB"}}
+//	parseSize   parses a size: {{parseSize "1.5GB"}}
+//	rate        formats a rate in bytes per second: {{rate .BytesPerSecond}}
+//
+// Sizes and rates may be given as any integer or float, or as a string
+// accepted by Parse or ParseRate.
+//
+//	t := template.Must(template.New("report").Funcs(bytesizer.TemplateFuncs()).Parse(text))
+func TemplateFuncs() map[string]any {
+	return map[string]any{
+		"bytesize": func(v any) (string, error) {
+			size, err := templateSize(v)
+			if err != nil {
+				return "", err
+			}
+			return size.String(), nil
+		},
+		"formatSize": func(v any, unit string) (string, error) {
+			size, err := templateSize(v)
+			if err != nil {
+				return "", err
+			}
+			u, ok := lookupUnitName(unit)
+			if !ok {
+				return "", fmt.Errorf("unknown unit %q", unit)
+			}
+			return size.Format(u), nil
+		},
+		"parseSize": Parse,
+		"rate": func(v any) (string, error) {
+			r, err := templateRate(v)
+			if err != nil {
+				return "", err
+			}
+			return r.String(), nil
+		},
+	}
+}
+
+// templateSize converts a template argument to a ByteSize.
+func templateSize(v any) (ByteSize, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return ByteSize(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return ByteSize(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return FromFloat(rv.Float())
+	case reflect.String:
+		return Parse(rv.String())
+	}
+	return 0, fmt.Errorf("cannot use %T as a size", v)
+}
+
+// templateRate converts a template argument to a Rate.
+func templateRate(v any) (Rate, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return Rate(rv.Float()), nil
+	case reflect.String:
+		return ParseRate(rv.String())
+	}
+	size, err := templateSize(v)
+	return Rate(size), err
+}
+
+// lookupUnitName returns the unit named name, such as "MB" or "mb".
+func lookupUnitName(name string) (ByteSize, bool) {
+	for _, u := range units {
+		if strings.EqualFold(u.unitName, name) {
+			return u.size, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,61 @@
+package bytesizer
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	data := map[string]any{
+		"Size":  3 * MB / 2,
+		"Int64": int64(2048),
+		"Uint":  uint32(512),
+		"Float": 1536.0,
+		"Text":  "2GB",
+		"Rate":  10 * MBps,
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		want    string
+		wantErr string
+	}{
+		{name: "ByteSize", text: "{{bytesize .Size}}", want: "1.5MB"},
+		{name: "Int64", text: "{{bytesize .Int64}}", want: "2KB"},
+		{name: "Uint", text: "{{bytesize .Uint}}", want: "512B"},
+		{name: "Float", text: "{{bytesize .Float}}", want: "1.5KB"},
+		{name: "String", text: "{{bytesize .Text}}", want: "2GB"},
+		{name: "Format", text: `{{formatSize .Size "KB"}} {{formatSize .Text "mb"}}`, want: "1536KB 2048MB"},
+		{name: "Parse", text: `{{(parseSize "1.5KB").KBInt}}`, want: "1"},
+		{name: "Rate", text: `{{rate .Rate}} {{rate 1024}} {{rate "1MB/min"}}`, want: "10MB/s 1KB/s 17.07KB/s"},
+		{name: "Unknown unit", text: `{{formatSize .Size "XB"}}`, wantErr: `unknown unit "XB"`},
+		{name: "Bad size", text: `{{bytesize "ten"}}`, wantErr: "bytesize"},
+		{name: "Bad type", text: `{{bytesize .}}`, wantErr: "cannot use map[string]interface {} as a size"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New("test").Funcs(TemplateFuncs()).Parse(tt.text))
+			var b strings.Builder
+			err := tmpl.Execute(&b, data)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, b.String())
+		})
+	}
+}
+
+func TestTemplateFuncsHTML(t *testing.T) {
+	tmpl := htmltemplate.Must(htmltemplate.New("test").Funcs(TemplateFuncs()).Parse("<td>{{bytesize .}}</td>"))
+	var b strings.Builder
+	assert.NoError(t, tmpl.Execute(&b, 5*GB))
+	assert.Equal(t, "<td>5GB</td>", b.String())
+}
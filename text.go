@@ -0,0 +1,52 @@
+package bytesizer
+
+import (
+	"errors"
+	"strconv"
+)
+
+var errUnknownText = errors.New("cannot marshal Unknown as text")
+
+// AppendText implements encoding.TextAppender (Go 1.24), appending the
+// text form of the ByteSize to b. See MarshalText for the format.
+func (fs ByteSize) AppendText(b []byte) ([]byte, error) {
+	if fs == Unknown {
+		return b, errUnknownText
+	}
+	n := uint64(fs)
+	if fs < 0 {
+		n = -n
+	}
+	// String uses the largest unit not above n. Its two decimals are exact
+	// under the same conditions as in appendExact, which then picks that
+	// unit and writes the same text.
+	i := len(units) - 1
+	for i > 0 && n < uint64(units[i].size) {
+		i--
+	}
+	u := uint64(units[i].size)
+	if q, r := n/u, n%u; r*100%u != 0 || r != 0 && q >= 1<<50 {
+		return append(strconv.AppendInt(b, int64(fs), 10), 'B'), nil
+	}
+	return fs.appendExact(b), nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+// It returns the humanized form produced by String when that form is exact,
+// and the exact number of bytes (e.g. "1049600B") otherwise, so that
+// encoding never loses precision. Unlimited is its number of bytes, and
+// Unknown, which has no number, is an error.
+func (fs ByteSize) MarshalText() ([]byte, error) {
+	return fs.AppendText(nil)
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+// It accepts any format accepted by Parse.
+func (fs *ByteSize) UnmarshalText(text []byte) error {
+	v, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*fs = v
+	return nil
+}
@@ -0,0 +1,69 @@
+package bytesizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByteSizeMarshalText(t *testing.T) {
+	tests := []struct {
+		name     string
+		size     ByteSize
+		expected string
+	}{
+		{"Bytes", 532, "532B"},
+		{"Kilobytes", KB, "1KB"},
+		{"Half megabyte", MB / 2, "512KB"},
+		{"Decimal", 1536 * KB, "1.5MB"},
+		{"Lossy humanized form", 1025 * KB, "1049600B"},
+		{"Zero", 0, "0B"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := tt.size.MarshalText()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(b))
+
+			var got ByteSize
+			assert.NoError(t, got.UnmarshalText(b))
+			assert.Equal(t, tt.size, got)
+		})
+	}
+}
+
+// textAppender mirrors encoding.TextAppender, added in Go 1.24.
+type textAppender interface {
+	AppendText(b []byte) ([]byte, error)
+}
+
+var _ textAppender = ByteSize(0)
+
+func TestByteSizeAppendText(t *testing.T) {
+	buf := []byte("size=")
+	buf, err := (1536 * KB).AppendText(buf)
+	assert.NoError(t, err)
+	buf = append(buf, ' ')
+	buf, err = (1025 * KB).AppendText(buf)
+	assert.NoError(t, err)
+	buf = append(buf, ' ')
+	buf, err = ByteSize(6819).AppendText(buf)
+	assert.NoError(t, err)
+	buf = append(buf, ' ')
+	buf, err = Unlimited.AppendText(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "size=1.5MB 1049600B 6819B 9223372036854775807B", string(buf))
+
+	_, err = Unknown.AppendText(buf)
+	assert.Error(t, err)
+}
+
+func TestByteSizeUnmarshalText(t *testing.T) {
+	var size ByteSize
+	assert.NoError(t, size.UnmarshalText([]byte("10MB")))
+	assert.Equal(t, 10*MB, size)
+
+	assert.Error(t, size.UnmarshalText([]byte("10XB")))
+	assert.Equal(t, 10*MB, size, "value must be unchanged on error")
+}
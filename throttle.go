@@ -0,0 +1,93 @@
+package bytesizer
+
+import (
+	"context"
+	"io"
+)
+
+// throttleBurst returns the bucket size for rate: a tenth of a second worth
+// of data, so that transfers are paced smoothly.
+func throttleBurst(rate Rate) ByteSize {
+	if burst := ByteSize(rate / 10); burst > 1 {
+		return burst
+	}
+	return 1
+}
+
+type throttledReader struct {
+	ctx     context.Context
+	r       io.Reader
+	limiter *Limiter
+}
+
+// ThrottleReader returns a reader that reads from r at no more than rate.
+// A rate of zero or less means no limit, and r is returned as is.
+func ThrottleReader(r io.Reader, rate Rate) io.Reader {
+	return ThrottleReaderContext(context.Background(), r, rate)
+}
+
+// ThrottleReaderContext is like ThrottleReader, but a Read waiting for its
+// turn returns early with ctx.Err() when ctx is done.
+func ThrottleReaderContext(ctx context.Context, r io.Reader, rate Rate) io.Reader {
+	if rate <= 0 {
+		return r
+	}
+	return &throttledReader{ctx: ctx, r: r, limiter: NewLimiter(rate, throttleBurst(rate))}
+}
+
+func (t *throttledReader) Read(p []byte) (int, error) {
+	if err := t.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if max := int(t.limiter.Burst()); len(p) > max {
+		p = p[:max]
+	}
+	n, err := t.r.Read(p)
+	if n > 0 {
+		if werr := t.limiter.wait(t.ctx, n); werr != nil && err == nil {
+			err = werr
+		}
+	}
+	return n, err
+}
+
+type throttledWriter struct {
+	ctx     context.Context
+	w       io.Writer
+	limiter *Limiter
+}
+
+// ThrottleWriter returns a writer that writes to w at no more than rate.
+// A rate of zero or less means no limit, and w is returned as is.
+func ThrottleWriter(w io.Writer, rate Rate) io.Writer {
+	return ThrottleWriterContext(context.Background(), w, rate)
+}
+
+// ThrottleWriterContext is like ThrottleWriter, but a Write waiting for its
+// turn returns early with ctx.Err() when ctx is done.
+func ThrottleWriterContext(ctx context.Context, w io.Writer, rate Rate) io.Writer {
+	if rate <= 0 {
+		return w
+	}
+	return &throttledWriter{ctx: ctx, w: w, limiter: NewLimiter(rate, throttleBurst(rate))}
+}
+
+func (t *throttledWriter) Write(p []byte) (int, error) {
+	var written int
+	for len(p) > 0 {
+		chunk := p
+		if max := int(t.limiter.Burst()); len(chunk) > max {
+			chunk = chunk[:max]
+		}
+		if err := t.limiter.wait(t.ctx, len(chunk)); err != nil {
+			return written, err
+		}
+		n, err := t.w.Write(chunk)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
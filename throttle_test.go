@@ -0,0 +1,63 @@
+package bytesizer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThrottleReader(t *testing.T) {
+	data := strings.Repeat("x", int(30*KB))
+	start := time.Now()
+	b, err := io.ReadAll(ThrottleReader(strings.NewReader(data), 100*KBps))
+	elapsed := time.Since(start)
+
+	assert.NoError(t, err)
+	assert.Equal(t, data, string(b))
+	// 30KB at 100KB/s, less the 10KB initial burst.
+	assert.GreaterOrEqual(t, elapsed, 150*time.Millisecond)
+	assert.Less(t, elapsed, 2*time.Second)
+}
+
+func TestThrottleWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := ThrottleWriter(&buf, 100*KBps)
+	start := time.Now()
+	n, err := w.Write(make([]byte, 30*KB))
+	elapsed := time.Since(start)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int(30*KB), n)
+	assert.Equal(t, int(30*KB), buf.Len())
+	assert.GreaterOrEqual(t, elapsed, 150*time.Millisecond)
+	assert.Less(t, elapsed, 2*time.Second)
+}
+
+func TestThrottleUnlimited(t *testing.T) {
+	r := strings.NewReader("Hello")
+	assert.Same(t, r, ThrottleReader(r, 0))
+
+	var buf bytes.Buffer
+	assert.Same(t, &buf, ThrottleWriter(&buf, -1))
+}
+
+func TestThrottleContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var buf bytes.Buffer
+	w := ThrottleWriterContext(ctx, &buf, KBps)
+	n, err := w.Write(make([]byte, 10*KB))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, n, int(10*KB))
+
+	r := ThrottleReaderContext(ctx, strings.NewReader(strings.Repeat("x", int(10*KB))), KBps)
+	_, err = io.ReadAll(r)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
@@ -0,0 +1,58 @@
+package bytesizer
+
+import (
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	gotoml "github.com/pelletier/go-toml/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type tomlConfig struct {
+	MaxBodySize ByteSize `toml:"max_body_size"`
+	CacheSize   ByteSize `toml:"cache_size"`
+	BufferSize  ByteSize `toml:"buffer_size"`
+}
+
+const tomlInput = `
+max_body_size = "10MB"
+cache_size = 2048
+buffer_size = "1.5KB"
+`
+
+var tomlExpected = tomlConfig{MaxBodySize: 10 * MB, CacheSize: 2 * KB, BufferSize: 1536}
+
+func TestByteSizeBurntSushiTOML(t *testing.T) {
+	var c tomlConfig
+	_, err := toml.Decode(tomlInput, &c)
+	assert.NoError(t, err)
+	assert.Equal(t, tomlExpected, c)
+
+	out, err := toml.Marshal(c)
+	assert.NoError(t, err)
+	assert.Equal(t, "max_body_size = \"10MB\"\ncache_size = \"2KB\"\nbuffer_size = \"1.5KB\"\n", string(out))
+
+	var back tomlConfig
+	_, err = toml.Decode(string(out), &back)
+	assert.NoError(t, err)
+	assert.Equal(t, c, back)
+
+	_, err = toml.Decode(`max_body_size = "10XB"`, &c)
+	assert.Error(t, err)
+}
+
+func TestByteSizeGoTOML(t *testing.T) {
+	var c tomlConfig
+	assert.NoError(t, gotoml.Unmarshal([]byte(tomlInput), &c))
+	assert.Equal(t, tomlExpected, c)
+
+	out, err := gotoml.Marshal(c)
+	assert.NoError(t, err)
+	assert.Equal(t, "max_body_size = '10MB'\ncache_size = '2KB'\nbuffer_size = '1.5KB'\n", string(out))
+
+	var back tomlConfig
+	assert.NoError(t, gotoml.Unmarshal(out, &back))
+	assert.Equal(t, c, back)
+
+	assert.Error(t, gotoml.Unmarshal([]byte(`max_body_size = "10XB"`), &c))
+}
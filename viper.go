@@ -0,0 +1,24 @@
+package bytesizer
+
+// ViperGetter is the subset of *viper.Viper used by GetViper.
+// Declaring it here keeps this package free of a viper dependency.
+type ViperGetter interface {
+	IsSet(key string) bool
+	Get(key string) interface{}
+}
+
+// GetViper returns the ByteSize stored under key, which may hold a size
+// string like "512MB" or a number of bytes. It returns def when the key
+// is not set or its value cannot be converted, mirroring viper's own getters.
+//
+//	cacheSize := bytesizer.GetViper(viper.GetViper(), "cache_size", 256*bytesizer.MB)
+func GetViper(v ViperGetter, key string, def ByteSize) ByteSize {
+	if !v.IsSet(key) {
+		return def
+	}
+	size, err := toByteSize(v.Get(key))
+	if err != nil {
+		return def
+	}
+	return size
+}
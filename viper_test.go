@@ -0,0 +1,84 @@
+package bytesizer_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/iamlongalong/bytesizer"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+const viperConfig = `
+cache_size: 512MB
+buffer: 4096
+invalid: lots
+`
+
+func newViper(t *testing.T) *viper.Viper {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	assert.NoError(t, v.ReadConfig(strings.NewReader(viperConfig)))
+	return v
+}
+
+func TestGetViper(t *testing.T) {
+	v := newViper(t)
+	v.SetDefault("fallback", "1KB")
+
+	tests := []struct {
+		key      string
+		expected bytesizer.ByteSize
+	}{
+		{"cache_size", 512 * bytesizer.MB},
+		{"buffer", 4 * bytesizer.KB},
+		{"fallback", bytesizer.KB},
+		{"missing", 7},
+		{"invalid", 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			assert.Equal(t, tt.expected, bytesizer.GetViper(v, tt.key, 7))
+		})
+	}
+}
+
+func TestViperUnmarshalWithDecodeHook(t *testing.T) {
+	var cfg struct {
+		CacheSize bytesizer.ByteSize `mapstructure:"cache_size"`
+		Buffer    bytesizer.ByteSize `mapstructure:"buffer"`
+	}
+	v := newViper(t)
+	assert.NoError(t, v.Unmarshal(&cfg, viper.DecodeHook(bytesizer.DecodeHook())))
+	assert.Equal(t, 512*bytesizer.MB, cfg.CacheSize)
+	assert.Equal(t, 4*bytesizer.KB, cfg.Buffer)
+}
+
+// Wiring DecodeHook into viper takes a single option on Unmarshal.
+func ExampleDecodeHook_viper() {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	_ = v.ReadConfig(strings.NewReader("cache_size: 512MB\n"))
+
+	var cfg struct {
+		CacheSize bytesizer.ByteSize `mapstructure:"cache_size"`
+	}
+	if err := v.Unmarshal(&cfg, viper.DecodeHook(bytesizer.DecodeHook())); err != nil {
+		panic(err)
+	}
+	fmt.Println(cfg.CacheSize, int(cfg.CacheSize))
+	// Output: 512MB 536870912
+}
+
+func ExampleGetViper() {
+	v := viper.New()
+	v.Set("cache_size", "1.5GB")
+
+	fmt.Println(bytesizer.GetViper(v, "cache_size", 256*bytesizer.MB))
+	fmt.Println(bytesizer.GetViper(v, "buffer_size", 256*bytesizer.MB))
+	// Output:
+	// 1.5GB
+	// 256MB
+}
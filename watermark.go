@@ -0,0 +1,112 @@
+package bytesizer
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WatermarkEvent reports that a Watermark crossed one of its marks.
+type WatermarkEvent struct {
+	// High is true when the value reached the high mark, and false when it
+	// fell back to the low mark.
+	High bool
+	// Mark is the mark crossed.
+	Mark ByteSize
+	// Value is the value that crossed it.
+	Value ByteSize
+}
+
+// Watermark fires events when a value, such as a cache or disk usage,
+// reaches a high mark, and again only once it has fallen back to a low
+// mark. The gap between the marks keeps a value hovering around one of
+// them from firing repeatedly. It is safe for concurrent use.
+//
+//	w, _ := NewWatermark(9*GB, 7*GB, func(e WatermarkEvent) {
+//		if e.High {
+//			startEviction()
+//		} else {
+//			stopEviction()
+//		}
+//	})
+//	go w.Watch(ctx, 10*time.Second, cache.Size)
+type Watermark struct {
+	high, low ByteSize
+	fn        func(WatermarkEvent)
+
+	mu    sync.Mutex
+	above bool
+}
+
+// NewWatermark returns a Watermark calling fn when a value reaches high,
+// and when it falls back to low. low must not be greater than high.
+func NewWatermark(high, low ByteSize, fn func(WatermarkEvent)) (*Watermark, error) {
+	if low > high {
+		return nil, fmt.Errorf("low watermark %s is above high watermark %s", low, high)
+	}
+	return &Watermark{high: high, low: low, fn: fn}, nil
+}
+
+// NewWatermarkPercent returns a Watermark with marks at percentages of
+// capacity, e.g. 90 and 75 percent of a disk's total size.
+func NewWatermarkPercent(capacity ByteSize, highPercent, lowPercent float64, fn func(WatermarkEvent)) (*Watermark, error) {
+	high, err := capacity.MulFloat(highPercent / 100)
+	if err != nil {
+		return nil, fmt.Errorf("high watermark: %w", err)
+	}
+	low, err := capacity.MulFloat(lowPercent / 100)
+	if err != nil {
+		return nil, fmt.Errorf("low watermark: %w", err)
+	}
+	return NewWatermark(high, low, fn)
+}
+
+// Update reports the current value, calling the event function if it
+// crossed a mark. Calls are made from the goroutine calling Update.
+func (w *Watermark) Update(v ByteSize) {
+	w.mu.Lock()
+	var e *WatermarkEvent
+	switch {
+	case !w.above && v >= w.high:
+		w.above = true
+		e = &WatermarkEvent{High: true, Mark: w.high, Value: v}
+	case w.above && v <= w.low:
+		w.above = false
+		e = &WatermarkEvent{Mark: w.low, Value: v}
+	}
+	w.mu.Unlock()
+
+	if e != nil && w.fn != nil {
+		w.fn(*e)
+	}
+}
+
+// High reports whether the value reached the high mark and has not fallen
+// back to the low mark since.
+func (w *Watermark) High() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.above
+}
+
+// Watch calls sample every interval and updates the watermark with the
+// result, until ctx is done or sample fails. It returns ctx.Err() or the
+// error from sample.
+func (w *Watermark) Watch(ctx context.Context, interval time.Duration, sample func() (ByteSize, error)) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		v, err := sample()
+		if err != nil {
+			return err
+		}
+		w.Update(v)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
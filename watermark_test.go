@@ -0,0 +1,75 @@
+package bytesizer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatermark(t *testing.T) {
+	var events []WatermarkEvent
+	w, err := NewWatermark(9*GB, 7*GB, func(e WatermarkEvent) {
+		events = append(events, e)
+	})
+	assert.NoError(t, err)
+
+	for _, v := range []ByteSize{5 * GB, 8 * GB, 9 * GB, 10 * GB, 8 * GB, 9 * GB, 7 * GB, 6 * GB, 9 * GB} {
+		w.Update(v)
+	}
+	assert.Equal(t, []WatermarkEvent{
+		{High: true, Mark: 9 * GB, Value: 9 * GB},
+		{Mark: 7 * GB, Value: 7 * GB},
+		{High: true, Mark: 9 * GB, Value: 9 * GB},
+	}, events)
+	assert.True(t, w.High())
+
+	_, err = NewWatermark(GB, 2*GB, nil)
+	assert.Error(t, err)
+}
+
+func TestWatermarkPercent(t *testing.T) {
+	var events []WatermarkEvent
+	w, err := NewWatermarkPercent(100*GB, 90, 75, func(e WatermarkEvent) {
+		events = append(events, e)
+	})
+	assert.NoError(t, err)
+
+	w.Update(89 * GB)
+	w.Update(95 * GB)
+	w.Update(80 * GB)
+	w.Update(70 * GB)
+	assert.Equal(t, []WatermarkEvent{
+		{High: true, Mark: 90 * GB, Value: 95 * GB},
+		{Mark: 75 * GB, Value: 70 * GB},
+	}, events)
+}
+
+func TestWatermarkWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	values := []ByteSize{GB, 10 * GB}
+	i := 0
+	w, err := NewWatermark(5*GB, 2*GB, func(e WatermarkEvent) {
+		assert.True(t, e.High)
+		cancel()
+	})
+	assert.NoError(t, err)
+
+	err = w.Watch(ctx, time.Millisecond, func() (ByteSize, error) {
+		v := values[i]
+		i++
+		return v, nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, i)
+
+	sampleErr := errors.New("disk gone")
+	err = w.Watch(context.Background(), time.Millisecond, func() (ByteSize, error) {
+		return 0, sampleErr
+	})
+	assert.ErrorIs(t, err, sampleErr)
+}
@@ -0,0 +1,101 @@
+package bytesizer
+
+import (
+	"sync"
+	"time"
+)
+
+// WindowCounter counts bytes over a sliding time window, such as the last
+// minute, in a ring of buckets. Memory and time per call depend only on the
+// number of buckets. It is safe for concurrent use.
+//
+//	c := NewWindowCounter(time.Minute, time.Second)
+//	c.Add(ByteSize(n))
+//	...
+//	if c.Sum(10*time.Second) > 100*MB {
+//		return errTooMuch
+//	}
+type WindowCounter struct {
+	res time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	buckets []int64
+	latest  int64 // number of the newest bucket, in resolutions since the epoch
+}
+
+// NewWindowCounter returns a counter over window, in buckets of resolution.
+// Sums are exact to a resolution: a shorter one is more precise, a longer
+// one uses fewer buckets.
+func NewWindowCounter(window, resolution time.Duration) *WindowCounter {
+	if resolution <= 0 {
+		resolution = time.Second
+	}
+	n := int((window + resolution - 1) / resolution)
+	if n < 1 {
+		n = 1
+	}
+	return &WindowCounter{res: resolution, now: time.Now, buckets: make([]int64, n)}
+}
+
+// Window returns the time the counter covers.
+func (c *WindowCounter) Window() time.Duration {
+	return time.Duration(len(c.buckets)) * c.res
+}
+
+// Add records n bytes now.
+func (c *WindowCounter) Add(n ByteSize) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b := c.advance()
+	c.buckets[b%int64(len(c.buckets))] += int64(n)
+}
+
+// Sum returns the bytes recorded in the last d, including the current
+// bucket. d is rounded up to the resolution and capped at the window.
+func (c *WindowCounter) Sum(d time.Duration) ByteSize {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b := c.advance()
+
+	k := int64((d + c.res - 1) / c.res)
+	if n := int64(len(c.buckets)); k > n {
+		k = n
+	}
+	var sum int64
+	for i := int64(0); i < k; i++ {
+		sum += c.buckets[(b-i)%int64(len(c.buckets))]
+	}
+	return ByteSize(sum)
+}
+
+// Total returns the bytes recorded over the whole window.
+func (c *WindowCounter) Total() ByteSize {
+	return c.Sum(c.Window())
+}
+
+// Rate returns the average rate over the last d.
+func (c *WindowCounter) Rate(d time.Duration) Rate {
+	if w := c.Window(); d > w {
+		d = w
+	}
+	return RateFor(c.Sum(d), d)
+}
+
+// advance moves the ring to the current bucket, clearing the buckets that
+// fell out of the window, and returns its number. Call with c.mu held.
+func (c *WindowCounter) advance() int64 {
+	b := c.now().UnixNano() / int64(c.res)
+	n := int64(len(c.buckets))
+	if b > c.latest {
+		from := c.latest + 1
+		if b-from >= n {
+			from = b - n + 1
+		}
+		for i := from; i <= b; i++ {
+			c.buckets[i%n] = 0
+		}
+		c.latest = b
+	}
+	return c.latest
+}
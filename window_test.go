@@ -0,0 +1,47 @@
+package bytesizer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowCounter(t *testing.T) {
+	now := time.Unix(1000, 0)
+	c := NewWindowCounter(10*time.Second, time.Second)
+	c.now = func() time.Time { return now }
+	assert.Equal(t, 10*time.Second, c.Window())
+
+	c.Add(KB)
+	now = now.Add(500 * time.Millisecond)
+	c.Add(KB)
+	assert.Equal(t, 2*KB, c.Sum(time.Second))
+
+	now = now.Add(2 * time.Second)
+	c.Add(4 * KB)
+	assert.Equal(t, 4*KB, c.Sum(time.Second))
+	assert.Equal(t, 4*KB, c.Sum(2*time.Second))
+	assert.Equal(t, 6*KB, c.Sum(3*time.Second))
+	assert.Equal(t, 6*KB, c.Total())
+	assert.Equal(t, 6*KB, c.Sum(time.Hour))
+	assert.Equal(t, Rate(614.4), c.Rate(10*time.Second))
+	assert.Equal(t, Rate(614.4), c.Rate(time.Hour))
+
+	// The first bucket slides out of the window.
+	now = now.Add(8 * time.Second)
+	assert.Equal(t, 4*KB, c.Total())
+
+	// Everything slides out after a long pause.
+	now = now.Add(time.Hour)
+	assert.Equal(t, ByteSize(0), c.Total())
+	c.Add(KB)
+	assert.Equal(t, KB, c.Total())
+}
+
+func TestWindowCounterDefaults(t *testing.T) {
+	c := NewWindowCounter(0, 0)
+	assert.Equal(t, time.Second, c.Window())
+	c.Add(MB)
+	assert.Equal(t, MB, c.Total())
+}
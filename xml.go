@@ -0,0 +1,35 @@
+package bytesizer
+
+import "encoding/xml"
+
+// MarshalXML encodes the ByteSize as element content in its text form, e.g. <maxSize>2GB</maxSize>.
+func (fs ByteSize) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	text, err := fs.MarshalText()
+	if err != nil {
+		return err
+	}
+	return e.EncodeElement(string(text), start)
+}
+
+// UnmarshalXML decodes the ByteSize from element content in any format accepted by Parse.
+func (fs *ByteSize) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var s string
+	if err := d.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+	return fs.UnmarshalText([]byte(s))
+}
+
+// MarshalXMLAttr encodes the ByteSize as an attribute in its text form, e.g. maxSize="2GB".
+func (fs ByteSize) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	text, err := fs.MarshalText()
+	if err != nil {
+		return xml.Attr{}, err
+	}
+	return xml.Attr{Name: name, Value: string(text)}, nil
+}
+
+// UnmarshalXMLAttr decodes the ByteSize from an attribute in any format accepted by Parse.
+func (fs *ByteSize) UnmarshalXMLAttr(attr xml.Attr) error {
+	return fs.UnmarshalText([]byte(attr.Value))
+}
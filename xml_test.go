@@ -0,0 +1,55 @@
+package bytesizer
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type xmlConfig struct {
+	XMLName   xml.Name `xml:"storage"`
+	MaxSize   ByteSize `xml:"maxSize,attr"`
+	ChunkSize ByteSize `xml:"chunkSize"`
+}
+
+func TestByteSizeMarshalXML(t *testing.T) {
+	c := xmlConfig{MaxSize: 2 * GB, ChunkSize: 1025 * KB}
+
+	out, err := xml.Marshal(c)
+	assert.NoError(t, err)
+	assert.Equal(t, `<storage maxSize="2GB"><chunkSize>1049600B</chunkSize></storage>`, string(out))
+
+	var back xmlConfig
+	assert.NoError(t, xml.Unmarshal(out, &back))
+	assert.Equal(t, c.MaxSize, back.MaxSize)
+	assert.Equal(t, c.ChunkSize, back.ChunkSize)
+}
+
+func TestByteSizeUnmarshalXML(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expectErr bool
+		expected  xmlConfig
+	}{
+		{"Humanized", `<storage maxSize="2GB"><chunkSize>1.5MB</chunkSize></storage>`, false, xmlConfig{MaxSize: 2 * GB, ChunkSize: 1536 * KB}},
+		{"Bytes", `<storage maxSize="1024"><chunkSize>512</chunkSize></storage>`, false, xmlConfig{MaxSize: KB, ChunkSize: 512}},
+		{"Invalid attribute", `<storage maxSize="2XB"><chunkSize>1MB</chunkSize></storage>`, true, xmlConfig{}},
+		{"Invalid element", `<storage maxSize="2GB"><chunkSize>lots</chunkSize></storage>`, true, xmlConfig{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var c xmlConfig
+			err := xml.Unmarshal([]byte(tt.input), &c)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected.MaxSize, c.MaxSize)
+				assert.Equal(t, tt.expected.ChunkSize, c.ChunkSize)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package bytesizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestByteSizeYAML(t *testing.T) {
+	type config struct {
+		MaxBodySize ByteSize  `yaml:"maxBodySize"`
+		CacheSize   ByteSize  `yaml:"cacheSize"`
+		Optional    *ByteSize `yaml:"optional,omitempty"`
+	}
+
+	var c config
+	err := yaml.Unmarshal([]byte("maxBodySize: 10MB\ncacheSize: 2048\n"), &c)
+	assert.NoError(t, err)
+	assert.Equal(t, 10*MB, c.MaxBodySize)
+	assert.Equal(t, 2*KB, c.CacheSize)
+	assert.Nil(t, c.Optional)
+
+	out, err := yaml.Marshal(c)
+	assert.NoError(t, err)
+	assert.Equal(t, "maxBodySize: 10MB\ncacheSize: 2KB\n", string(out))
+
+	var back config
+	assert.NoError(t, yaml.Unmarshal(out, &back))
+	assert.Equal(t, c, back)
+}
+
+func TestByteSizeYAMLRoundTrip(t *testing.T) {
+	sizes := []ByteSize{0, 1, 532, KB, 1536 * KB, 1025 * KB, 3 * GB, PB}
+
+	for _, size := range sizes {
+		out, err := yaml.Marshal(size)
+		assert.NoError(t, err)
+
+		var got ByteSize
+		assert.NoError(t, yaml.Unmarshal(out, &got))
+		assert.Equal(t, size, got, "round trip of %s", out)
+	}
+}
+
+func TestByteSizeYAMLInvalid(t *testing.T) {
+	var c struct {
+		Size ByteSize `yaml:"size"`
+	}
+	assert.Error(t, yaml.Unmarshal([]byte("size: 10XB\n"), &c))
+	assert.Error(t, yaml.Unmarshal([]byte("size: [1, 2]\n"), &c))
+}